@@ -1,6 +1,6 @@
 BEGIN TRANSACTION;
 
-DROP TABLE IF EXISTS athlete, gym, referee, style, athlete, athlete_record, athlete_gym, athlete_score, bout, outcome, athlete_style, competition, athlete_competition, referee_style, following CASCADE; 
+DROP TABLE IF EXISTS athlete, gym, referee, style, athlete, athlete_record, athlete_gym, athlete_score, bout, outcome, athlete_style, competition, athlete_competition, referee_style, following, style_rating_config, season, season_standing, athlete_overall_rating, overall_rating_config, bout_status_history, notification, bout_proposal, open_challenge, outcome_void, outcome_response, outcome_resolution, idempotency_key, outcome_finish, outcome_round_score CASCADE; 
 
 CREATE TABLE gym (
 	gym_id serial PRIMARY KEY,
@@ -13,6 +13,7 @@ CREATE TABLE gym (
     gym_email varchar(100),
     gym_website varchar(100),
     gym_description varchar(1000) NOT NULL,
+    owner_id int,
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now());
 
@@ -30,7 +31,9 @@ CREATE TABLE style (
 	style_id serial PRIMARY KEY,
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now(),
-    style_name varchar(20) NOT NULL);
+    style_name varchar(20) NOT NULL,
+    challenge_expiry_hours int NOT NULL DEFAULT 168,
+    CONSTRAINT CHK_style_challenge_expiry CHECK (challenge_expiry_hours > 0));
 
 CREATE TABLE athlete (
 	athlete_id serial PRIMARY KEY,
@@ -43,6 +46,8 @@ CREATE TABLE athlete (
     updated_dt timestamp NOT NULL DEFAULT now(),
     email varchar(100) NOT NULL);
 
+ALTER TABLE gym ADD CONSTRAINT FK_owner_id FOREIGN KEY (owner_id) REFERENCES athlete(athlete_id);
+
 CREATE TABLE athlete_record (
     athlete_id int,
     wins int,
@@ -68,16 +73,98 @@ CREATE TABLE bout (
     acceptor_id int NOT NULL,
     referee_id int NOT NULL,
     style_id int NOT NULL,
-    accepted boolean,
-    completed boolean,
-    cancelled boolean,
+    status varchar(20) NOT NULL DEFAULT 'proposed',
+    responder_id int NOT NULL,
     points int,
+    expires_dt timestamp,
+    scheduled_at timestamptz,
+    duration_minutes int NOT NULL DEFAULT 60,
+    gym_id int,
+    rated boolean NOT NULL DEFAULT true,
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now(),
     CONSTRAINT FK_referee_id FOREIGN KEY (referee_id) REFERENCES athlete(athlete_id),
 	CONSTRAINT FK_challenger_id FOREIGN KEY (challenger_id) REFERENCES athlete(athlete_id),
     CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
-    CONSTRAINT FK_acceptor_id FOREIGN KEY (acceptor_id) REFERENCES athlete(athlete_id));
+    CONSTRAINT FK_acceptor_id FOREIGN KEY (acceptor_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    CONSTRAINT FK_responder_id FOREIGN KEY (responder_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT CHK_bout_duration CHECK (duration_minutes > 0),
+    CONSTRAINT CHK_bout_status CHECK (status IN ('proposed', 'accepted', 'declined', 'scheduled', 'in_progress', 'completed', 'disputed', 'cancelled', 'voided', 'expired')));
+
+CREATE TABLE bout_status_history (
+    history_id serial PRIMARY KEY,
+    bout_id int NOT NULL,
+    from_status varchar(20),
+    to_status varchar(20) NOT NULL,
+    actor_id int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_actor_id FOREIGN KEY (actor_id) REFERENCES athlete(athlete_id)
+);
+
+CREATE TABLE bout_proposal (
+    proposal_id serial PRIMARY KEY,
+    bout_id int NOT NULL,
+    round int NOT NULL,
+    proposed_by int NOT NULL,
+    style_id int NOT NULL,
+    referee_id int NOT NULL,
+    scheduled_at timestamptz,
+    duration_minutes int NOT NULL,
+    gym_id int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_proposed_by FOREIGN KEY (proposed_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT FK_referee_id FOREIGN KEY (referee_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    CONSTRAINT unique_bout_proposal_round UNIQUE (bout_id, round)
+);
+
+CREATE TABLE open_challenge (
+    challenge_id serial PRIMARY KEY,
+    challenger_id int NOT NULL,
+    style_id int NOT NULL,
+    referee_id int NOT NULL,
+    min_rating double precision,
+    max_rating double precision,
+    gym_id int,
+    region varchar(2),
+    scheduled_at timestamptz,
+    duration_minutes int NOT NULL DEFAULT 60,
+    points int NOT NULL DEFAULT 0,
+    status varchar(20) NOT NULL DEFAULT 'open',
+    claimed_by int,
+    bout_id int,
+    expires_dt timestamp NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_challenger_id FOREIGN KEY (challenger_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT FK_referee_id FOREIGN KEY (referee_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    CONSTRAINT FK_claimed_by FOREIGN KEY (claimed_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT CHK_open_challenge_status CHECK (status IN ('open', 'claimed', 'cancelled', 'expired')),
+    CONSTRAINT CHK_open_challenge_rating_window CHECK (min_rating IS NULL OR max_rating IS NULL OR min_rating <= max_rating),
+    CONSTRAINT CHK_open_challenge_duration CHECK (duration_minutes > 0)
+);
+
+CREATE TABLE notification (
+    notification_id serial PRIMARY KEY,
+    athlete_id int NOT NULL,
+    bout_id int,
+    kind varchar(30) NOT NULL,
+    message varchar(255) NOT NULL,
+    read_dt timestamp,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id)
+);
 
 CREATE TABLE outcome (
     outcome_id serial PRIMARY KEY,
@@ -86,19 +173,116 @@ CREATE TABLE outcome (
     loser_id int,
     style_id int, 
     is_draw boolean,
+    finish_method varchar(20) NOT NULL DEFAULT 'decision',
+    score_margin int NOT NULL DEFAULT 0,
+    rated boolean NOT NULL DEFAULT true,
+    confirmation_status varchar(20) NOT NULL DEFAULT 'confirmed',
+    confirm_by_dt timestamp,
+    confirmed_dt timestamp,
+    voided_dt timestamp,
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now(),
     CONSTRAINT FK_winner_id FOREIGN KEY (winner_id) REFERENCES athlete(athlete_id),
     CONSTRAINT FK_loser_id FOREIGN KEY (loser_id) REFERENCES athlete(athlete_id),
     CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
-    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id));
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT CHK_finish_method CHECK (finish_method IN ('submission', 'points', 'decision', 'dq', 'forfeit')),
+    CONSTRAINT CHK_outcome_confirmation_status CHECK (confirmation_status IN ('pending', 'confirmed', 'disputed')));
+
+CREATE TABLE outcome_void (
+    void_id serial PRIMARY KEY,
+    outcome_id int NOT NULL UNIQUE,
+    bout_id int,
+    voided_by int NOT NULL,
+    reason varchar(500) NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_voided_by FOREIGN KEY (voided_by) REFERENCES athlete(athlete_id)
+);
+
+CREATE TABLE outcome_finish (
+    outcome_id int PRIMARY KEY,
+    submission_type varchar(50),
+    finish_round int,
+    finish_time_seconds int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT CHK_outcome_finish_round CHECK (finish_round > 0),
+    CONSTRAINT CHK_outcome_finish_time CHECK (finish_time_seconds >= 0)
+);
+
+CREATE TABLE outcome_round_score (
+    round_score_id serial PRIMARY KEY,
+    outcome_id int NOT NULL,
+    round_number int NOT NULL,
+    athlete_id int NOT NULL,
+    points int NOT NULL DEFAULT 0,
+    advantages int NOT NULL DEFAULT 0,
+    penalties int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT UQ_outcome_round_score UNIQUE (outcome_id, round_number, athlete_id),
+    CONSTRAINT CHK_outcome_round_number CHECK (round_number > 0),
+    CONSTRAINT CHK_outcome_round_score CHECK (points >= 0 AND advantages >= 0 AND penalties >= 0)
+);
+
+CREATE TABLE outcome_response (
+    response_id serial PRIMARY KEY,
+    outcome_id int NOT NULL,
+    athlete_id int NOT NULL,
+    response varchar(20) NOT NULL,
+    reason varchar(500),
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT unique_outcome_response UNIQUE (outcome_id, athlete_id),
+    CONSTRAINT CHK_outcome_response CHECK (response IN ('confirm', 'dispute'))
+);
+
+CREATE TABLE outcome_resolution (
+    resolution_id serial PRIMARY KEY,
+    outcome_id int NOT NULL UNIQUE,
+    resolved_by int NOT NULL,
+    action varchar(20) NOT NULL,
+    reason varchar(500) NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_resolved_by FOREIGN KEY (resolved_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT CHK_outcome_resolution_action CHECK (action IN ('uphold', 'overturn', 'void'))
+);
+
+CREATE TABLE idempotency_key (
+    idempotency_key_id serial PRIMARY KEY,
+    idempotency_key varchar(255) NOT NULL,
+    athlete_id int NOT NULL DEFAULT 0,
+    request_method varchar(10) NOT NULL,
+    request_path varchar(255) NOT NULL,
+    request_hash char(64) NOT NULL,
+    status_code int,
+    content_type varchar(100),
+    response_body text,
+    expires_dt timestamp NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT UQ_idempotency_key UNIQUE (athlete_id, idempotency_key)
+);
 
 
 CREATE TABLE athlete_score (
     athlete_id serial,
     style_id int,
     outcome_id int,
-    score int,
+    score double precision,
+    rating_deviation double precision,
+    volatility double precision,
+    provisional boolean NOT NULL DEFAULT false,
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now(),
     CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
@@ -110,8 +294,14 @@ CREATE TABLE athlete_score_history (
     athlete_id int NOT NULL,
     style_id int NOT NULL,
     outcome_id int,
-    previous_score int,
-    new_score int NOT NULL,
+    previous_score double precision,
+    new_score double precision NOT NULL,
+    previous_rating_deviation double precision,
+    new_rating_deviation double precision,
+    previous_volatility double precision,
+    new_volatility double precision,
+    k_factor numeric(6, 2),
+    reason varchar(20),
     created_dt timestamp NOT NULL DEFAULT now(),
     updated_dt timestamp NOT NULL DEFAULT now(),
     CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
@@ -119,6 +309,65 @@ CREATE TABLE athlete_score_history (
     CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id)
 );
 
+CREATE TABLE style_rating_config (
+    style_id int PRIMARY KEY,
+    engine varchar(20) NOT NULL DEFAULT 'elo',
+    settings jsonb NOT NULL DEFAULT '{}',
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id)
+);
+
+CREATE TABLE athlete_overall_rating (
+    athlete_id int PRIMARY KEY,
+    overall_score double precision NOT NULL,
+    styles_rated int NOT NULL DEFAULT 0,
+    rated_bouts int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id)
+);
+
+CREATE TABLE overall_rating_config (
+    config_id int PRIMARY KEY DEFAULT 1,
+    settings jsonb NOT NULL DEFAULT '{}',
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT CHK_single_config CHECK (config_id = 1)
+);
+
+CREATE TABLE season (
+    season_id serial PRIMARY KEY,
+    style_id int NOT NULL,
+    season_name varchar(100) NOT NULL,
+    start_dt date NOT NULL,
+    end_dt date NOT NULL,
+    reset_mean numeric(8, 3),
+    reset_weight numeric(4, 3),
+    closed_dt timestamp,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT CHK_season_dates CHECK (end_dt >= start_dt)
+);
+
+CREATE TABLE season_standing (
+    season_id int NOT NULL,
+    athlete_id int NOT NULL,
+    style_id int NOT NULL,
+    final_rank int NOT NULL,
+    final_score double precision NOT NULL,
+    rating_deviation double precision,
+    volatility double precision,
+    rated_bouts int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT PK_season_standing PRIMARY KEY (season_id, athlete_id),
+    CONSTRAINT FK_season_id FOREIGN KEY (season_id) REFERENCES season(season_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id)
+);
+
 CREATE TABLE athlete_style (
 	athlete_id int,
     style_id int,
@@ -218,11 +467,86 @@ CREATE TRIGGER update_bout_updated_dt
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_dt_column();
 	
+CREATE TRIGGER update_bout_status_history_updated_dt
+    BEFORE UPDATE ON bout_status_history
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_bout_proposal_updated_dt
+    BEFORE UPDATE ON bout_proposal
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_open_challenge_updated_dt
+    BEFORE UPDATE ON open_challenge
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_notification_updated_dt
+    BEFORE UPDATE ON notification
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
 CREATE TRIGGER update_outcome_updated_dt
     BEFORE UPDATE ON outcome
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_void_updated_dt
+    BEFORE UPDATE ON outcome_void
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_finish_updated_dt
+    BEFORE UPDATE ON outcome_finish
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_round_score_updated_dt
+    BEFORE UPDATE ON outcome_round_score
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_response_updated_dt
+    BEFORE UPDATE ON outcome_response
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_resolution_updated_dt
+    BEFORE UPDATE ON outcome_resolution
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_idempotency_key_updated_dt
+    BEFORE UPDATE ON idempotency_key
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+	
+CREATE TRIGGER update_style_rating_config_updated_dt
+    BEFORE UPDATE ON style_rating_config
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
 	
+CREATE TRIGGER update_athlete_overall_rating_updated_dt
+    BEFORE UPDATE ON athlete_overall_rating
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_overall_rating_config_updated_dt
+    BEFORE UPDATE ON overall_rating_config
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_season_updated_dt
+    BEFORE UPDATE ON season
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_season_standing_updated_dt
+    BEFORE UPDATE ON season_standing
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
 CREATE TRIGGER update_athlete_style_updated_dt
     BEFORE UPDATE ON athlete_style
     FOR EACH ROW
@@ -78,26 +78,26 @@ VALUES
 (10, 3, 400); -- Lisa Thomas Boxing
 
 -- Insert historical bouts for John Smith (ID: 1) with dates spread from Jan 1 to Apr 10
-INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, accepted, completed, cancelled, points, created_dt)
+INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, status, responder_id, points, created_dt)
 VALUES 
 -- BJJ matches (showing progression)
-(1, 2, 3, 2, true, true, false, 15, '2024-01-05 19:30:00'),  -- vs John Doe
-(5, 1, 3, 2, true, true, false, 18, '2024-01-15 20:15:00'),  -- vs David Brown
-(1, 8, 3, 2, true, true, false, 20, '2024-01-28 18:45:00'),  -- vs Jessica Taylor
-(2, 1, 5, 2, true, true, false, 22, '2024-02-10 19:00:00'),  -- vs John Doe rematch
-(1, 5, 8, 2, true, true, false, 25, '2024-02-22 20:30:00'),  -- vs David Brown rematch
-(8, 1, 2, 2, true, true, false, 18, '2024-03-05 19:15:00'),  -- vs Jessica Taylor rematch
-(1, 2, 5, 2, true, true, false, 20, '2024-03-18 20:00:00'),  -- vs John Doe final
+(1, 2, 3, 2, 'completed', 2, 15, '2024-01-05 19:30:00'),  -- vs John Doe
+(5, 1, 3, 2, 'completed', 1, 18, '2024-01-15 20:15:00'),  -- vs David Brown
+(1, 8, 3, 2, 'completed', 8, 20, '2024-01-28 18:45:00'),  -- vs Jessica Taylor
+(2, 1, 5, 2, 'completed', 1, 22, '2024-02-10 19:00:00'),  -- vs John Doe rematch
+(1, 5, 8, 2, 'completed', 5, 25, '2024-02-22 20:30:00'),  -- vs David Brown rematch
+(8, 1, 2, 2, 'completed', 1, 18, '2024-03-05 19:15:00'),  -- vs Jessica Taylor rematch
+(1, 2, 5, 2, 'completed', 2, 20, '2024-03-18 20:00:00'),  -- vs John Doe final
 
 -- Muay Thai matches
-(3, 1, 9, 1, true, true, false, 15, '2024-01-08 18:30:00'),  -- vs Mike Johnson
-(1, 6, 3, 1, true, true, false, 18, '2024-01-20 19:45:00'),  -- vs Emily Davis
-(9, 1, 6, 1, true, true, false, 20, '2024-02-03 20:15:00'),  -- vs Michael Anderson
-(1, 3, 9, 1, true, true, false, 22, '2024-02-15 18:30:00'),  -- vs Mike Johnson rematch
-(6, 1, 3, 1, true, true, false, 25, '2024-02-28 19:00:00'),  -- vs Emily Davis rematch
-(1, 9, 6, 1, true, true, false, 18, '2024-03-12 20:45:00'),  -- vs Michael Anderson rematch
-(3, 1, 9, 1, true, true, false, 20, '2024-03-25 19:30:00'),  -- vs Mike Johnson final
-(1, 6, 3, 1, true, true, false, 22, '2024-04-05 18:45:00');  -- vs Emily Davis final
+(3, 1, 9, 1, 'completed', 1, 15, '2024-01-08 18:30:00'),  -- vs Mike Johnson
+(1, 6, 3, 1, 'completed', 6, 18, '2024-01-20 19:45:00'),  -- vs Emily Davis
+(9, 1, 6, 1, 'completed', 1, 20, '2024-02-03 20:15:00'),  -- vs Michael Anderson
+(1, 3, 9, 1, 'completed', 3, 22, '2024-02-15 18:30:00'),  -- vs Mike Johnson rematch
+(6, 1, 3, 1, 'completed', 1, 25, '2024-02-28 19:00:00'),  -- vs Emily Davis rematch
+(1, 9, 6, 1, 'completed', 9, 18, '2024-03-12 20:45:00'),  -- vs Michael Anderson rematch
+(3, 1, 9, 1, 'completed', 1, 20, '2024-03-25 19:30:00'),  -- vs Mike Johnson final
+(1, 6, 3, 1, 'completed', 6, 22, '2024-04-05 18:45:00');  -- vs Emily Davis final
 
 -- Insert outcomes for historical bouts with corresponding dates
 INSERT INTO outcome (bout_id, winner_id, loser_id, style_id, is_draw, created_dt)
@@ -167,28 +167,28 @@ UPDATE athlete_score SET score = 420 WHERE athlete_id = 8 AND style_id = 2;  --
 UPDATE athlete_score SET score = 425 WHERE athlete_id = 9 AND style_id = 1;  -- Michael Anderson Muay Thai
 
 -- Insert pending bouts (John Smith as referee)
-INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, accepted, completed, cancelled, points, created_dt)
+INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, status, responder_id, points, created_dt)
 VALUES 
-(2, 5, 1, 2, false, false, false, 25, '2024-04-08 19:00:00'),  -- John Doe vs David Brown in BJJ
-(3, 6, 1, 1, false, false, false, 20, '2024-04-09 20:00:00'),  -- Mike Johnson vs Emily Davis in Muay Thai
-(8, 5, 1, 2, false, false, false, 30, '2024-04-10 18:30:00');  -- Jessica Taylor vs David Brown in BJJ
+(2, 5, 1, 2, 'proposed', 5, 25, '2024-04-08 19:00:00'),  -- John Doe vs David Brown in BJJ
+(3, 6, 1, 1, 'proposed', 6, 20, '2024-04-09 20:00:00'),  -- Mike Johnson vs Emily Davis in Muay Thai
+(8, 5, 1, 2, 'proposed', 5, 30, '2024-04-10 18:30:00');  -- Jessica Taylor vs David Brown in BJJ
 
 -- Insert incomplete bouts (John Smith as referee)
-INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, accepted, completed, cancelled, points, created_dt)
+INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, status, responder_id, points, created_dt)
 VALUES 
-(2, 1, 3, 2, true, false, false, 25, '2024-04-08 19:30:00'),   -- John Doe challenging John Smith in BJJ
-(6, 1, 9, 1, true, false, false, 20, '2024-04-09 20:30:00'),   -- Emily Davis challenging John Smith in Muay Thai
-(9, 1, 3, 1, false, false, false, 30, '2024-04-10 19:00:00'),  -- Michael Anderson challenging John Smith in Muay Thai
-(3, 8, 1, 2, true, false, false, 25, '2024-04-11 19:00:00');   -- Mike Johnson vs Jessica Taylor in BJJ (accepted, awaiting jsmith's decision)
+(2, 1, 3, 2, 'accepted', 1, 25, '2024-04-08 19:30:00'),   -- John Doe challenging John Smith in BJJ
+(6, 1, 9, 1, 'accepted', 1, 20, '2024-04-09 20:30:00'),   -- Emily Davis challenging John Smith in Muay Thai
+(9, 1, 3, 1, 'proposed', 1, 30, '2024-04-10 19:00:00'),  -- Michael Anderson challenging John Smith in Muay Thai
+(3, 8, 1, 2, 'accepted', 8, 25, '2024-04-11 19:00:00');   -- Mike Johnson vs Jessica Taylor in BJJ (accepted, awaiting jsmith's decision)
 
 -- Insert completed bouts with recent dates
-INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, accepted, completed, cancelled, points, created_dt)
+INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, status, responder_id, points, created_dt)
 VALUES 
-(1, 3, 9, 1, true, true, false, 20, '2024-04-06 20:00:00'), -- John Smith vs Mike Johnson in Muay Thai
-(8, 5, 10, 2, true, true, false, 15, '2024-04-07 19:15:00'), -- Jessica Taylor vs David Brown in BJJ
-(4, 7, 2, 3, true, true, false, 25, '2024-04-08 20:30:00'), -- Sarah Williams vs Robert Wilson in Boxing
-(6, 9, 1, 1, true, true, false, 18, '2024-04-09 18:45:00'), -- Emily Davis vs Michael Anderson in Muay Thai
-(10, 4, 3, 3, true, true, false, 22, '2024-04-10 19:45:00'); -- Lisa Thomas vs Sarah Williams in Boxing
+(1, 3, 9, 1, 'completed', 3, 20, '2024-04-06 20:00:00'), -- John Smith vs Mike Johnson in Muay Thai
+(8, 5, 10, 2, 'completed', 5, 15, '2024-04-07 19:15:00'), -- Jessica Taylor vs David Brown in BJJ
+(4, 7, 2, 3, 'completed', 7, 25, '2024-04-08 20:30:00'), -- Sarah Williams vs Robert Wilson in Boxing
+(6, 9, 1, 1, 'completed', 9, 18, '2024-04-09 18:45:00'), -- Emily Davis vs Michael Anderson in Muay Thai
+(10, 4, 3, 3, 'completed', 4, 22, '2024-04-10 19:45:00'); -- Lisa Thomas vs Sarah Williams in Boxing
 
 -- Insert outcomes for completed bouts
 INSERT INTO outcome (bout_id, winner_id, loser_id, style_id, is_draw, created_dt)
@@ -200,7 +200,10 @@ VALUES
 (20, 4, 10, 3, false, '2024-04-10 21:45:00'); -- Sarah Williams beat Lisa Thomas in Boxing
 
 -- Update bout completion status
-UPDATE bout SET completed = true WHERE bout_id IN (16, 17, 18, 19, 20);
+UPDATE bout SET status = 'completed' WHERE bout_id IN (16, 17, 18, 19, 20);
+
+-- Every test outcome was confirmed when it was recorded
+UPDATE outcome SET confirmed_dt = created_dt;
 
 -- Update athlete records for winners and losers
 UPDATE athlete_record SET wins = wins + 1 WHERE athlete_id = 1;  -- John Smith wins +1
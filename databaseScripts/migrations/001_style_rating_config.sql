@@ -0,0 +1,18 @@
+-- Per-style rating engine and settings. Styles without a row keep using Elo with a K-factor of 32.
+BEGIN;
+
+CREATE TABLE style_rating_config (
+    style_id int PRIMARY KEY,
+    engine varchar(20) NOT NULL DEFAULT 'elo',
+    settings jsonb NOT NULL DEFAULT '{}',
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id)
+);
+
+CREATE TRIGGER update_style_rating_config_updated_dt
+    BEFORE UPDATE ON style_rating_config
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Glicko-2 rating deviation and volatility per athlete and style, and their before and after values
+-- in the score history. Existing rows stay NULL and the engine starts them from its defaults.
+BEGIN;
+
+ALTER TABLE athlete_score
+    ADD COLUMN rating_deviation numeric(8, 3),
+    ADD COLUMN volatility numeric(10, 8);
+
+ALTER TABLE athlete_score_history
+    ADD COLUMN previous_rating_deviation numeric(8, 3),
+    ADD COLUMN new_rating_deviation numeric(8, 3),
+    ADD COLUMN previous_volatility numeric(10, 8),
+    ADD COLUMN new_volatility numeric(10, 8);
+
+COMMIT;
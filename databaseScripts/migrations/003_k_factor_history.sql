@@ -0,0 +1,7 @@
+-- Record the K-factor applied to each rating change. Earlier rows and engines without a K stay NULL.
+BEGIN;
+
+ALTER TABLE athlete_score_history
+    ADD COLUMN k_factor numeric(6, 2);
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- How each outcome was decided and by how much, used to scale its rating change.
+-- Existing outcomes are recorded as decisions with no margin, which leaves their rating changes unscaled.
+BEGIN;
+
+ALTER TABLE outcome
+    ADD COLUMN finish_method varchar(20) NOT NULL DEFAULT 'decision',
+    ADD COLUMN score_margin int NOT NULL DEFAULT 0,
+    ADD CONSTRAINT CHK_finish_method CHECK (finish_method IN ('submission', 'points', 'decision', 'dq', 'forfeit'));
+
+COMMIT;
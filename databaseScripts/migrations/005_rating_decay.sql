@@ -0,0 +1,11 @@
+-- Inactivity decay: athletes returning from a decay are flagged provisional, and every score history
+-- row records why the rating changed. Earlier history rows keep a NULL reason.
+BEGIN;
+
+ALTER TABLE athlete_score
+    ADD COLUMN provisional boolean NOT NULL DEFAULT false;
+
+ALTER TABLE athlete_score_history
+    ADD COLUMN reason varchar(20);
+
+COMMIT;
@@ -0,0 +1,47 @@
+-- Seasons: each style runs dated seasons, and closing one archives the final standings and
+-- soft-resets every rating toward the recorded mean.
+BEGIN;
+
+CREATE TABLE season (
+    season_id serial PRIMARY KEY,
+    style_id int NOT NULL,
+    season_name varchar(100) NOT NULL,
+    start_dt date NOT NULL,
+    end_dt date NOT NULL,
+    reset_mean numeric(8, 3),
+    reset_weight numeric(4, 3),
+    closed_dt timestamp,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT CHK_season_dates CHECK (end_dt >= start_dt)
+);
+
+CREATE TABLE season_standing (
+    season_id int NOT NULL,
+    athlete_id int NOT NULL,
+    style_id int NOT NULL,
+    final_rank int NOT NULL,
+    final_score int NOT NULL,
+    rating_deviation numeric(8, 3),
+    volatility numeric(10, 8),
+    rated_bouts int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT PK_season_standing PRIMARY KEY (season_id, athlete_id),
+    CONSTRAINT FK_season_id FOREIGN KEY (season_id) REFERENCES season(season_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id)
+);
+
+CREATE TRIGGER update_season_updated_dt
+    BEFORE UPDATE ON season
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_season_standing_updated_dt
+    BEFORE UPDATE ON season_standing
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
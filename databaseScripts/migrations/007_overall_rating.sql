@@ -0,0 +1,33 @@
+-- Overall rating: a composite rating per athlete across every style they compete in, and a single
+-- config row holding the weighting settings.
+BEGIN;
+
+CREATE TABLE athlete_overall_rating (
+    athlete_id int PRIMARY KEY,
+    overall_score numeric(8, 3) NOT NULL,
+    styles_rated int NOT NULL DEFAULT 0,
+    rated_bouts int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id)
+);
+
+CREATE TABLE overall_rating_config (
+    config_id int PRIMARY KEY DEFAULT 1,
+    settings jsonb NOT NULL DEFAULT '{}',
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT CHK_single_config CHECK (config_id = 1)
+);
+
+CREATE TRIGGER update_athlete_overall_rating_updated_dt
+    BEFORE UPDATE ON athlete_overall_rating
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_overall_rating_config_updated_dt
+    BEFORE UPDATE ON overall_rating_config
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- Store ratings at full precision instead of truncating them to whole points.
+-- Existing values are kept as they are; they were already truncated when written.
+BEGIN;
+
+ALTER TABLE athlete_score
+    ALTER COLUMN score TYPE double precision USING score::double precision,
+    ALTER COLUMN rating_deviation TYPE double precision USING rating_deviation::double precision,
+    ALTER COLUMN volatility TYPE double precision USING volatility::double precision;
+
+ALTER TABLE athlete_score_history
+    ALTER COLUMN previous_score TYPE double precision USING previous_score::double precision,
+    ALTER COLUMN new_score TYPE double precision USING new_score::double precision,
+    ALTER COLUMN previous_rating_deviation TYPE double precision USING previous_rating_deviation::double precision,
+    ALTER COLUMN new_rating_deviation TYPE double precision USING new_rating_deviation::double precision,
+    ALTER COLUMN previous_volatility TYPE double precision USING previous_volatility::double precision,
+    ALTER COLUMN new_volatility TYPE double precision USING new_volatility::double precision;
+
+ALTER TABLE athlete_overall_rating
+    ALTER COLUMN overall_score TYPE double precision USING overall_score::double precision;
+
+ALTER TABLE season_standing
+    ALTER COLUMN final_score TYPE double precision USING final_score::double precision,
+    ALTER COLUMN rating_deviation TYPE double precision USING rating_deviation::double precision,
+    ALTER COLUMN volatility TYPE double precision USING volatility::double precision;
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- Replace the accepted/completed/cancelled flags on bout with a single status column
+-- and start an audit trail of status changes.
+BEGIN;
+
+ALTER TABLE bout ADD COLUMN status varchar(20) NOT NULL DEFAULT 'proposed';
+
+UPDATE bout SET status = CASE
+    WHEN cancelled THEN 'cancelled'
+    WHEN completed AND accepted THEN 'completed'
+    -- DeclineBout used to mark declined bouts as completed without accepting them
+    WHEN completed THEN 'declined'
+    WHEN accepted THEN 'accepted'
+    ELSE 'proposed'
+END;
+
+ALTER TABLE bout
+    ADD CONSTRAINT CHK_bout_status CHECK (status IN ('proposed', 'accepted', 'declined', 'scheduled', 'in_progress', 'completed', 'cancelled', 'voided')),
+    DROP COLUMN accepted,
+    DROP COLUMN completed,
+    DROP COLUMN cancelled;
+
+CREATE TABLE bout_status_history (
+    history_id serial PRIMARY KEY,
+    bout_id int NOT NULL,
+    from_status varchar(20),
+    to_status varchar(20) NOT NULL,
+    actor_id int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_actor_id FOREIGN KEY (actor_id) REFERENCES athlete(athlete_id)
+);
+
+CREATE TRIGGER update_bout_status_history_updated_dt
+    BEFORE UPDATE ON bout_status_history
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+-- Record each existing bout's current status as its starting point
+INSERT INTO bout_status_history (bout_id, from_status, to_status, created_dt, updated_dt)
+SELECT bout_id, NULL, status, updated_dt, updated_dt FROM bout;
+
+COMMIT;
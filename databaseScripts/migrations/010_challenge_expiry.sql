@@ -0,0 +1,38 @@
+-- Let proposed challenges expire after a per-style number of hours and keep a
+-- notification inbox so challengers hear about it.
+BEGIN;
+
+ALTER TABLE style
+    ADD COLUMN challenge_expiry_hours int NOT NULL DEFAULT 168,
+    ADD CONSTRAINT CHK_style_challenge_expiry CHECK (challenge_expiry_hours > 0);
+
+ALTER TABLE bout ADD COLUMN expires_dt timestamp;
+
+-- Existing proposals get their style's default window counted from when they were made
+UPDATE bout b SET expires_dt = b.created_dt + make_interval(hours => s.challenge_expiry_hours)
+FROM style s
+WHERE b.style_id = s.style_id AND b.status = 'proposed';
+
+ALTER TABLE bout
+    DROP CONSTRAINT CHK_bout_status,
+    ADD CONSTRAINT CHK_bout_status CHECK (status IN ('proposed', 'accepted', 'declined', 'scheduled', 'in_progress', 'completed', 'cancelled', 'voided', 'expired'));
+
+CREATE TABLE notification (
+    notification_id serial PRIMARY KEY,
+    athlete_id int NOT NULL,
+    bout_id int,
+    kind varchar(30) NOT NULL,
+    message varchar(255) NOT NULL,
+    read_dt timestamp,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id)
+);
+
+CREATE TRIGGER update_notification_updated_dt
+    BEFORE UPDATE ON notification
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Give bouts a start time, a length and a venue gym.
+BEGIN;
+
+ALTER TABLE bout
+    ADD COLUMN scheduled_at timestamptz,
+    ADD COLUMN duration_minutes int NOT NULL DEFAULT 60,
+    ADD COLUMN gym_id int,
+    ADD CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    ADD CONSTRAINT CHK_bout_duration CHECK (duration_minutes > 0);
+
+COMMIT;
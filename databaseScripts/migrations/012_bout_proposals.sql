@@ -0,0 +1,40 @@
+-- Let the two athletes of a proposed bout counter each other's terms. responder_id is the
+-- athlete whose turn it is to accept, decline or counter; bout_proposal keeps every round.
+BEGIN;
+
+ALTER TABLE bout ADD COLUMN responder_id int;
+UPDATE bout SET responder_id = acceptor_id;
+ALTER TABLE bout
+    ALTER COLUMN responder_id SET NOT NULL,
+    ADD CONSTRAINT FK_responder_id FOREIGN KEY (responder_id) REFERENCES athlete(athlete_id);
+
+CREATE TABLE bout_proposal (
+    proposal_id serial PRIMARY KEY,
+    bout_id int NOT NULL,
+    round int NOT NULL,
+    proposed_by int NOT NULL,
+    style_id int NOT NULL,
+    referee_id int NOT NULL,
+    scheduled_at timestamptz,
+    duration_minutes int NOT NULL,
+    gym_id int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_proposed_by FOREIGN KEY (proposed_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT FK_referee_id FOREIGN KEY (referee_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    CONSTRAINT unique_bout_proposal_round UNIQUE (bout_id, round)
+);
+
+CREATE TRIGGER update_bout_proposal_updated_dt
+    BEFORE UPDATE ON bout_proposal
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+-- The challenger's original terms are the first round of every existing bout
+INSERT INTO bout_proposal (bout_id, round, proposed_by, style_id, referee_id, scheduled_at, duration_minutes, gym_id, created_dt, updated_dt)
+SELECT bout_id, 1, challenger_id, style_id, referee_id, scheduled_at, duration_minutes, gym_id, created_dt, created_dt FROM bout;
+
+COMMIT;
@@ -0,0 +1,39 @@
+-- Open challenges are posted without an acceptor; the first eligible athlete to claim one
+-- becomes the acceptor of a new bout.
+BEGIN;
+
+CREATE TABLE open_challenge (
+    challenge_id serial PRIMARY KEY,
+    challenger_id int NOT NULL,
+    style_id int NOT NULL,
+    referee_id int NOT NULL,
+    min_rating double precision,
+    max_rating double precision,
+    gym_id int,
+    region varchar(2),
+    scheduled_at timestamptz,
+    duration_minutes int NOT NULL DEFAULT 60,
+    points int NOT NULL DEFAULT 0,
+    status varchar(20) NOT NULL DEFAULT 'open',
+    claimed_by int,
+    bout_id int,
+    expires_dt timestamp NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_challenger_id FOREIGN KEY (challenger_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_style_id FOREIGN KEY (style_id) REFERENCES style(style_id),
+    CONSTRAINT FK_referee_id FOREIGN KEY (referee_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_gym_id FOREIGN KEY (gym_id) REFERENCES gym(gym_id),
+    CONSTRAINT FK_claimed_by FOREIGN KEY (claimed_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT CHK_open_challenge_status CHECK (status IN ('open', 'claimed', 'cancelled', 'expired')),
+    CONSTRAINT CHK_open_challenge_rating_window CHECK (min_rating IS NULL OR max_rating IS NULL OR min_rating <= max_rating),
+    CONSTRAINT CHK_open_challenge_duration CHECK (duration_minutes > 0)
+);
+
+CREATE TRIGGER update_open_challenge_updated_dt
+    BEFORE UPDATE ON open_challenge
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
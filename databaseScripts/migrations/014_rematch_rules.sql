@@ -0,0 +1,8 @@
+-- Bouts created over a style's rematch limits can go ahead unrated. The flag is copied onto the
+-- outcome so rating replays leave those outcomes unrated too.
+BEGIN;
+
+ALTER TABLE bout ADD COLUMN rated boolean NOT NULL DEFAULT true;
+ALTER TABLE outcome ADD COLUMN rated boolean NOT NULL DEFAULT true;
+
+COMMIT;
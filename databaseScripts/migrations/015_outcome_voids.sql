@@ -0,0 +1,25 @@
+-- Let a referee or admin void a wrongly recorded outcome. The outcome is kept with its voided_dt
+-- set, and every void is audited in outcome_void with who did it and why.
+BEGIN;
+
+ALTER TABLE outcome ADD COLUMN voided_dt timestamp;
+
+CREATE TABLE outcome_void (
+    void_id serial PRIMARY KEY,
+    outcome_id int NOT NULL UNIQUE,
+    bout_id int,
+    voided_by int NOT NULL,
+    reason varchar(500) NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_bout_id FOREIGN KEY (bout_id) REFERENCES bout(bout_id),
+    CONSTRAINT FK_voided_by FOREIGN KEY (voided_by) REFERENCES athlete(athlete_id)
+);
+
+CREATE TRIGGER update_outcome_void_updated_dt
+    BEFORE UPDATE ON outcome_void
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
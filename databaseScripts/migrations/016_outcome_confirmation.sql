@@ -0,0 +1,59 @@
+-- Outcomes recorded by a referee wait for both athletes to confirm them. A disputed outcome holds
+-- its rating changes, and its bout is marked disputed, until an admin or the owner of the bout's
+-- gym resolves it. Existing outcomes count as confirmed when they were recorded.
+BEGIN;
+
+ALTER TABLE gym
+    ADD COLUMN owner_id int,
+    ADD CONSTRAINT FK_owner_id FOREIGN KEY (owner_id) REFERENCES athlete(athlete_id);
+
+ALTER TABLE outcome
+    ADD COLUMN confirmation_status varchar(20) NOT NULL DEFAULT 'confirmed',
+    ADD COLUMN confirm_by_dt timestamp,
+    ADD COLUMN confirmed_dt timestamp,
+    ADD CONSTRAINT CHK_outcome_confirmation_status CHECK (confirmation_status IN ('pending', 'confirmed', 'disputed'));
+
+UPDATE outcome SET confirmed_dt = created_dt;
+
+ALTER TABLE bout
+    DROP CONSTRAINT CHK_bout_status,
+    ADD CONSTRAINT CHK_bout_status CHECK (status IN ('proposed', 'accepted', 'declined', 'scheduled', 'in_progress', 'completed', 'disputed', 'cancelled', 'voided', 'expired'));
+
+CREATE TABLE outcome_response (
+    response_id serial PRIMARY KEY,
+    outcome_id int NOT NULL,
+    athlete_id int NOT NULL,
+    response varchar(20) NOT NULL,
+    reason varchar(500),
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT unique_outcome_response UNIQUE (outcome_id, athlete_id),
+    CONSTRAINT CHK_outcome_response CHECK (response IN ('confirm', 'dispute'))
+);
+
+CREATE TABLE outcome_resolution (
+    resolution_id serial PRIMARY KEY,
+    outcome_id int NOT NULL UNIQUE,
+    resolved_by int NOT NULL,
+    action varchar(20) NOT NULL,
+    reason varchar(500) NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_resolved_by FOREIGN KEY (resolved_by) REFERENCES athlete(athlete_id),
+    CONSTRAINT CHK_outcome_resolution_action CHECK (action IN ('uphold', 'overturn', 'void'))
+);
+
+CREATE TRIGGER update_outcome_response_updated_dt
+    BEFORE UPDATE ON outcome_response
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_resolution_updated_dt
+    BEFORE UPDATE ON outcome_resolution
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
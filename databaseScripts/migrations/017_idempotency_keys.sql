@@ -0,0 +1,27 @@
+-- Remember the response to each request sent with an Idempotency-Key header, so a retried bout or
+-- outcome mutation returns the original result instead of running again. A row without a
+-- status_code is a request still in progress. Rows are deleted once expires_dt has passed.
+BEGIN;
+
+CREATE TABLE idempotency_key (
+    idempotency_key_id serial PRIMARY KEY,
+    idempotency_key varchar(255) NOT NULL,
+    athlete_id int NOT NULL DEFAULT 0,
+    request_method varchar(10) NOT NULL,
+    request_path varchar(255) NOT NULL,
+    request_hash char(64) NOT NULL,
+    status_code int,
+    content_type varchar(100),
+    response_body text,
+    expires_dt timestamp NOT NULL,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT UQ_idempotency_key UNIQUE (athlete_id, idempotency_key)
+);
+
+CREATE TRIGGER update_idempotency_key_updated_dt
+    BEFORE UPDATE ON idempotency_key
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
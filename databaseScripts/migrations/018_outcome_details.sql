@@ -0,0 +1,44 @@
+-- Record how an outcome was reached: the submission, round and time of the finish in outcome_finish,
+-- and each athlete's points, advantages and penalties per round in outcome_round_score.
+BEGIN;
+
+CREATE TABLE outcome_finish (
+    outcome_id int PRIMARY KEY,
+    submission_type varchar(50),
+    finish_round int,
+    finish_time_seconds int,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT CHK_outcome_finish_round CHECK (finish_round > 0),
+    CONSTRAINT CHK_outcome_finish_time CHECK (finish_time_seconds >= 0)
+);
+
+CREATE TABLE outcome_round_score (
+    round_score_id serial PRIMARY KEY,
+    outcome_id int NOT NULL,
+    round_number int NOT NULL,
+    athlete_id int NOT NULL,
+    points int NOT NULL DEFAULT 0,
+    advantages int NOT NULL DEFAULT 0,
+    penalties int NOT NULL DEFAULT 0,
+    created_dt timestamp NOT NULL DEFAULT now(),
+    updated_dt timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT FK_outcome_id FOREIGN KEY (outcome_id) REFERENCES outcome(outcome_id),
+    CONSTRAINT FK_athlete_id FOREIGN KEY (athlete_id) REFERENCES athlete(athlete_id),
+    CONSTRAINT UQ_outcome_round_score UNIQUE (outcome_id, round_number, athlete_id),
+    CONSTRAINT CHK_outcome_round_number CHECK (round_number > 0),
+    CONSTRAINT CHK_outcome_round_score CHECK (points >= 0 AND advantages >= 0 AND penalties >= 0)
+);
+
+CREATE TRIGGER update_outcome_finish_updated_dt
+    BEFORE UPDATE ON outcome_finish
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+CREATE TRIGGER update_outcome_round_score_updated_dt
+    BEFORE UPDATE ON outcome_round_score
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_dt_column();
+
+COMMIT;
@@ -4,15 +4,22 @@ import "ronin/models"
 
 // BoutService defines the interface for bout-related operations
 type BoutService interface {
-	GetAll() ([]models.OutboundBout, error)
+	List(filter models.BoutListFilter) (models.BoutPage, error)
 	GetByID(id string) (models.OutboundBout, error)
-	Create(bout models.Bout) (models.OutboundBout, error)
-	Update(id string, bout models.Bout) error
-	Delete(id string) error
-	Accept(id string) error
-	Decline(id string) error
-	Complete(boutID string, refereeID string) error
-	Cancel(boutID string, challengerID string) error
+	Create(bout models.Bout, actorID int) (models.OutboundBout, error)
+	Update(id string, bout models.Bout, actorID int) error
+	Delete(id string, actorID int) error
+	Accept(id string, actorID int) error
+	Decline(id string, actorID int) error
+	Counter(boutID string, counter models.BoutCounterProposal, actorID int) error
+	GetProposals(boutID string) ([]models.BoutProposal, error)
+	Start(boutID string, actorID int) error
+	Complete(boutID string, actorID int) error
+	Cancel(boutID string, actorID int) error
+	Schedule(boutID string, schedule models.BoutSchedule, actorID int) error
+	GetStatusHistory(boutID string) ([]models.BoutStatusHistory, error)
 	GetPendingBouts(athleteID string) ([]models.OutboundBout, error)
 	GetIncompleteBouts(athleteID string) ([]models.OutboundBout, error)
+	GetUpcomingBouts(athleteID string) ([]models.OutboundBout, error)
+	GetCalendar(athleteID string) (string, error)
 }
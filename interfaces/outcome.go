@@ -6,7 +6,14 @@ import "ronin/models"
 type OutcomeService interface {
 	GetAll() ([]models.Outcome, error)
 	GetByID(id string) (models.Outcome, error)
-	Create(outcome models.Outcome) error
-	CreateForBout(outcome models.Outcome, boutID string) error
+	Create(outcome models.Outcome, actorID int) (models.Outcome, error)
+	CreateForBout(outcome models.Outcome, boutID string, actorID int) (models.Outcome, error)
 	GetByBoutID(boutID string) (models.Outcome, error)
+	Void(outcomeID string, reason string, actorID int) (models.OutcomeVoidReport, error)
+	GetVoid(outcomeID string) (models.OutcomeVoid, error)
+	Confirm(outcomeID string, actorID int) (models.Outcome, error)
+	Dispute(outcomeID string, reason string, actorID int) (models.Outcome, error)
+	Resolve(outcomeID string, request models.OutcomeResolutionRequest, actorID int) (models.OutcomeResolution, error)
+	GetReview(outcomeID string) (models.OutcomeReview, error)
+	ConfirmOverdue() error
 }
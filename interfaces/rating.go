@@ -0,0 +1,10 @@
+package interfaces
+
+import "ronin/models"
+
+// RatingEngine defines the interface for a rating system that scores bouts within a style
+type RatingEngine interface {
+	Name() string
+	Rate(input models.RatingInput) (models.RatingResult, error)
+	ExpectedScore(athlete, opponent models.AthleteScore) float64
+}
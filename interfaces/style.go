@@ -8,5 +8,6 @@ type StyleService interface {
 	Create(style models.Style) error
 	RegisterAthleteToStyle(athleteID int, styleID int) error
 	RegisterMultipleStylesToAthlete(athleteID int, styles []int) error
+	SetChallengeExpiry(styleID int, hours int, actorID int) error
 	GetCommonStyles(acceptorID, challengerID string) ([]models.Style, error)
 }
@@ -5,8 +5,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"ronin/interfaces"
+	"ronin/models"
 	"ronin/repositories"
 	"ronin/router"
 	"ronin/services"
@@ -25,6 +27,16 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
+	// Configure how ratings are rounded in responses
+	rounding, err := models.NewRatingRounding(os.Getenv("RATING_DISPLAY_ROUNDING"), utils.GetIntEnv("RATING_DISPLAY_DECIMALS", 0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	models.DisplayRounding = rounding
+
+	// Configure auth token signing and platform admins
+	utils.ConfigureAuth()
+
 	// Get database connection
 	dbconn := utils.GetConnection()
 
@@ -36,15 +48,34 @@ func main() {
 	feedRepo := repositories.NewFeedRepository(dbconn)
 	gymRepo := repositories.NewGymRepository(dbconn)
 	styleRepo := repositories.NewStyleRepository(dbconn)
+	ratingConfigRepo := repositories.NewRatingConfigRepository(dbconn)
+	seasonRepo := repositories.NewSeasonRepository(dbconn)
+	overallRatingRepo := repositories.NewOverallRatingRepository(dbconn)
+	notificationRepo := repositories.NewNotificationRepository(dbconn)
+	idempotencyRepo := repositories.NewIdempotencyRepository(dbconn)
+	openChallengeRepo := repositories.NewOpenChallengeRepository(dbconn)
+	matchmakingRepo := repositories.NewMatchmakingRepository(dbconn)
+	unitOfWork := repositories.NewUnitOfWork(dbconn)
 
 	// Initialize services
-	athleteScoreService := services.NewAthleteScoreService(athleteScoreRepo)
+	athleteScoreService := services.NewAthleteScoreService(athleteScoreRepo, ratingConfigRepo)
+	overallRatingService := services.NewOverallRatingService(overallRatingRepo)
 	var athleteService interfaces.AthleteService = services.NewAthleteService(athleteRepo)
-	var outcomeService interfaces.OutcomeService = services.NewOutcomeService(outcomeRepo, athleteScoreService, boutRepo)
-	boutService := services.NewBoutService(boutRepo)
+	ratingReplayService := services.NewRatingReplayService(athleteScoreService, overallRatingService, athleteScoreRepo, outcomeRepo, styleRepo, seasonRepo,
+		unitOfWork)
+	var outcomeService interfaces.OutcomeService = services.NewOutcomeService(outcomeRepo, athleteScoreService, overallRatingService, boutRepo, unitOfWork,
+		ratingReplayService, gymRepo, notificationRepo, utils.GetDurationEnv("OUTCOME_CONFIRMATION_WINDOW", 48*time.Hour))
+	boutService := services.NewBoutService(boutRepo, styleRepo, athleteScoreService)
 	feedService := services.NewFeedService(feedRepo)
 	gymService := services.NewGymService(gymRepo)
 	styleService := services.NewStyleService(styleRepo, athleteScoreService)
+	ratingDecayService := services.NewRatingDecayService(athleteScoreService, overallRatingService, athleteScoreRepo, styleRepo)
+	seasonService := services.NewSeasonService(athleteScoreService, overallRatingService, athleteScoreRepo, seasonRepo)
+	challengeExpiryService := services.NewChallengeExpiryService(boutRepo, openChallengeRepo)
+	openChallengeService := services.NewOpenChallengeService(openChallengeRepo, boutRepo, athleteScoreService)
+	matchmakingService := services.NewMatchmakingService(matchmakingRepo, athleteScoreService)
+	notificationService := services.NewNotificationService(notificationRepo)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, utils.GetDurationEnv("IDEMPOTENCY_KEY_RETENTION", 24*time.Hour))
 
 	// Initialize handlers
 	athleteHandler := services.NewAthleteHandler(athleteService)
@@ -54,6 +85,12 @@ func main() {
 	feedHandler := services.NewFeedHandler(feedService)
 	gymHandler := services.NewGymHandler(gymService)
 	styleHandler := services.NewStyleHandler(styleService)
+	ratingReplayHandler := services.NewRatingReplayHandler(ratingReplayService)
+	seasonHandler := services.NewSeasonHandler(seasonService)
+	overallRatingHandler := services.NewOverallRatingHandler(overallRatingService)
+	notificationHandler := services.NewNotificationHandler(notificationService)
+	openChallengeHandler := services.NewOpenChallengeHandler(openChallengeService)
+	matchmakingHandler := services.NewMatchmakingHandler(matchmakingService)
 
 	// Set handlers in router package
 	router.SetAthleteHandler(athleteHandler)
@@ -63,6 +100,20 @@ func main() {
 	router.SetFeedHandler(feedHandler)
 	router.SetGymHandler(gymHandler)
 	router.SetStyleHandler(styleHandler)
+	router.SetRatingReplayHandler(ratingReplayHandler)
+	router.SetSeasonHandler(seasonHandler)
+	router.SetOverallRatingHandler(overallRatingHandler)
+	router.SetNotificationHandler(notificationHandler)
+	router.SetOpenChallengeHandler(openChallengeHandler)
+	router.SetMatchmakingHandler(matchmakingHandler)
+	router.SetIdempotencyService(idempotencyService)
+
+	// Start background jobs
+	utils.RunEvery("rating decay", utils.GetDurationEnv("RATING_DECAY_INTERVAL", 24*time.Hour), ratingDecayService.Run)
+	utils.RunEvery("season close", utils.GetDurationEnv("SEASON_CLOSE_INTERVAL", time.Hour), seasonService.CloseEndedSeasons)
+	utils.RunEvery("challenge expiry", utils.GetDurationEnv("CHALLENGE_EXPIRY_INTERVAL", 5*time.Minute), challengeExpiryService.Run)
+	utils.RunEvery("outcome confirmation", utils.GetDurationEnv("OUTCOME_CONFIRMATION_INTERVAL", 5*time.Minute), outcomeService.ConfirmOverdue)
+	utils.RunEvery("idempotency key cleanup", utils.GetDurationEnv("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour), idempotencyService.DeleteExpired)
 
 	// Create router with all routes configured
 	r := router.CreateRouter()
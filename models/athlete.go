@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 type Athlete struct {
 	AthleteId	 int    `json:"athlete_id" db:"athlete_id"`
 	FirstName	 string `json:"firstName" db:"first_name"`
@@ -10,6 +12,15 @@ type Athlete struct {
 	Password  	string `json:"password" db:"password"`
 	CreatedDate string `json:"createdDate" db:"created_dt"`
 	UpdatedDate string `json:"updatedDate" db:"updated_dt"`
+	OverallRating float64 `json:"overallRating,omitempty" db:"overall_rating"`
+}
+
+// MarshalJSON writes the athlete with display rounding applied to their overall rating
+func (a Athlete) MarshalJSON() ([]byte, error) {
+	type athlete Athlete
+	display := athlete(a)
+	display.OverallRating = displayRating(a.OverallRating)
+	return json.Marshal(display)
 }
 
 func GetAthlete() Athlete {
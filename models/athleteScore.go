@@ -1,12 +1,27 @@
 package models
 
+import "encoding/json"
+
 type AthleteScore struct {
-	AthleteId   int     `json:"athleteId" db:"athlete_id"`
-	StyleId     int     `json:"styleId" db:"style_id"`
-	OutcomeId   int     `json:"outcomeId" db:"outcome_id"`
-	Score       float64 `json:"score" db:"score"`
-	CreatedDate string  `json:"createdDate" db:"created_dt"`
-	UpdatedDate string  `json:"updatedDate" db:"updated_dt"`
+	AthleteId       int     `json:"athleteId" db:"athlete_id"`
+	StyleId         int     `json:"styleId" db:"style_id"`
+	OutcomeId       int     `json:"outcomeId" db:"outcome_id"`
+	Score           float64 `json:"score" db:"score"`
+	RatingDeviation float64 `json:"ratingDeviation" db:"rating_deviation"`
+	Volatility      float64 `json:"volatility" db:"volatility"`
+	RatedBouts      int     `json:"ratedBouts" db:"rated_bouts"`
+	Provisional     bool    `json:"provisional" db:"provisional"`
+	CreatedDate     string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate     string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// MarshalJSON writes the score with display rounding applied
+func (s AthleteScore) MarshalJSON() ([]byte, error) {
+	type athleteScore AthleteScore
+	display := athleteScore(s)
+	display.Score = displayRating(s.Score)
+	display.RatingDeviation = displayRating(s.RatingDeviation)
+	return json.Marshal(display)
 }
 
 func GetAthleteScore() AthleteScore {
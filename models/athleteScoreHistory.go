@@ -0,0 +1,32 @@
+package models
+
+import "encoding/json"
+
+// AthleteScoreHistory is one recorded change to an athlete's rating in a style
+type AthleteScoreHistory struct {
+	HistoryId               int     `json:"historyId" db:"history_id"`
+	AthleteId               int     `json:"athleteId" db:"athlete_id"`
+	StyleId                 int     `json:"styleId" db:"style_id"`
+	OutcomeId               int     `json:"outcomeId" db:"outcome_id"`
+	PreviousScore           float64 `json:"previousScore" db:"previous_score"`
+	NewScore                float64 `json:"newScore" db:"new_score"`
+	PreviousRatingDeviation float64 `json:"previousRatingDeviation" db:"previous_rating_deviation"`
+	NewRatingDeviation      float64 `json:"newRatingDeviation" db:"new_rating_deviation"`
+	PreviousVolatility      float64 `json:"previousVolatility" db:"previous_volatility"`
+	NewVolatility           float64 `json:"newVolatility" db:"new_volatility"`
+	KFactor                 float64 `json:"kFactor" db:"k_factor"`
+	Reason                  string  `json:"reason" db:"reason"`
+	CreatedDate             string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate             string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// MarshalJSON writes the history entry with display rounding applied
+func (h AthleteScoreHistory) MarshalJSON() ([]byte, error) {
+	type athleteScoreHistory AthleteScoreHistory
+	display := athleteScoreHistory(h)
+	display.PreviousScore = displayRating(h.PreviousScore)
+	display.NewScore = displayRating(h.NewScore)
+	display.PreviousRatingDeviation = displayRating(h.PreviousRatingDeviation)
+	display.NewRatingDeviation = displayRating(h.NewRatingDeviation)
+	return json.Marshal(display)
+}
@@ -6,12 +6,31 @@ type Bout struct {
 	AcceptorId   int    `json:"acceptorId" db:"acceptor_id"`
 	RefereeId    int    `json:"refereeId" db:"referee_id"`
 	StyleId      int    `json:"styleId" db:"style_id"`
-	Accepted     bool   `json:"accepted" db:"accepted"`
-	Completed    bool   `json:"completed" db:"completed"`
-	Cancelled    bool   `json:"cancelled" db:"cancelled"`
-	Points       int    `json:"points" db:"points"`
-	CreatedDate  string `json:"createdDate" db:"created_dt"`
-	UpdatedDate  string `json:"updatedDate" db:"updated_dt"`
+	Status       string `json:"status" db:"status"`
+	// ResponderId is the athlete whose turn it is to accept, decline or counter a proposed bout
+	ResponderId int `json:"responderId" db:"responder_id"`
+	Points      int `json:"points" db:"points"`
+	// Rated is false when the bout was created over the style's rematch limits, so its outcome will not change ratings
+	Rated bool `json:"rated" db:"rated"`
+	// ExpiresInHours overrides the style's challenge expiry when creating a bout; zero uses the default
+	ExpiresInHours int     `json:"expiresInHours,omitempty" db:"-"`
+	ExpiresDate    *string `json:"expiresDate" db:"expires_dt"`
+	// ScheduledAt is an RFC 3339 start time; a bout without one has not been scheduled yet
+	ScheduledAt     *string `json:"scheduledAt" db:"scheduled_at"`
+	DurationMinutes int     `json:"durationMinutes" db:"duration_minutes"`
+	GymId           *int    `json:"gymId" db:"gym_id"`
+	CreatedDate     string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate     string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// DefaultBoutDurationMinutes is used when a bout is scheduled without a duration
+const DefaultBoutDurationMinutes = 60
+
+// BoutSchedule is when and where a bout takes place
+type BoutSchedule struct {
+	ScheduledAt     string `json:"scheduledAt"`
+	DurationMinutes int    `json:"durationMinutes"`
+	GymId           *int   `json:"gymId"`
 }
 
 func GetBout() Bout {
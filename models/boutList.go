@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Sort keys for bout listings
+const (
+	BoutSortCreated = "created"
+	BoutSortUpdated = "updated"
+	BoutSortId      = "id"
+)
+
+// BoutCursor marks where a page of bouts ended: the sort key value and bout ID of its last bout
+type BoutCursor struct {
+	Sort   string `json:"sort"`
+	Order  string `json:"order"`
+	Value  string `json:"value,omitempty"`
+	BoutId int    `json:"boutId"`
+}
+
+// BoutListFilter selects and orders bouts for a listing. Zero values are not filtered on.
+// AthleteId matches either competitor; From and To bound the creation time.
+type BoutListFilter struct {
+	AthleteId  int
+	StyleId    int
+	RefereeId  int
+	Statuses   []string
+	From       *time.Time
+	To         *time.Time
+	Sort       string
+	Descending bool
+	Limit      int
+	After      *BoutCursor
+}
+
+// BoutPaging describes a page of bouts and how to get the next one
+type BoutPaging struct {
+	Limit      int     `json:"limit"`
+	Count      int     `json:"count"`
+	Sort       string  `json:"sort"`
+	Order      string  `json:"order"`
+	HasMore    bool    `json:"hasMore"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// BoutPage is one page of a bout listing
+type BoutPage struct {
+	Bouts  []OutboundBout `json:"bouts"`
+	Paging BoutPaging     `json:"paging"`
+}
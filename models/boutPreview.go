@@ -0,0 +1,34 @@
+package models
+
+import "encoding/json"
+
+// BoutPreview shows what is at stake for both athletes in a proposed bout
+type BoutPreview struct {
+	StyleId    int             `json:"styleId"`
+	Engine     string          `json:"engine"`
+	Challenger BoutPreviewSide `json:"challenger"`
+	Acceptor   BoutPreviewSide `json:"acceptor"`
+}
+
+// BoutPreviewSide is one athlete's current rating and the rating change for each possible result
+type BoutPreviewSide struct {
+	AthleteId       int     `json:"athleteId"`
+	Score           float64 `json:"score"`
+	RatingDeviation float64 `json:"ratingDeviation"`
+	WinProbability  float64 `json:"winProbability"`
+	DeltaOnWin      float64 `json:"deltaOnWin"`
+	DeltaOnLoss     float64 `json:"deltaOnLoss"`
+	DeltaOnDraw     float64 `json:"deltaOnDraw"`
+}
+
+// MarshalJSON writes the preview side with display rounding applied to ratings and deltas
+func (s BoutPreviewSide) MarshalJSON() ([]byte, error) {
+	type boutPreviewSide BoutPreviewSide
+	display := boutPreviewSide(s)
+	display.Score = displayRating(s.Score)
+	display.RatingDeviation = displayRating(s.RatingDeviation)
+	display.DeltaOnWin = displayRating(s.DeltaOnWin)
+	display.DeltaOnLoss = displayRating(s.DeltaOnLoss)
+	display.DeltaOnDraw = displayRating(s.DeltaOnDraw)
+	return json.Marshal(display)
+}
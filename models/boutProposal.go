@@ -0,0 +1,27 @@
+package models
+
+// BoutProposal is one round of terms offered for a bout. Round 1 is the challenger's original
+// challenge; each counter-proposal adds the next round.
+type BoutProposal struct {
+	ProposalId      int     `json:"proposalId" db:"proposal_id"`
+	BoutId          int     `json:"boutId" db:"bout_id"`
+	Round           int     `json:"round" db:"round"`
+	ProposedBy      int     `json:"proposedBy" db:"proposed_by"`
+	StyleId         int     `json:"styleId" db:"style_id"`
+	RefereeId       int     `json:"refereeId" db:"referee_id"`
+	ScheduledAt     *string `json:"scheduledAt" db:"scheduled_at"`
+	DurationMinutes int     `json:"durationMinutes" db:"duration_minutes"`
+	GymId           *int    `json:"gymId" db:"gym_id"`
+	CreatedDate     string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate     string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// BoutCounterProposal changes the terms of a proposed bout. Zero or nil fields keep the current
+// terms, so a counter can change just the style, the referee or the time.
+type BoutCounterProposal struct {
+	StyleId         int     `json:"styleId"`
+	RefereeId       int     `json:"refereeId"`
+	ScheduledAt     *string `json:"scheduledAt"`
+	DurationMinutes int     `json:"durationMinutes"`
+	GymId           *int    `json:"gymId"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Bout statuses. A bout starts out proposed and can only move along the transitions
+// allowed by BoutService.
+const (
+	BoutStatusProposed   = "proposed"
+	BoutStatusAccepted   = "accepted"
+	BoutStatusDeclined   = "declined"
+	BoutStatusScheduled  = "scheduled"
+	BoutStatusInProgress = "in_progress"
+	BoutStatusCompleted  = "completed"
+	BoutStatusDisputed   = "disputed"
+	BoutStatusCancelled  = "cancelled"
+	BoutStatusVoided     = "voided"
+	BoutStatusExpired    = "expired"
+)
+
+// BoutStatusHistory is one recorded status change of a bout. FromStatus is empty for the
+// initial status and ActorId is zero when no athlete triggered the change.
+type BoutStatusHistory struct {
+	HistoryId   int    `json:"historyId" db:"history_id"`
+	BoutId      int    `json:"boutId" db:"bout_id"`
+	FromStatus  string `json:"fromStatus" db:"from_status"`
+	ToStatus    string `json:"toStatus" db:"to_status"`
+	ActorId     int    `json:"actorId" db:"actor_id"`
+	CreatedDate string `json:"createdDate" db:"created_dt"`
+}
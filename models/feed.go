@@ -1,38 +1,49 @@
 package models
 
+import "encoding/json"
+
 type Feed struct {
-	BoutId              int    `json:"boutId" db:"boutId"`
-	ChallengerId        int    `json:"challengerId" db:"challengerId"`
-	ChallengerFirstName string `json:"challengerFirstName" db:"challengerFirstName"`
-	ChallengerLastName  string `json:"challengerLastName" db:"challengerLastName"`
-	ChallengerUsername  string `json:"challengerUsername" db:"challengerUsername"`
-	Style               string `json:"style" db:"style"`
-	StyleId             int    `json:"styleId" db:"styleId"`
-	AcceptorId          int    `json:"acceptorId" db:"acceptorId"`
-	AcceptorFirstName   string `json:"acceptorFirstName" db:"acceptorFirstName"`
-	AcceptorLastName    string `json:"acceptorLastName" db:"acceptorLastName"`
-	AcceptorUsername    string `json:"acceptorUsername" db:"acceptorUsername"`
-	RefereeId           int    `json:"refereeId" db:"refereeId"`
-	RefereeFirstName    string `json:"refereeFirstName" db:"refereeFirstName"`
-	RefereeLastName     string `json:"refereeLastName" db:"refereeLastName"`
-	Date                string `json:"date" db:"updatedDt"`
-	WinnerScore         int    `json:"winnerScore" db:"winnerScore"`
-	LoserScore          int    `json:"loserScore" db:"loserScore"`
-	WinnerId            int    `json:"winnerId" db:"winnerId"`
-	WinnerWins          int    `json:"winnerWins" db:"winnerWins"`
-	WinnerLosses        int    `json:"winnerLosses" db:"winnerLosses"`
-	WinnerDraws         int    `json:"winnerDraws" db:"winnerDraws"`
-	LoserWins           int    `json:"loserWins" db:"loserWins"`
-	LoserLosses         int    `json:"loserLosses" db:"loserLosses"`
-	LoserDraws          int    `json:"loserDraws" db:"loserDraws"`
-	LoserId             int    `json:"loserId" db:"loserId"`
-	WinnerFirstName     string `json:"winnerFirstName" db:"winnerFirstName"`
-	WinnerLastName      string `json:"winnerLastName" db:"winnerLastName"`
-	WinnerUsername      string `json:"winnerUsername" db:"winnerUsername"`
-	LoserFirstName      string `json:"loserFirstName" db:"loserFirstName"`
-	LoserLastName       string `json:"loserLastName" db:"loserLastName"`
-	LoserUsername       string `json:"loserUsername" db:"loserUsername"`
-	IsDraw              bool   `json:"isDraw" db:"isDraw"`
+	BoutId              int     `json:"boutId" db:"boutId"`
+	ChallengerId        int     `json:"challengerId" db:"challengerId"`
+	ChallengerFirstName string  `json:"challengerFirstName" db:"challengerFirstName"`
+	ChallengerLastName  string  `json:"challengerLastName" db:"challengerLastName"`
+	ChallengerUsername  string  `json:"challengerUsername" db:"challengerUsername"`
+	Style               string  `json:"style" db:"style"`
+	StyleId             int     `json:"styleId" db:"styleId"`
+	AcceptorId          int     `json:"acceptorId" db:"acceptorId"`
+	AcceptorFirstName   string  `json:"acceptorFirstName" db:"acceptorFirstName"`
+	AcceptorLastName    string  `json:"acceptorLastName" db:"acceptorLastName"`
+	AcceptorUsername    string  `json:"acceptorUsername" db:"acceptorUsername"`
+	RefereeId           int     `json:"refereeId" db:"refereeId"`
+	RefereeFirstName    string  `json:"refereeFirstName" db:"refereeFirstName"`
+	RefereeLastName     string  `json:"refereeLastName" db:"refereeLastName"`
+	Date                string  `json:"date" db:"updatedDt"`
+	WinnerScore         float64 `json:"winnerScore" db:"winnerScore"`
+	LoserScore          float64 `json:"loserScore" db:"loserScore"`
+	WinnerId            int     `json:"winnerId" db:"winnerId"`
+	WinnerWins          int     `json:"winnerWins" db:"winnerWins"`
+	WinnerLosses        int     `json:"winnerLosses" db:"winnerLosses"`
+	WinnerDraws         int     `json:"winnerDraws" db:"winnerDraws"`
+	LoserWins           int     `json:"loserWins" db:"loserWins"`
+	LoserLosses         int     `json:"loserLosses" db:"loserLosses"`
+	LoserDraws          int     `json:"loserDraws" db:"loserDraws"`
+	LoserId             int     `json:"loserId" db:"loserId"`
+	WinnerFirstName     string  `json:"winnerFirstName" db:"winnerFirstName"`
+	WinnerLastName      string  `json:"winnerLastName" db:"winnerLastName"`
+	WinnerUsername      string  `json:"winnerUsername" db:"winnerUsername"`
+	LoserFirstName      string  `json:"loserFirstName" db:"loserFirstName"`
+	LoserLastName       string  `json:"loserLastName" db:"loserLastName"`
+	LoserUsername       string  `json:"loserUsername" db:"loserUsername"`
+	IsDraw              bool    `json:"isDraw" db:"isDraw"`
+}
+
+// MarshalJSON writes the feed entry with display rounding applied to both athletes' ratings
+func (f Feed) MarshalJSON() ([]byte, error) {
+	type feed Feed
+	display := feed(f)
+	display.WinnerScore = displayRating(f.WinnerScore)
+	display.LoserScore = displayRating(f.LoserScore)
+	return json.Marshal(display)
 }
 
 func GetFeed() Feed {
@@ -0,0 +1,18 @@
+package models
+
+// IdempotencyKey remembers a request sent with an Idempotency-Key header and, once it has finished,
+// the response it got. StatusCode is nil while the request is still in progress.
+type IdempotencyKey struct {
+	IdempotencyKeyId int     `json:"idempotencyKeyId" db:"idempotency_key_id"`
+	Key              string  `json:"key" db:"idempotency_key"`
+	AthleteId        int     `json:"athleteId" db:"athlete_id"`
+	RequestMethod    string  `json:"requestMethod" db:"request_method"`
+	RequestPath      string  `json:"requestPath" db:"request_path"`
+	RequestHash      string  `json:"requestHash" db:"request_hash"`
+	StatusCode       *int    `json:"statusCode" db:"status_code"`
+	ContentType      *string `json:"contentType" db:"content_type"`
+	ResponseBody     *string `json:"responseBody" db:"response_body"`
+	ExpiresDate      string  `json:"expiresDate" db:"expires_dt"`
+	CreatedDate      string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate      string  `json:"updatedDate" db:"updated_dt"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "encoding/json"
+
+// Gym proximity levels between two athletes, from closest to furthest
+const (
+	GymProximitySameGym   = 3
+	GymProximitySameCity  = 2
+	GymProximitySameState = 1
+	GymProximityNone      = 0
+)
+
+// MatchCandidate is the raw data matchmaking scores a possible opponent on
+type MatchCandidate struct {
+	AthleteId    int     `db:"athlete_id"`
+	FirstName    string  `db:"first_name"`
+	LastName     string  `db:"last_name"`
+	Username     string  `db:"username"`
+	Score        float64 `db:"score"`
+	SharedStyles int     `db:"shared_styles"`
+	// AthleteStyles is how many styles the athlete looking for a match practises
+	AthleteStyles int     `db:"athlete_styles"`
+	GymProximity  int     `db:"gym_proximity"`
+	LastBoutDate  *string `db:"last_bout_dt"`
+}
+
+// MatchFactors are a suggestion's component scores, each between 0 and 1
+type MatchFactors struct {
+	Rating       float64 `json:"rating"`
+	SharedStyles float64 `json:"sharedStyles"`
+	Gym          float64 `json:"gym"`
+	Recency      float64 `json:"recency"`
+}
+
+// MatchSuggestion is a ranked opponent suggestion. MatchScore is the weighted average of Factors.
+type MatchSuggestion struct {
+	AthleteId        int          `json:"athleteId"`
+	FirstName        string       `json:"firstName"`
+	LastName         string       `json:"lastName"`
+	Username         string       `json:"username"`
+	Score            float64      `json:"score"`
+	RatingDifference float64      `json:"ratingDifference"`
+	SharedStyles     int          `json:"sharedStyles"`
+	GymProximity     int          `json:"gymProximity"`
+	LastBoutDate     *string      `json:"lastBoutDate"`
+	MatchScore       float64      `json:"matchScore"`
+	Factors          MatchFactors `json:"factors"`
+}
+
+// MarshalJSON writes the suggestion with display rounding applied to ratings
+func (m MatchSuggestion) MarshalJSON() ([]byte, error) {
+	type matchSuggestion MatchSuggestion
+	display := matchSuggestion(m)
+	display.Score = displayRating(m.Score)
+	display.RatingDifference = displayRating(m.RatingDifference)
+	return json.Marshal(display)
+}
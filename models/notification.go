@@ -0,0 +1,23 @@
+package models
+
+// Notification kinds
+const (
+	NotificationChallengeExpired = "challenge_expired"
+	NotificationBoutCountered    = "bout_countered"
+	NotificationChallengeClaimed = "challenge_claimed"
+	NotificationOutcomeRecorded  = "outcome_recorded"
+	NotificationOutcomeDisputed  = "outcome_disputed"
+	NotificationOutcomeResolved  = "outcome_resolved"
+)
+
+// Notification is a message for an athlete about one of their bouts. ReadDate is nil until the athlete reads it.
+type Notification struct {
+	NotificationId int     `json:"notificationId" db:"notification_id"`
+	AthleteId      int     `json:"athleteId" db:"athlete_id"`
+	BoutId         *int    `json:"boutId" db:"bout_id"`
+	Kind           string  `json:"kind" db:"kind"`
+	Message        string  `json:"message" db:"message"`
+	ReadDate       *string `json:"readDate" db:"read_dt"`
+	CreatedDate    string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate    string  `json:"updatedDate" db:"updated_dt"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// Open challenge statuses
+const (
+	OpenChallengeOpen      = "open"
+	OpenChallengeClaimed   = "claimed"
+	OpenChallengeCancelled = "cancelled"
+	OpenChallengeExpired   = "expired"
+)
+
+// OpenChallenge is a bout posted without an acceptor. Any athlete in the style whose rating is
+// inside [MinRating, MaxRating] and who trains at GymId or in Region can claim it; nil limits are
+// not checked. Claiming creates an accepted bout and records it in BoutId.
+type OpenChallenge struct {
+	ChallengeId     int      `json:"challengeId" db:"challenge_id"`
+	ChallengerId    int      `json:"challengerId" db:"challenger_id"`
+	StyleId         int      `json:"styleId" db:"style_id"`
+	RefereeId       int      `json:"refereeId" db:"referee_id"`
+	MinRating       *float64 `json:"minRating" db:"min_rating"`
+	MaxRating       *float64 `json:"maxRating" db:"max_rating"`
+	GymId           *int     `json:"gymId" db:"gym_id"`
+	Region          *string  `json:"region" db:"region"`
+	ScheduledAt     *string  `json:"scheduledAt" db:"scheduled_at"`
+	DurationMinutes int      `json:"durationMinutes" db:"duration_minutes"`
+	Points          int      `json:"points" db:"points"`
+	Status          string   `json:"status" db:"status"`
+	ClaimedBy       *int     `json:"claimedBy" db:"claimed_by"`
+	BoutId          *int     `json:"boutId" db:"bout_id"`
+	// ExpiresInHours overrides the style's challenge expiry when posting; zero uses the default
+	ExpiresInHours int    `json:"expiresInHours,omitempty" db:"-"`
+	ExpiresDate    string `json:"expiresDate" db:"expires_dt"`
+	CreatedDate    string `json:"createdDate" db:"created_dt"`
+	UpdatedDate    string `json:"updatedDate" db:"updated_dt"`
+}
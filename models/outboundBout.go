@@ -1,20 +1,41 @@
 package models
 
+import "encoding/json"
+
 type OutboundBout struct {
-	BoutId              int    `json:"boutId" db:"boutId"`
-	ChallengerId        int    `json:"challengerId" db:"challengerId"`
-	ChallengerFirstName string `json:"challengerFirstName" db:"challengerFirstName"`
-	ChallengerLastName  string `json:"challengerLastName" db:"challengerLastName"`
-	Style               string `json:"style" db:"style"`
-	StyleId             int    `json:"styleId" db:"styleId"`
-	ChallengerScore     int    `json:"challengerScore" db:"challengerScore"`
-	AcceptorId          int    `json:"acceptorId" db:"acceptorId"`
-	AcceptorFirstName   string `json:"acceptorFirstName" db:"acceptorFirstName"`
-	AcceptorLastName    string `json:"acceptorLastName" db:"acceptorLastName"`
-	AcceptorScore       int    `json:"acceptorScore" db:"acceptorScore"`
-	RefereeId           int    `json:"refereeId" db:"refereeId"`
-	RefereeFirstName    string `json:"refereeFirstName" db:"refereeFirstName"`
-	RefereeLastName     string `json:"refereeLastName" db:"refereeLastName"`
+	BoutId              int     `json:"boutId" db:"boutId"`
+	ChallengerId        int     `json:"challengerId" db:"challengerId"`
+	ChallengerFirstName string  `json:"challengerFirstName" db:"challengerFirstName"`
+	ChallengerLastName  string  `json:"challengerLastName" db:"challengerLastName"`
+	Style               string  `json:"style" db:"style"`
+	StyleId             int     `json:"styleId" db:"styleId"`
+	ChallengerScore     float64 `json:"challengerScore" db:"challengerScore"`
+	AcceptorId          int     `json:"acceptorId" db:"acceptorId"`
+	AcceptorFirstName   string  `json:"acceptorFirstName" db:"acceptorFirstName"`
+	AcceptorLastName    string  `json:"acceptorLastName" db:"acceptorLastName"`
+	AcceptorScore       float64 `json:"acceptorScore" db:"acceptorScore"`
+	RefereeId           int     `json:"refereeId" db:"refereeId"`
+	RefereeFirstName    string  `json:"refereeFirstName" db:"refereeFirstName"`
+	RefereeLastName     string  `json:"refereeLastName" db:"refereeLastName"`
+	Status              string  `json:"status" db:"status"`
+	ResponderId         int     `json:"responderId" db:"responderId"`
+	Rated               bool    `json:"rated" db:"rated"`
+	ExpiresDate         *string `json:"expiresDate" db:"expiresDate"`
+	ScheduledAt         *string `json:"scheduledAt" db:"scheduledAt"`
+	DurationMinutes     int     `json:"durationMinutes" db:"durationMinutes"`
+	GymId               *int    `json:"gymId" db:"gymId"`
+	GymName             *string `json:"gymName" db:"gymName"`
+	CreatedDate         string  `json:"createdDate" db:"createdDate"`
+	UpdatedDate         string  `json:"updatedDate" db:"updatedDate"`
+}
+
+// MarshalJSON writes the bout with display rounding applied to both competitors' ratings
+func (b OutboundBout) MarshalJSON() ([]byte, error) {
+	type outboundBout OutboundBout
+	display := outboundBout(b)
+	display.ChallengerScore = displayRating(b.ChallengerScore)
+	display.AcceptorScore = displayRating(b.AcceptorScore)
+	return json.Marshal(display)
 }
 
 func GetOutboundBout() OutboundBout {
@@ -1,14 +1,43 @@
 package models
 
+// Finish methods an outcome can be decided by
+const (
+	FinishSubmission       = "submission"
+	FinishPoints           = "points"
+	FinishDecision         = "decision"
+	FinishDisqualification = "dq"
+	FinishForfeit          = "forfeit"
+)
+
+// Outcome confirmation statuses. An outcome recorded by a bout's referee is pending until both
+// athletes confirm it or its confirmation window ends; only confirmed outcomes change ratings and records.
+const (
+	OutcomeStatusPending   = "pending"
+	OutcomeStatusConfirmed = "confirmed"
+	OutcomeStatusDisputed  = "disputed"
+)
+
 type Outcome struct {
-	OutcomeId int  `json:"outcomeId" db:"outcome_id"`
-	BoutId    int  `json:"boutId" db:"bout_id"`
-	WinnerId  int  `json:"winnerId" db:"winner_id"`
-	LoserId   int  `json:"loserId" db:"loser_id"`
-	StyleId   int  `json:"styleId" db:"style_id"`
-	IsDraw    bool `json:"isDraw" db:"is_draw"`
-	CreatedDate string `json:"createdDate" db:"created_dt"`
-	UpdatedDate string `json:"updatedDate" db:"updated_dt"`
+	OutcomeId    int    `json:"outcomeId" db:"outcome_id"`
+	BoutId       int    `json:"boutId" db:"bout_id"`
+	WinnerId     int    `json:"winnerId" db:"winner_id"`
+	LoserId      int    `json:"loserId" db:"loser_id"`
+	StyleId      int    `json:"styleId" db:"style_id"`
+	IsDraw       bool   `json:"isDraw" db:"is_draw"`
+	FinishMethod string `json:"finishMethod" db:"finish_method"`
+	ScoreMargin  int    `json:"scoreMargin" db:"score_margin"`
+	// Rated is false for outcomes of bouts created over a style's rematch limits
+	Rated              bool    `json:"rated" db:"rated"`
+	ConfirmationStatus string  `json:"confirmationStatus" db:"confirmation_status"`
+	ConfirmByDate      *string `json:"confirmByDate" db:"confirm_by_dt"`
+	ConfirmedDate      *string `json:"confirmedDate" db:"confirmed_dt"`
+	// VoidedDate is set once the outcome has been voided; a voided outcome no longer counts towards ratings or records
+	VoidedDate  *string `json:"voidedDate" db:"voided_dt"`
+	CreatedDate string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate string  `json:"updatedDate" db:"updated_dt"`
+	// Finish and Scorecard are stored in their own tables and only loaded for a single outcome
+	Finish    *OutcomeFinish      `json:"finish,omitempty" db:"-"`
+	Scorecard []OutcomeRoundScore `json:"scorecard,omitempty" db:"-"`
 }
 
 func GetOutcome() Outcome {
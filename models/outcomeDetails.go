@@ -0,0 +1,25 @@
+package models
+
+// OutcomeFinish describes how an outcome ended. SubmissionType is only set for submissions, and
+// FinishTimeSeconds is the time into FinishRound at which the bout was stopped.
+type OutcomeFinish struct {
+	OutcomeId         int     `json:"outcomeId" db:"outcome_id"`
+	SubmissionType    *string `json:"submissionType" db:"submission_type"`
+	FinishRound       *int    `json:"finishRound" db:"finish_round"`
+	FinishTimeSeconds *int    `json:"finishTimeSeconds" db:"finish_time_seconds"`
+	CreatedDate       string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate       string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// OutcomeRoundScore is one athlete's line on an outcome's scorecard for a single round
+type OutcomeRoundScore struct {
+	RoundScoreId int    `json:"roundScoreId" db:"round_score_id"`
+	OutcomeId    int    `json:"outcomeId" db:"outcome_id"`
+	RoundNumber  int    `json:"roundNumber" db:"round_number"`
+	AthleteId    int    `json:"athleteId" db:"athlete_id"`
+	Points       int    `json:"points" db:"points"`
+	Advantages   int    `json:"advantages" db:"advantages"`
+	Penalties    int    `json:"penalties" db:"penalties"`
+	CreatedDate  string `json:"createdDate" db:"created_dt"`
+	UpdatedDate  string `json:"updatedDate" db:"updated_dt"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// Answers an athlete can give to a pending outcome
+const (
+	OutcomeResponseConfirm = "confirm"
+	OutcomeResponseDispute = "dispute"
+)
+
+// Ways an admin or gym owner can resolve a disputed outcome
+const (
+	// OutcomeResolutionUphold confirms the outcome as the referee recorded it
+	OutcomeResolutionUphold = "uphold"
+	// OutcomeResolutionOverturn confirms the outcome with a corrected result
+	OutcomeResolutionOverturn = "overturn"
+	// OutcomeResolutionVoid voids the outcome and its bout
+	OutcomeResolutionVoid = "void"
+)
+
+// OutcomeResponse is an athlete's confirmation or dispute of a pending outcome
+type OutcomeResponse struct {
+	ResponseId  int     `json:"responseId" db:"response_id"`
+	OutcomeId   int     `json:"outcomeId" db:"outcome_id"`
+	AthleteId   int     `json:"athleteId" db:"athlete_id"`
+	Response    string  `json:"response" db:"response"`
+	Reason      *string `json:"reason" db:"reason"`
+	CreatedDate string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// OutcomeResolution is the audit record of how a disputed outcome was resolved
+type OutcomeResolution struct {
+	ResolutionId int    `json:"resolutionId" db:"resolution_id"`
+	OutcomeId    int    `json:"outcomeId" db:"outcome_id"`
+	ResolvedBy   int    `json:"resolvedBy" db:"resolved_by"`
+	Action       string `json:"action" db:"action"`
+	Reason       string `json:"reason" db:"reason"`
+	CreatedDate  string `json:"createdDate" db:"created_dt"`
+	UpdatedDate  string `json:"updatedDate" db:"updated_dt"`
+}
+
+// OutcomeDisputeRequest is the body of a request to dispute an outcome
+type OutcomeDisputeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// OutcomeResolutionRequest is the body of a request to resolve a disputed outcome. The winner,
+// loser and draw flag are only read when the action is overturn.
+type OutcomeResolutionRequest struct {
+	Action   string `json:"action"`
+	WinnerId int    `json:"winnerId"`
+	LoserId  int    `json:"loserId"`
+	IsDraw   bool   `json:"isDraw"`
+	Reason   string `json:"reason"`
+}
+
+// OutcomeReview is an outcome with every athlete's answer to it and, once a dispute is settled, its resolution
+type OutcomeReview struct {
+	Outcome    Outcome            `json:"outcome"`
+	Responses  []OutcomeResponse  `json:"responses"`
+	Resolution *OutcomeResolution `json:"resolution"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// OutcomeVoid is the audit record of a voided outcome: who voided it and why
+type OutcomeVoid struct {
+	VoidId      int    `json:"voidId" db:"void_id"`
+	OutcomeId   int    `json:"outcomeId" db:"outcome_id"`
+	BoutId      *int   `json:"boutId" db:"bout_id"`
+	VoidedBy    int    `json:"voidedBy" db:"voided_by"`
+	Reason      string `json:"reason" db:"reason"`
+	CreatedDate string `json:"createdDate" db:"created_dt"`
+	UpdatedDate string `json:"updatedDate" db:"updated_dt"`
+}
+
+// OutcomeVoidRequest is the body of a request to void an outcome
+type OutcomeVoidRequest struct {
+	Reason string `json:"reason"`
+}
+
+// OutcomeVoidReport describes a void and how the style's ratings were rebuilt without the outcome
+type OutcomeVoidReport struct {
+	Void             OutcomeVoid          `json:"void"`
+	OutcomesReplayed int                  `json:"outcomesReplayed"`
+	Changes          []RatingReplayChange `json:"changes"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "encoding/json"
+
+// OverallRating is an athlete's composite rating across every style they are registered in
+type OverallRating struct {
+	AthleteId    int                  `json:"athleteId" db:"athlete_id"`
+	Username     string               `json:"username,omitempty" db:"username"`
+	Rank         int                  `json:"rank,omitempty" db:"overall_rank"`
+	OverallScore float64              `json:"overallScore" db:"overall_score"`
+	StylesRated  int                  `json:"stylesRated" db:"styles_rated"`
+	RatedBouts   int                  `json:"ratedBouts" db:"rated_bouts"`
+	Styles       []OverallRatingStyle `json:"styles,omitempty" db:"-"`
+	UpdatedDate  string               `json:"updatedDate" db:"updated_dt"`
+}
+
+// OverallRatingStyle is one style's contribution to an athlete's overall rating
+type OverallRatingStyle struct {
+	StyleId    int     `json:"styleId" db:"style_id"`
+	StyleName  string  `json:"styleName" db:"style_name"`
+	Score      float64 `json:"score" db:"score"`
+	RatedBouts int     `json:"ratedBouts" db:"rated_bouts"`
+	Weight     float64 `json:"weight" db:"-"`
+}
+
+// OverallRatingConfig holds the weighting settings used to combine per-style scores
+type OverallRatingConfig struct {
+	Settings    json.RawMessage `json:"settings" db:"settings"`
+	UpdatedDate string          `json:"updatedDate,omitempty" db:"updated_dt"`
+}
+
+// MarshalJSON writes the overall rating with display rounding applied
+func (o OverallRating) MarshalJSON() ([]byte, error) {
+	type overallRating OverallRating
+	display := overallRating(o)
+	display.OverallScore = displayRating(o.OverallScore)
+	return json.Marshal(display)
+}
+
+// MarshalJSON writes the style contribution with display rounding applied
+func (s OverallRatingStyle) MarshalJSON() ([]byte, error) {
+	type overallRatingStyle OverallRatingStyle
+	display := overallRatingStyle(s)
+	display.Score = displayRating(s.Score)
+	return json.Marshal(display)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StyleRatingConfig selects the rating engine and its settings for a style
+type StyleRatingConfig struct {
+	StyleId     int             `json:"styleId" db:"style_id"`
+	Engine      string          `json:"engine" db:"engine"`
+	Settings    json.RawMessage `json:"settings" db:"settings"`
+	CreatedDate string          `json:"createdDate" db:"created_dt"`
+	UpdatedDate string          `json:"updatedDate" db:"updated_dt"`
+}
+
+// RatingInput is the current state of both athletes going into a rated outcome
+type RatingInput struct {
+	Winner       AthleteScore
+	Loser        AthleteScore
+	IsDraw       bool
+	FinishMethod string
+	ScoreMargin  int
+	PlayedAt     time.Time
+	Rematch      RematchHistory
+}
+
+// RematchHistory is how often the two athletes of an outcome were already rated against each other
+// in a style: the number of rated bouts within the style's rematch period and when the last one was
+type RematchHistory struct {
+	RatedInPeriod int        `db:"rated_in_period"`
+	LastRatedAt   *time.Time `db:"last_rated_at"`
+}
+
+// RatingResult holds the ratings produced by a rating engine for both athletes.
+// Unrated results are recorded as outcomes but must not change either rating. WinnerUnchanged
+// results only rate the loser: the winner's score, rated bouts and provisional flag stay as they were.
+type RatingResult struct {
+	Winner          RatingChange
+	Loser           RatingChange
+	Unrated         bool
+	WinnerUnchanged bool
+}
+
+// Reason codes recorded in athlete_score_history for each rating change
+const (
+	ScoreReasonRegistration = "registration"
+	ScoreReasonOutcome      = "outcome"
+	ScoreReasonDecay        = "decay"
+	ScoreReasonSeasonReset  = "season_reset"
+)
+
+// RatingChange is an athlete's new rating along with the K-factor that produced it
+// and the reason it changed. KFactor is zero for engines that do not use one.
+// It is only used internally; the embedded AthleteScore's MarshalJSON would hide the other fields.
+type RatingChange struct {
+	AthleteScore
+	KFactor float64 `json:"kFactor"`
+	Reason  string  `json:"reason"`
+}
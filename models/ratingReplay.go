@@ -0,0 +1,35 @@
+package models
+
+import "encoding/json"
+
+// RatingReplayReport summarizes a rating recomputation across one or more styles
+type RatingReplayReport struct {
+	StyleIds         []int                `json:"styleIds"`
+	DryRun           bool                 `json:"dryRun"`
+	OutcomesReplayed int                  `json:"outcomesReplayed"`
+	OutcomesSkipped  int                  `json:"outcomesSkipped"`
+	Changes          []RatingReplayChange `json:"changes"`
+}
+
+// RatingReplayChange compares an athlete's current rating in a style with the replayed one
+type RatingReplayChange struct {
+	AthleteId               int     `json:"athleteId"`
+	StyleId                 int     `json:"styleId"`
+	CurrentScore            float64 `json:"currentScore"`
+	ReplayedScore           float64 `json:"replayedScore"`
+	ScoreDelta              float64 `json:"scoreDelta"`
+	CurrentRatingDeviation  float64 `json:"currentRatingDeviation"`
+	ReplayedRatingDeviation float64 `json:"replayedRatingDeviation"`
+}
+
+// MarshalJSON writes the change with display rounding applied
+func (c RatingReplayChange) MarshalJSON() ([]byte, error) {
+	type ratingReplayChange RatingReplayChange
+	display := ratingReplayChange(c)
+	display.CurrentScore = displayRating(c.CurrentScore)
+	display.ReplayedScore = displayRating(c.ReplayedScore)
+	display.ScoreDelta = displayRating(c.ScoreDelta)
+	display.CurrentRatingDeviation = displayRating(c.CurrentRatingDeviation)
+	display.ReplayedRatingDeviation = displayRating(c.ReplayedRatingDeviation)
+	return json.Marshal(display)
+}
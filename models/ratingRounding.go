@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rounding modes for ratings shown in API responses
+const (
+	RoundingNearest = "nearest"
+	RoundingDown    = "down"
+	RoundingUp      = "up"
+	RoundingNone    = "none"
+)
+
+// RatingRounding is how ratings are rounded when they are written to an API response.
+// Stored ratings always keep full precision; rounding is never applied before a calculation.
+type RatingRounding struct {
+	Mode     string
+	Decimals int
+}
+
+// DisplayRounding is the rounding applied to every rating in API responses
+var DisplayRounding = RatingRounding{Mode: RoundingNearest, Decimals: 0}
+
+// NewRatingRounding validates a rounding mode and number of decimals. An empty mode means nearest.
+func NewRatingRounding(mode string, decimals int) (RatingRounding, error) {
+	if mode == "" {
+		mode = RoundingNearest
+	}
+	switch mode {
+	case RoundingNearest, RoundingDown, RoundingUp, RoundingNone:
+	default:
+		return RatingRounding{}, fmt.Errorf("unknown rating rounding mode %q", mode)
+	}
+	if decimals < 0 || decimals > 6 {
+		return RatingRounding{}, fmt.Errorf("rating rounding decimals must be between 0 and 6, got %d", decimals)
+	}
+	return RatingRounding{Mode: mode, Decimals: decimals}, nil
+}
+
+// Apply rounds a rating for display
+func (r RatingRounding) Apply(value float64) float64 {
+	scale := math.Pow(10, float64(r.Decimals))
+	switch r.Mode {
+	case RoundingNone:
+		return value
+	case RoundingDown:
+		return math.Floor(value*scale) / scale
+	case RoundingUp:
+		return math.Ceil(value*scale) / scale
+	default:
+		return math.Round(value*scale) / scale
+	}
+}
+
+// displayRating rounds a rating with the configured display rounding
+func displayRating(value float64) float64 {
+	return DisplayRounding.Apply(value)
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRatingRoundingApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		decimals int
+		value    float64
+		want     float64
+	}{
+		{name: "nearest rounds up", mode: RoundingNearest, value: 1516.5, want: 1517},
+		{name: "nearest rounds down", mode: RoundingNearest, value: 1516.49, want: 1516},
+		{name: "down", mode: RoundingDown, value: 1516.99, want: 1516},
+		{name: "up", mode: RoundingUp, value: 1516.01, want: 1517},
+		{name: "none keeps full precision", mode: RoundingNone, decimals: 2, value: 1516.123456, want: 1516.123456},
+		{name: "nearest to two decimals", mode: RoundingNearest, decimals: 2, value: 1516.126, want: 1516.13},
+		{name: "down to one decimal", mode: RoundingDown, decimals: 1, value: 1516.19, want: 1516.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounding, err := NewRatingRounding(tt.mode, tt.decimals)
+			if err != nil {
+				t.Fatalf("NewRatingRounding() error = %v", err)
+			}
+			if got := rounding.Apply(tt.value); got != tt.want {
+				t.Errorf("Apply(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRatingRoundingValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		decimals int
+		wantMode string
+		wantErr  bool
+	}{
+		{name: "empty mode means nearest", wantMode: RoundingNearest},
+		{name: "up", mode: RoundingUp, decimals: 3, wantMode: RoundingUp},
+		{name: "unknown mode", mode: "banker", wantErr: true},
+		{name: "negative decimals", mode: RoundingDown, decimals: -1, wantErr: true},
+		{name: "too many decimals", mode: RoundingDown, decimals: 7, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounding, err := NewRatingRounding(tt.mode, tt.decimals)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRatingRounding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && rounding.Mode != tt.wantMode {
+				t.Errorf("NewRatingRounding() mode = %q, want %q", rounding.Mode, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestAthleteScoreMarshalRoundsForDisplayOnly(t *testing.T) {
+	previous := DisplayRounding
+	defer func() { DisplayRounding = previous }()
+	DisplayRounding = RatingRounding{Mode: RoundingNearest, Decimals: 1}
+
+	score := AthleteScore{AthleteId: 1, Score: 1516.4567, RatingDeviation: 80.04, Volatility: 0.0600012}
+	body, err := json.Marshal(score)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var shown AthleteScore
+	if err := json.Unmarshal(body, &shown); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if shown.Score != 1516.5 || shown.RatingDeviation != 80 {
+		t.Errorf("response shows score %v and deviation %v, want 1516.5 and 80", shown.Score, shown.RatingDeviation)
+	}
+	if shown.Volatility != score.Volatility {
+		t.Errorf("response shows volatility %v, want it unrounded at %v", shown.Volatility, score.Volatility)
+	}
+	if score.Score != 1516.4567 {
+		t.Errorf("marshalling changed the stored score to %v", score.Score)
+	}
+}
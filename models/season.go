@@ -0,0 +1,40 @@
+package models
+
+import "encoding/json"
+
+// Season is a bounded rating period for one style. ClosedDate is nil until the
+// season has been closed, archived and its ratings soft-reset.
+type Season struct {
+	SeasonId    int     `json:"seasonId" db:"season_id"`
+	StyleId     int     `json:"styleId" db:"style_id"`
+	SeasonName  string  `json:"name" db:"season_name"`
+	StartDate   string  `json:"startDate" db:"start_dt"`
+	EndDate     string  `json:"endDate" db:"end_dt"`
+	ResetMean   float64 `json:"resetMean" db:"reset_mean"`
+	ResetWeight float64 `json:"resetWeight" db:"reset_weight"`
+	ClosedDate  *string `json:"closedDate" db:"closed_dt"`
+	CreatedDate string  `json:"createdDate" db:"created_dt"`
+	UpdatedDate string  `json:"updatedDate" db:"updated_dt"`
+}
+
+// SeasonStanding is an athlete's archived final rating for a closed season
+type SeasonStanding struct {
+	SeasonId        int     `json:"seasonId" db:"season_id"`
+	AthleteId       int     `json:"athleteId" db:"athlete_id"`
+	StyleId         int     `json:"styleId" db:"style_id"`
+	Rank            int     `json:"rank" db:"final_rank"`
+	Score           float64 `json:"score" db:"final_score"`
+	RatingDeviation float64 `json:"ratingDeviation" db:"rating_deviation"`
+	Volatility      float64 `json:"volatility" db:"volatility"`
+	RatedBouts      int     `json:"ratedBouts" db:"rated_bouts"`
+	CreatedDate     string  `json:"createdDate" db:"created_dt"`
+}
+
+// MarshalJSON writes the standing with display rounding applied
+func (s SeasonStanding) MarshalJSON() ([]byte, error) {
+	type seasonStanding SeasonStanding
+	display := seasonStanding(s)
+	display.Score = displayRating(s.Score)
+	display.RatingDeviation = displayRating(s.RatingDeviation)
+	return json.Marshal(display)
+}
@@ -3,6 +3,7 @@ package models
 type Style struct {
 	StyleId   int    `json:"styleId" db:"style_id"`
 	StyleName string `json:"name" db:"style_name"`
+	ChallengeExpiryHours int `json:"challengeExpiryHours" db:"challenge_expiry_hours"`
 	CreatedDate string `json:"createdDate" db:"created_dt"`
 	UpdatedDate string `json:"updatedDate" db:"updated_dt"`
 }
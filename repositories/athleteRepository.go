@@ -66,7 +66,10 @@ func (repo *AthleteRepository) GetAllAthletes() ([]models.Athlete, error) {
 func (repo *AthleteRepository) GetAthleteById(id string) (models.Athlete, error) {
 	var tempAthlete models.Athlete
 
-	sqlStmt := `SELECT * FROM athlete where athlete_id = $1`
+	sqlStmt := `SELECT a.*, COALESCE(o.overall_score, 0) AS overall_rating
+	FROM athlete AS a
+	LEFT JOIN athlete_overall_rating AS o ON o.athlete_id = a.athlete_id
+	WHERE a.athlete_id = $1`
 	err := repo.db.Get(&tempAthlete, sqlStmt, id)
 	if err != nil {
 		return tempAthlete, err
@@ -3,8 +3,10 @@ package repositories
 import (
 	"database/sql"
 	"ronin/models"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type AthleteScoreRepository struct {
@@ -19,6 +21,13 @@ func NewAthleteScoreRepository(db *sqlx.DB) *AthleteScoreRepository {
 
 type AthleteScoreService struct{}
 
+// Starting rating values given to an athlete when they register to a style
+const (
+	DefaultScore           = 400
+	DefaultRatingDeviation = 350.0
+	DefaultVolatility      = 0.06
+)
+
 func (repo *AthleteScoreRepository) GetAllAthleteScores() ([]models.AthleteScore, error) {
 	var athleteScores []models.AthleteScore
 	var tempAthleteScore models.AthleteScore
@@ -66,11 +75,14 @@ func (repo *AthleteScoreRepository) GetAllAthleteScoresByAthleteId(id string) ([
 		style_id,
 		COALESCE(outcome_id, 0) as outcome_id,
 		score,
+		COALESCE(rating_deviation, $2) as rating_deviation,
+		COALESCE(volatility, $3) as volatility,
+		provisional,
 		created_dt,
 		updated_dt
 	FROM athlete_score 
 	WHERE athlete_id = $1`
-	err := repo.DB.Select(&athleteScores, sqlStmt, id)
+	err := repo.DB.Select(&athleteScores, sqlStmt, id, DefaultRatingDeviation, DefaultVolatility)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +90,51 @@ func (repo *AthleteScoreRepository) GetAllAthleteScoresByAthleteId(id string) ([
 }
 
 func (repo *AthleteScoreRepository) GetAthleteScoreByStyle(id int, style int) (models.AthleteScore, error) {
+	return getAthleteScoreByStyle(repo.DB, id, style)
+}
+
+// LockStyleScores takes the style's rating lock inside a shared transaction. Everything that writes a
+// style's scores holds it, so a rating replay cannot rewrite the style while an outcome, decay or
+// season reset is being rated from the scores it is about to replace. Take it before locking any
+// other row so the lock order is the same everywhere.
+func (repo *AthleteScoreRepository) LockStyleScores(tx *Tx, styleId int) error {
+	return lockStyleScores(tx.tx, styleId)
+}
+
+// lockStyleScores locks the style row inside an open transaction. NO KEY UPDATE leaves new bouts,
+// outcomes and scores free to reference the style.
+func lockStyleScores(tx *sqlx.Tx, styleId int) error {
+	_, err := tx.Exec(`SELECT style_id FROM style WHERE style_id = $1 FOR NO KEY UPDATE`, styleId)
+	return err
+}
+
+// GetAthleteScoresByStyleForUpdate locks the style and the given athletes inside a shared transaction
+// and returns their current scores in it. athlete_score only ever gains rows, so the athlete rows are
+// what is locked, in ID order so two outcomes cannot deadlock. The scores are read once the locks are
+// held, and no other outcome can rate the same athletes until the transaction ends.
+func (repo *AthleteScoreRepository) GetAthleteScoresByStyleForUpdate(tx *Tx, style int, ids ...int) (map[int]models.AthleteScore, error) {
+	if err := lockStyleScores(tx.tx, style); err != nil {
+		return nil, err
+	}
+
+	_, err := tx.tx.Exec(`SELECT athlete_id FROM athlete WHERE athlete_id = ANY($1) ORDER BY athlete_id FOR NO KEY UPDATE`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int]models.AthleteScore, len(ids))
+	for _, id := range ids {
+		score, err := getAthleteScoreByStyle(tx.tx, id, style)
+		if err != nil {
+			return nil, err
+		}
+		scores[id] = score
+	}
+	return scores, nil
+}
+
+// getAthleteScoreByStyle reads an athlete's current score in a style through the database or an open transaction
+func getAthleteScoreByStyle(q sqlx.Queryer, id int, style int) (models.AthleteScore, error) {
 	var athleteScore models.AthleteScore
 
 	sqlStmt := `WITH ranked_scores AS (
@@ -85,6 +142,9 @@ func (repo *AthleteScoreRepository) GetAthleteScoreByStyle(id int, style int) (m
 			athlete_id,
 			style_id,
 			score,
+			rating_deviation,
+			volatility,
+			provisional,
 			updated_dt,
 			outcome_id,
 			ROW_NUMBER() OVER (PARTITION BY athlete_id, style_id ORDER BY updated_dt DESC) AS rank
@@ -97,12 +157,17 @@ func (repo *AthleteScoreRepository) GetAthleteScoreByStyle(id int, style int) (m
 		athlete_id,
 		style_id,
 		score,
+		COALESCE(rating_deviation, $3) AS rating_deviation,
+		COALESCE(volatility, $4) AS volatility,
+		(SELECT COUNT(*) FROM athlete_score_history h
+			WHERE h.athlete_id = $1 AND h.style_id = $2 AND h.outcome_id IS NOT NULL) AS rated_bouts,
+		provisional,
 		updated_dt
 	FROM
 		ranked_scores
 	WHERE
 		rank = 1`
-	err := repo.DB.QueryRowx(sqlStmt, id, style).StructScan(&athleteScore)
+	err := q.QueryRowx(sqlStmt, id, style, DefaultRatingDeviation, DefaultVolatility).StructScan(&athleteScore)
 	if err != nil {
 		return models.AthleteScore{}, err
 	}
@@ -110,39 +175,113 @@ func (repo *AthleteScoreRepository) GetAthleteScoreByStyle(id int, style int) (m
 	return athleteScore, nil
 }
 
-func (repo *AthleteScoreRepository) UpdateAthleteScore(score int, athleteId int, styleId int, outcomeId int) error {
+// UpdateAthleteScore appends a new current score and its history entry. An outcomeId of 0
+// records a change that is not tied to an outcome, such as inactivity decay.
+func (repo *AthleteScoreRepository) UpdateAthleteScore(score models.RatingChange, outcomeId int) error {
 	// Start transaction
 	tx, err := repo.DB.Beginx()
 	if err != nil {
 		return err
 	}
 
+	if err := lockStyleScores(tx, score.StyleId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := appendAthleteScore(tx, score, outcomeId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AppendAthleteScore writes a new current score and its history entry inside a shared transaction
+func (repo *AthleteScoreRepository) AppendAthleteScore(tx *Tx, score models.RatingChange, outcomeId int) error {
+	return appendAthleteScore(tx.tx, score, outcomeId)
+}
+
+// appendAthleteScore writes a new current score and its history entry inside an open transaction
+func appendAthleteScore(tx *sqlx.Tx, score models.RatingChange, outcomeId int) error {
 	// Get previous score
-	var previousScore int
-	err = tx.QueryRow(`SELECT score FROM athlete_score WHERE athlete_id = $1 AND style_id = $2 ORDER BY updated_dt DESC LIMIT 1`,
-		athleteId, styleId).Scan(&previousScore)
+	var previousScore, previousDeviation, previousVolatility float64
+	err := tx.QueryRow(`SELECT score, COALESCE(rating_deviation, $3), COALESCE(volatility, $4) FROM athlete_score
+		WHERE athlete_id = $1 AND style_id = $2 ORDER BY updated_dt DESC LIMIT 1`,
+		score.AthleteId, score.StyleId, DefaultRatingDeviation, DefaultVolatility).Scan(&previousScore, &previousDeviation, &previousVolatility)
 	if err != nil && err != sql.ErrNoRows {
-		tx.Rollback()
 		return err
 	}
 
 	// Insert into history
-	_, err = tx.Exec(`INSERT INTO athlete_score_history (athlete_id, style_id, outcome_id, previous_score, new_score) 
-		VALUES ($1, $2, $3, $4, $5)`, athleteId, styleId, outcomeId, previousScore, score)
+	_, err = tx.Exec(`INSERT INTO athlete_score_history (athlete_id, style_id, outcome_id, previous_score, new_score,
+		previous_rating_deviation, new_rating_deviation, previous_volatility, new_volatility, k_factor, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`, score.AthleteId, score.StyleId, nullableOutcomeId(outcomeId), previousScore, score.Score,
+		previousDeviation, score.RatingDeviation, previousVolatility, score.Volatility, nullableKFactor(score.KFactor), score.Reason)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
 	// Update current score
-	_, err = tx.Exec(`INSERT INTO athlete_score (score, athlete_id, style_id, outcome_id) VALUES ($1, $2, $3, $4)`,
-		score, athleteId, styleId, outcomeId)
+	_, err = tx.Exec(`INSERT INTO athlete_score (score, rating_deviation, volatility, provisional, athlete_id, style_id, outcome_id) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		score.Score, score.RatingDeviation, score.Volatility, score.Provisional, score.AthleteId, score.StyleId, nullableOutcomeId(outcomeId))
+	return err
+}
+
+// GetDecayHistoryByStyle returns every inactivity decay applied in a style, oldest first, read inside a shared transaction
+func (repo *AthleteScoreRepository) GetDecayHistoryByStyle(tx *Tx, styleId int) ([]models.AthleteScoreHistory, error) {
+	var history []models.AthleteScoreHistory
+	sqlStmt := `SELECT
+		history_id,
+		athlete_id,
+		style_id,
+		0 AS outcome_id,
+		COALESCE(previous_score, new_score) AS previous_score,
+		new_score,
+		COALESCE(previous_rating_deviation, 0) AS previous_rating_deviation,
+		COALESCE(new_rating_deviation, 0) AS new_rating_deviation,
+		COALESCE(previous_volatility, 0) AS previous_volatility,
+		COALESCE(new_volatility, 0) AS new_volatility,
+		0 AS k_factor,
+		reason,
+		created_dt,
+		updated_dt
+	FROM athlete_score_history
+	WHERE style_id = $1 AND reason = $2
+	ORDER BY created_dt, history_id`
+	err := tx.tx.Select(&history, sqlStmt, styleId, models.ScoreReasonDecay)
 	if err != nil {
-		tx.Rollback()
-		return err
+		return nil, err
 	}
+	return history, nil
+}
 
-	return tx.Commit()
+// GetAthleteScoreHistoryByStyle returns an athlete's rating changes in a style, oldest first
+func (repo *AthleteScoreRepository) GetAthleteScoreHistoryByStyle(athleteId int, styleId int) ([]models.AthleteScoreHistory, error) {
+	var history []models.AthleteScoreHistory
+	sqlStmt := `SELECT
+		history_id,
+		athlete_id,
+		style_id,
+		COALESCE(outcome_id, 0) AS outcome_id,
+		COALESCE(previous_score, 0) AS previous_score,
+		new_score,
+		COALESCE(previous_rating_deviation, 0) AS previous_rating_deviation,
+		COALESCE(new_rating_deviation, 0) AS new_rating_deviation,
+		COALESCE(previous_volatility, 0) AS previous_volatility,
+		COALESCE(new_volatility, 0) AS new_volatility,
+		COALESCE(k_factor, 0) AS k_factor,
+		COALESCE(reason, '') AS reason,
+		created_dt,
+		updated_dt
+	FROM athlete_score_history
+	WHERE athlete_id = $1 AND style_id = $2
+	ORDER BY created_dt, history_id`
+	err := repo.DB.Select(&history, sqlStmt, athleteId, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
 }
 
 func (repo *AthleteScoreRepository) CreateAthleteScoreUponRegistration(athleteId int, styleId int) error {
@@ -152,16 +291,16 @@ func (repo *AthleteScoreRepository) CreateAthleteScoreUponRegistration(athleteId
 	}
 
 	// Insert initial score
-	_, err = tx.Exec(`INSERT INTO athlete_score (athlete_id, style_id, score) VALUES ($1, $2, $3)`,
-		athleteId, styleId, 400)
+	_, err = tx.Exec(`INSERT INTO athlete_score (athlete_id, style_id, score, rating_deviation, volatility) VALUES ($1, $2, $3, $4, $5)`,
+		athleteId, styleId, DefaultScore, DefaultRatingDeviation, DefaultVolatility)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	// Record in history
-	_, err = tx.Exec(`INSERT INTO athlete_score_history (athlete_id, style_id, previous_score, new_score) 
-		VALUES ($1, $2, NULL, $3)`, athleteId, styleId, 400)
+	_, err = tx.Exec(`INSERT INTO athlete_score_history (athlete_id, style_id, previous_score, new_score, new_rating_deviation, new_volatility, reason) 
+		VALUES ($1, $2, NULL, $3, $4, $5, $6)`, athleteId, styleId, DefaultScore, DefaultRatingDeviation, DefaultVolatility, models.ScoreReasonRegistration)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -169,3 +308,234 @@ func (repo *AthleteScoreRepository) CreateAthleteScoreUponRegistration(athleteId
 
 	return tx.Commit()
 }
+
+// GetLatestScoresByStyle returns the current score of every athlete rated in a style
+func (repo *AthleteScoreRepository) GetLatestScoresByStyle(styleId int) ([]models.AthleteScore, error) {
+	return getLatestScoresByStyle(repo.DB, styleId)
+}
+
+// GetStyleScoresForReplay returns the current score of every athlete rated in a style like
+// GetLatestScoresByStyle, read inside a shared transaction
+func (repo *AthleteScoreRepository) GetStyleScoresForReplay(tx *Tx, styleId int) ([]models.AthleteScore, error) {
+	return getLatestScoresByStyle(tx.tx, styleId)
+}
+
+// getLatestScoresByStyle reads the current scores of a style through the database or an open transaction
+func getLatestScoresByStyle(q sqlx.Queryer, styleId int) ([]models.AthleteScore, error) {
+	var athleteScores []models.AthleteScore
+	sqlStmt := `WITH ranked_scores AS (
+		SELECT
+			athlete_id,
+			style_id,
+			outcome_id,
+			score,
+			rating_deviation,
+			volatility,
+			provisional,
+			created_dt,
+			updated_dt,
+			ROW_NUMBER() OVER (PARTITION BY athlete_id, style_id ORDER BY updated_dt DESC) AS rank
+		FROM
+			athlete_score
+		WHERE
+			style_id = $1
+	)
+	SELECT
+		athlete_id,
+		style_id,
+		COALESCE(outcome_id, 0) AS outcome_id,
+		score,
+		COALESCE(rating_deviation, $2) AS rating_deviation,
+		COALESCE(volatility, $3) AS volatility,
+		provisional,
+		created_dt,
+		updated_dt
+	FROM
+		ranked_scores
+	WHERE
+		rank = 1
+	ORDER BY athlete_id`
+	err := sqlx.Select(q, &athleteScores, sqlStmt, styleId, DefaultRatingDeviation, DefaultVolatility)
+	if err != nil {
+		return nil, err
+	}
+	return athleteScores, nil
+}
+
+// GetStyleRatingSeeds returns every athlete that needs a starting score in a style, dated just
+// before the first time they appear in the style's scores or outcomes, read inside a shared transaction
+func (repo *AthleteScoreRepository) GetStyleRatingSeeds(tx *Tx, styleId int) ([]models.AthleteScore, error) {
+	var seeds []models.AthleteScore
+	sqlStmt := `SELECT
+		athlete_id,
+		$1::int AS style_id,
+		MIN(seeded_dt) AS created_dt,
+		MIN(seeded_dt) AS updated_dt
+	FROM (
+		SELECT athlete_id, created_dt AS seeded_dt FROM athlete_style WHERE style_id = $1
+		UNION ALL
+		SELECT athlete_id, created_dt FROM athlete_score WHERE style_id = $1
+		UNION ALL
+		SELECT winner_id, created_dt - interval '1 second' FROM outcome WHERE style_id = $1 AND winner_id IS NOT NULL
+		UNION ALL
+		SELECT loser_id, created_dt - interval '1 second' FROM outcome WHERE style_id = $1 AND loser_id IS NOT NULL
+	) AS seeds
+	GROUP BY athlete_id
+	ORDER BY athlete_id`
+	err := tx.tx.Select(&seeds, sqlStmt, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return seeds, nil
+}
+
+// RewriteStyleScores wipes the derived score rows for a style and writes the given rows in their place,
+// inside a shared transaction that holds the style's rating lock. Rows must be in chronological order;
+// each row becomes one athlete_score row and one history entry.
+func (repo *AthleteScoreRepository) RewriteStyleScores(tx *Tx, styleId int, scores []models.RatingChange) error {
+	return replaceStyleScores(tx.tx, styleId, scores)
+}
+
+// replaceStyleScores wipes and rewrites a style's score rows inside an open transaction
+func replaceStyleScores(tx *sqlx.Tx, styleId int, scores []models.RatingChange) error {
+	_, err := tx.Exec(`DELETE FROM athlete_score_history WHERE style_id = $1`, styleId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM athlete_score WHERE style_id = $1`, styleId)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[int]models.RatingChange)
+	for _, score := range scores {
+		outcomeId := nullableOutcomeId(score.OutcomeId)
+
+		var previousScore, previousDeviation, previousVolatility interface{}
+		if prior, ok := previous[score.AthleteId]; ok {
+			previousScore = prior.Score
+			previousDeviation = prior.RatingDeviation
+			previousVolatility = prior.Volatility
+		}
+
+		_, err = tx.Exec(`INSERT INTO athlete_score_history (athlete_id, style_id, outcome_id, previous_score, new_score,
+			previous_rating_deviation, new_rating_deviation, previous_volatility, new_volatility, k_factor, reason, created_dt, updated_dt)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)`, score.AthleteId, styleId, outcomeId, previousScore, score.Score,
+			previousDeviation, score.RatingDeviation, previousVolatility, score.Volatility, nullableKFactor(score.KFactor), score.Reason, score.UpdatedDate)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO athlete_score (score, rating_deviation, volatility, provisional, athlete_id, style_id, outcome_id, created_dt, updated_dt)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)`,
+			score.Score, score.RatingDeviation, score.Volatility, score.Provisional, score.AthleteId, styleId, outcomeId, score.UpdatedDate)
+		if err != nil {
+			return err
+		}
+
+		previous[score.AthleteId] = score
+	}
+	return nil
+}
+
+// GetInactiveScores returns the current score of every athlete in a style whose last outcome was
+// before inactiveSince and who has not had decay applied since decayedSince
+func (repo *AthleteScoreRepository) GetInactiveScores(styleId int, inactiveSince, decayedSince time.Time) ([]models.AthleteScore, error) {
+	var athleteScores []models.AthleteScore
+	sqlStmt := `WITH ranked_scores AS (
+		SELECT
+			athlete_id,
+			style_id,
+			score,
+			rating_deviation,
+			volatility,
+			provisional,
+			created_dt,
+			updated_dt,
+			ROW_NUMBER() OVER (PARTITION BY athlete_id, style_id ORDER BY updated_dt DESC) AS rank
+		FROM
+			athlete_score
+		WHERE
+			style_id = $1
+	),
+	last_outcome AS (
+		SELECT athlete_id, MAX(created_dt) AS last_dt
+		FROM (
+			SELECT winner_id AS athlete_id, created_dt FROM outcome WHERE style_id = $1
+			UNION ALL
+			SELECT loser_id, created_dt FROM outcome WHERE style_id = $1
+		) AS participants
+		WHERE athlete_id IS NOT NULL
+		GROUP BY athlete_id
+	),
+	last_decay AS (
+		SELECT athlete_id, MAX(created_dt) AS last_dt
+		FROM athlete_score_history
+		WHERE style_id = $1 AND reason = $4
+		GROUP BY athlete_id
+	)
+	SELECT
+		rs.athlete_id,
+		rs.style_id,
+		rs.score,
+		COALESCE(rs.rating_deviation, $5) AS rating_deviation,
+		COALESCE(rs.volatility, $6) AS volatility,
+		rs.provisional,
+		rs.created_dt,
+		rs.updated_dt
+	FROM
+		ranked_scores rs
+	JOIN
+		last_outcome lo ON lo.athlete_id = rs.athlete_id
+	LEFT JOIN
+		last_decay ld ON ld.athlete_id = rs.athlete_id
+	WHERE
+		rs.rank = 1
+		AND lo.last_dt < $2
+		AND (ld.last_dt IS NULL OR ld.last_dt < lo.last_dt OR ld.last_dt < $3)
+	ORDER BY rs.athlete_id`
+	err := repo.DB.Select(&athleteScores, sqlStmt, styleId, inactiveSince, decayedSince,
+		models.ScoreReasonDecay, DefaultRatingDeviation, DefaultVolatility)
+	if err != nil {
+		return nil, err
+	}
+	return athleteScores, nil
+}
+
+// nullableOutcomeId stores NULL for rating changes that are not tied to an outcome
+func nullableOutcomeId(outcomeId int) interface{} {
+	if outcomeId == 0 {
+		return nil
+	}
+	return outcomeId
+}
+
+// nullableKFactor stores NULL for rating changes that did not come from a K-factor
+func nullableKFactor(kFactor float64) interface{} {
+	if kFactor == 0 {
+		return nil
+	}
+	return kFactor
+}
+
+// GetRematchHistory counts the outcomes between two athletes in a style that changed their ratings
+// since the start of the rematch period, and returns when the last of them was confirmed
+func (repo *AthleteScoreRepository) GetRematchHistory(athleteA int, athleteB int, styleId int, since time.Time) (models.RematchHistory, error) {
+	var history models.RematchHistory
+	sqlStmt := `SELECT
+		COUNT(*) FILTER (WHERE o.confirmed_dt >= $4) AS rated_in_period,
+		MAX(o.confirmed_dt) AS last_rated_at
+	FROM outcome o
+	WHERE o.style_id = $3
+		AND ((o.winner_id = $1 AND o.loser_id = $2) OR (o.winner_id = $2 AND o.loser_id = $1))
+		AND EXISTS (
+			SELECT 1 FROM athlete_score_history h
+			WHERE h.outcome_id = o.outcome_id AND h.reason = 'outcome'
+		)`
+	err := repo.DB.QueryRowx(sqlStmt, athleteA, athleteB, styleId, since).StructScan(&history)
+	if err != nil {
+		return models.RematchHistory{}, err
+	}
+	return history, nil
+}
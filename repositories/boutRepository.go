@@ -1,11 +1,19 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"ronin/models"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// ErrBoutStatusChanged is returned when a bout's status changed between reading and updating it
+var ErrBoutStatusChanged = errors.New("bout status changed concurrently")
+
 type BoutRepository struct {
 	DB *sqlx.DB
 }
@@ -16,22 +24,123 @@ func NewBoutRepository(db *sqlx.DB) *BoutRepository {
 	}
 }
 
-func (repo *BoutRepository) GetAllBouts() ([]models.Bout, error) {
-	var bouts = models.GetBouts()
-	sqlStmt := `SELECT * FROM bout`
-	rows, err := repo.DB.Queryx(sqlStmt)
-	if err != nil {
-		return nil, err
-	} else {
-		for rows.Next() {
-			var tempBout models.Bout
-			err = rows.StructScan(&tempBout)
-			if err != nil {
-				return nil, err
-			}
-			bouts = append(bouts, tempBout)
+// boutSortColumns maps listing sort keys to the columns they order by
+var boutSortColumns = map[string]string{
+	models.BoutSortCreated: "b.created_dt",
+	models.BoutSortUpdated: "b.updated_dt",
+	models.BoutSortId:      "b.bout_id",
+}
+
+// ListBouts returns up to limit bouts matching a filter in a single query, ordered by the filter's
+// sort key with the bout ID as tie-breaker, starting after the filter's cursor
+func (repo *BoutRepository) ListBouts(filter models.BoutListFilter, limit int) ([]models.OutboundBout, error) {
+	sortColumn, ok := boutSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown bout sort %q", filter.Sort)
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.AthleteId != 0 {
+		id := arg(filter.AthleteId)
+		conditions = append(conditions, fmt.Sprintf("(b.challenger_id = %s OR b.acceptor_id = %s)", id, id))
+	}
+	if filter.StyleId != 0 {
+		conditions = append(conditions, "b.style_id = "+arg(filter.StyleId))
+	}
+	if filter.RefereeId != 0 {
+		conditions = append(conditions, "b.referee_id = "+arg(filter.RefereeId))
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "b.status = ANY("+arg(pq.Array(filter.Statuses))+")")
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "b.created_dt >= "+arg(filter.From.UTC())+"::timestamp")
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "b.created_dt < "+arg(filter.To.UTC())+"::timestamp")
+	}
+	if filter.After != nil {
+		if filter.Sort == models.BoutSortId {
+			conditions = append(conditions, fmt.Sprintf("b.bout_id %s %s", comparison, arg(filter.After.BoutId)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s, b.bout_id) %s (%s::timestamp, %s)",
+				sortColumn, comparison, arg(filter.After.Value), arg(filter.After.BoutId)))
 		}
 	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	orderBy := fmt.Sprintf("%s %s, b.bout_id %s", sortColumn, direction, direction)
+	if filter.Sort == models.BoutSortId {
+		orderBy = "b.bout_id " + direction
+	}
+
+	sqlStmt := fmt.Sprintf(`WITH latest_scores AS (
+		SELECT DISTINCT ON (athlete_id, style_id) athlete_id, style_id, score
+		FROM athlete_score
+		ORDER BY athlete_id, style_id, updated_dt DESC
+	)
+	SELECT 
+		b.bout_id AS "boutId",
+		b.challenger_id AS "challengerId",
+		c.first_name AS "challengerFirstName",
+		c.last_name AS "challengerLastName",
+		s.style_name AS "style",
+		s.style_id AS "styleId",
+		COALESCE(cs.score, 0) AS "challengerScore",
+		b.acceptor_id AS "acceptorId",
+		a.first_name AS "acceptorFirstName",
+		a.last_name AS "acceptorLastName",
+		COALESCE(ascore.score, 0) AS "acceptorScore",
+		r.athlete_id AS "refereeId",
+		r.first_name AS "refereeFirstName",
+		r.last_name AS "refereeLastName",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
+	FROM 
+		bout b
+	JOIN 
+		athlete c ON b.challenger_id = c.athlete_id
+	JOIN 
+		athlete a ON b.acceptor_id = a.athlete_id
+	LEFT JOIN 
+		latest_scores cs ON b.challenger_id = cs.athlete_id AND b.style_id = cs.style_id
+	LEFT JOIN 
+		latest_scores ascore ON b.acceptor_id = ascore.athlete_id AND b.style_id = ascore.style_id
+	JOIN 
+		athlete r ON b.referee_id = r.athlete_id
+	JOIN 
+		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
+	%s
+	ORDER BY %s
+	LIMIT %s`, where, orderBy, arg(limit))
+
+	var bouts []models.OutboundBout
+	if err := repo.DB.Select(&bouts, sqlStmt, args...); err != nil {
+		return nil, err
+	}
 	return bouts, nil
 }
 
@@ -45,9 +154,48 @@ func (repo *BoutRepository) GetBoutById(id string) (models.Bout, error) {
 	return bout, nil
 }
 
+// CreateBout inserts a proposed bout and records its initial status with the challenger as actor.
+// The challenge expires after bout.ExpiresInHours, or the style's default when that is zero.
 func (repo *BoutRepository) CreateBout(bout models.Bout) (int, error) {
-	sqlStmt := `INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, accepted, completed, cancelled, points) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING bout_id`
-	err := repo.DB.QueryRowx(sqlStmt, bout.ChallengerId, bout.AcceptorId, bout.RefereeId, bout.StyleId, bout.Accepted, bout.Completed, bout.Cancelled, bout.Points).Scan(&bout.BoutId)
+	tx, err := repo.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	boutId, err := insertBout(tx, bout)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return boutId, nil
+}
+
+// insertBout adds a proposed bout with its initial status history and first proposal round inside an existing transaction
+func insertBout(tx *sqlx.Tx, bout models.Bout) (int, error) {
+	sqlStmt := `INSERT INTO bout (challenger_id, acceptor_id, referee_id, style_id, status, responder_id, points, expires_dt, scheduled_at, duration_minutes, gym_id, rated)
+	SELECT $1, $2, $3, s.style_id, $5, $2, $6, now() + make_interval(hours => COALESCE(NULLIF($7::int, 0), s.challenge_expiry_hours)), $8, $9, $10, $11
+	FROM style s
+	WHERE s.style_id = $4
+	RETURNING bout_id`
+	err := tx.QueryRowx(sqlStmt, bout.ChallengerId, bout.AcceptorId, bout.RefereeId, bout.StyleId, models.BoutStatusProposed, bout.Points, bout.ExpiresInHours,
+		bout.ScheduledAt, bout.DurationMinutes, bout.GymId, bout.Rated).Scan(&bout.BoutId)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO bout_status_history (bout_id, from_status, to_status, actor_id) VALUES ($1, NULL, $2, $3)`,
+		bout.BoutId, models.BoutStatusProposed, bout.ChallengerId)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO bout_proposal (bout_id, round, proposed_by, style_id, referee_id, scheduled_at, duration_minutes, gym_id)
+	VALUES ($1, 1, $2, $3, $4, $5, $6, $7)`,
+		bout.BoutId, bout.ChallengerId, bout.StyleId, bout.RefereeId, bout.ScheduledAt, bout.DurationMinutes, bout.GymId)
 	if err != nil {
 		return 0, err
 	}
@@ -71,7 +219,17 @@ func (repo *BoutRepository) GetOutboundBoutByBoutId(id int) (models.OutboundBout
 		r.athlete_id AS "refereeId",
 		r.first_name AS "refereeFirstName",
 		r.last_name AS "refereeLastName",
-		s.style_id AS "styleId"
+		s.style_id AS "styleId",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
 	FROM 
 		bout b
 	JOIN 
@@ -86,6 +244,8 @@ func (repo *BoutRepository) GetOutboundBoutByBoutId(id int) (models.OutboundBout
 		athlete r ON b.referee_id = r.athlete_id
 	JOIN 
 		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
 	WHERE 
 		b.bout_id = $1;`
 
@@ -98,8 +258,12 @@ func (repo *BoutRepository) GetOutboundBoutByBoutId(id int) (models.OutboundBout
 }
 
 func (repo *BoutRepository) UpdateBout(id string, bout models.Bout) error {
-	sqlStmt := `UPDATE bout SET challenger_id = $1, acceptor_id = $2, referee_id =$3, accepted = $4, points = $5, style_id = $6 WHERE bout_id = $7`
-	_, err := repo.DB.Exec(sqlStmt, bout.ChallengerId, bout.AcceptorId, bout.RefereeId, bout.Accepted, bout.Points, bout.StyleId, id)
+	sqlStmt := `UPDATE bout SET challenger_id = $1, acceptor_id = $2, referee_id = $3, points = $4, style_id = $5,
+		scheduled_at = $7, duration_minutes = $8, gym_id = $9, rated = $10,
+		responder_id = CASE WHEN responder_id = challenger_id THEN $1 ELSE $2 END
+	WHERE bout_id = $6`
+	_, err := repo.DB.Exec(sqlStmt, bout.ChallengerId, bout.AcceptorId, bout.RefereeId, bout.Points, bout.StyleId, id,
+		bout.ScheduledAt, bout.DurationMinutes, bout.GymId, bout.Rated)
 	if err != nil {
 		return err
 	}
@@ -107,48 +271,332 @@ func (repo *BoutRepository) UpdateBout(id string, bout models.Bout) error {
 }
 
 func (repo *BoutRepository) DeleteBout(id string) error {
-	sqlStmt := `DELETE FROM bout WHERE bout_id = $1`
-	_, err := repo.DB.Exec(sqlStmt, id)
+	tx, err := repo.DB.Beginx()
 	if err != nil {
 		return err
 	}
-	return nil
+
+	_, err = tx.Exec(`DELETE FROM bout_status_history WHERE bout_id = $1`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM notification WHERE bout_id = $1`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM bout_proposal WHERE bout_id = $1`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE open_challenge SET bout_id = NULL WHERE bout_id = $1`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM bout WHERE bout_id = $1`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *BoutRepository) AcceptBout(id string) error {
-	sqlStmt := `UPDATE bout SET accepted = true WHERE bout_id = $1`
-	_, err := repo.DB.Exec(sqlStmt, id)
+// TransitionBoutStatus moves a bout from one status to another and records the change.
+// It returns ErrBoutStatusChanged if the bout is no longer in the from status.
+func (repo *BoutRepository) TransitionBoutStatus(boutId int, fromStatus, toStatus string, actorId int) error {
+	tx, err := repo.DB.Beginx()
 	if err != nil {
 		return err
 	}
-	return nil
+
+	if err := transitionBoutStatus(tx, boutId, fromStatus, toStatus, actorId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *BoutRepository) DeclineBout(id string) error {
-	sqlStmt := `UPDATE bout SET accepted = false, completed = true WHERE bout_id = $1`
-	_, err := repo.DB.Exec(sqlStmt, id)
+// CompleteBout moves a bout from fromStatus to completed inside a shared transaction. It returns
+// ErrBoutStatusChanged if the bout is no longer in fromStatus.
+func (repo *BoutRepository) CompleteBout(tx *Tx, boutId int, fromStatus string, actorId int) error {
+	return transitionBoutStatus(tx.tx, boutId, fromStatus, models.BoutStatusCompleted, actorId)
+}
+
+// VoidBout moves a completed or disputed bout to voided inside a shared transaction. It returns
+// ErrBoutStatusChanged if the bout is no longer in fromStatus.
+func (repo *BoutRepository) VoidBout(tx *Tx, boutId int, fromStatus string, actorId int) error {
+	return transitionBoutStatus(tx.tx, boutId, fromStatus, models.BoutStatusVoided, actorId)
+}
+
+// DisputeBout moves a completed bout to disputed inside a shared transaction. It returns
+// ErrBoutStatusChanged if the bout is no longer completed.
+func (repo *BoutRepository) DisputeBout(tx *Tx, boutId int, actorId int) error {
+	return transitionBoutStatus(tx.tx, boutId, models.BoutStatusCompleted, models.BoutStatusDisputed, actorId)
+}
+
+// ExpireChallenge moves a proposed bout to expired and notifies the athlete still waiting on an
+// answer in the same transaction. It returns ErrBoutStatusChanged if the bout is no longer proposed.
+func (repo *BoutRepository) ExpireChallenge(bout models.Bout, message string) error {
+	tx, err := repo.DB.Beginx()
 	if err != nil {
 		return err
 	}
-	return nil
+
+	if err := transitionBoutStatus(tx, bout.BoutId, models.BoutStatusProposed, models.BoutStatusExpired, 0); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	proposer := bout.ChallengerId
+	if bout.ResponderId == bout.ChallengerId {
+		proposer = bout.AcceptorId
+	}
+
+	err = insertNotification(tx, models.Notification{
+		AthleteId: proposer,
+		BoutId:    &bout.BoutId,
+		Kind:      models.NotificationChallengeExpired,
+		Message:   message,
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *BoutRepository) CompleteBoutByBoutId(boutId string) error {
-	sqlStmt := `UPDATE bout SET completed = true WHERE bout_id = $1`
-	_, err := repo.DB.Exec(sqlStmt, boutId)
+// CounterBout replaces the terms of a proposed bout with a counter-proposal from its current
+// responder, records the new round, hands the turn to the other athlete and notifies them, all in
+// one transaction. The challenge expiry restarts from the style's default. It returns
+// ErrBoutStatusChanged if the bout is no longer proposed or it is no longer the athlete's turn.
+func (repo *BoutRepository) CounterBout(bout models.Bout, proposedBy int, nextResponder int, message string) error {
+	tx, err := repo.DB.Beginx()
 	if err != nil {
 		return err
 	}
-	return nil
+
+	result, err := tx.Exec(`UPDATE bout SET style_id = $4, referee_id = $5, scheduled_at = $6, duration_minutes = $7, gym_id = $8,
+		rated = $9, responder_id = $3,
+		expires_dt = now() + make_interval(hours => (SELECT challenge_expiry_hours FROM style WHERE style_id = $4))
+	WHERE bout_id = $1 AND status = 'proposed' AND responder_id = $2`,
+		bout.BoutId, proposedBy, nextResponder, bout.StyleId, bout.RefereeId, bout.ScheduledAt, bout.DurationMinutes, bout.GymId, bout.Rated)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rows == 0 {
+		tx.Rollback()
+		return ErrBoutStatusChanged
+	}
+
+	_, err = tx.Exec(`INSERT INTO bout_proposal (bout_id, round, proposed_by, style_id, referee_id, scheduled_at, duration_minutes, gym_id)
+	SELECT $1, COALESCE(MAX(round), 0) + 1, $2, $3, $4, $5, $6, $7 FROM bout_proposal WHERE bout_id = $1`,
+		bout.BoutId, proposedBy, bout.StyleId, bout.RefereeId, bout.ScheduledAt, bout.DurationMinutes, bout.GymId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = insertNotification(tx, models.Notification{
+		AthleteId: nextResponder,
+		BoutId:    &bout.BoutId,
+		Kind:      models.NotificationBoutCountered,
+		Message:   message,
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetBoutProposals returns every round of terms offered for a bout, first round first
+func (repo *BoutRepository) GetBoutProposals(boutId int) ([]models.BoutProposal, error) {
+	var proposals []models.BoutProposal
+	err := repo.DB.Select(&proposals, `SELECT * FROM bout_proposal WHERE bout_id = $1 ORDER BY round`, boutId)
+	if err != nil {
+		return nil, err
+	}
+	return proposals, nil
 }
 
-func (repo *BoutRepository) CompleteBout(boutId, refereeId string) error {
-	sqlStmt := `UPDATE bout SET completed = true WHERE bout_id = $1 and referee_id = $2`
-	_, err := repo.DB.Exec(sqlStmt, boutId, refereeId)
+// transitionBoutStatus applies a conditional status change and its history row inside an existing transaction
+func transitionBoutStatus(tx *sqlx.Tx, boutId int, fromStatus, toStatus string, actorId int) error {
+	result, err := tx.Exec(`UPDATE bout SET status = $3 WHERE bout_id = $1 AND status = $2`, boutId, fromStatus, toStatus)
 	if err != nil {
 		return err
 	}
-	return nil
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBoutStatusChanged
+	}
+
+	_, err = tx.Exec(`INSERT INTO bout_status_history (bout_id, from_status, to_status, actor_id) VALUES ($1, $2, $3, $4)`,
+		boutId, fromStatus, toStatus, nullableAthleteId(actorId))
+	return err
+}
+
+// ScheduleBout sets when and where a bout takes place and moves it from one status to another
+// in the same transaction. When fromStatus and toStatus match only the schedule changes. It
+// returns ErrBoutStatusChanged if the bout is no longer in fromStatus.
+func (repo *BoutRepository) ScheduleBout(boutId int, fromStatus, toStatus string, schedule models.BoutSchedule, actorId int) error {
+	tx, err := repo.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`UPDATE bout SET scheduled_at = $3, duration_minutes = $4, gym_id = $5 WHERE bout_id = $1 AND status = $2`,
+		boutId, fromStatus, schedule.ScheduledAt, schedule.DurationMinutes, schedule.GymId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rows == 0 {
+		tx.Rollback()
+		return ErrBoutStatusChanged
+	}
+
+	if fromStatus != toStatus {
+		if err := transitionBoutStatus(tx, boutId, fromStatus, toStatus, actorId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetScheduleConflict returns an open bout, other than excludeBoutId, that involves any of the given
+// athletes in any role and overlaps the window starting at start. It returns sql.ErrNoRows if there is none.
+func (repo *BoutRepository) GetScheduleConflict(excludeBoutId int, athleteIds []int, start time.Time, durationMinutes int) (models.Bout, error) {
+	var bout models.Bout
+	sqlStmt := `SELECT * FROM bout
+	WHERE bout_id <> $1
+		AND status IN ('proposed', 'accepted', 'scheduled', 'in_progress')
+		AND scheduled_at IS NOT NULL
+		AND scheduled_at < $3::timestamptz + make_interval(mins => $4)
+		AND scheduled_at + make_interval(mins => duration_minutes) > $3::timestamptz
+		AND (challenger_id = ANY($2) OR acceptor_id = ANY($2) OR referee_id = ANY($2))
+	ORDER BY scheduled_at
+	LIMIT 1`
+	err := repo.DB.QueryRowx(sqlStmt, excludeBoutId, pq.Array(athleteIds), start, durationMinutes).StructScan(&bout)
+	if err != nil {
+		return models.Bout{}, err
+	}
+	return bout, nil
+}
+
+// GetUpcomingBoutsByAthleteId returns the accepted bouts with a start time that an athlete takes part
+// in and that have not finished yet, soonest first
+func (repo *BoutRepository) GetUpcomingBoutsByAthleteId(athleteId string) ([]models.OutboundBout, error) {
+	var bouts []models.OutboundBout
+	sqlStmt := `SELECT 
+		b.bout_id AS "boutId",
+		b.challenger_id AS "challengerId",
+		c.first_name AS "challengerFirstName",
+		c.last_name AS "challengerLastName",
+		s.style_name AS "style",
+		s.style_id AS "styleId",
+		COALESCE(cs.score, 0) AS "challengerScore",
+		b.acceptor_id AS "acceptorId",
+		a.first_name AS "acceptorFirstName",
+		a.last_name AS "acceptorLastName",
+		COALESCE(ascore.score, 0) AS "acceptorScore",
+		r.athlete_id AS "refereeId",
+		r.first_name AS "refereeFirstName",
+		r.last_name AS "refereeLastName",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
+	FROM 
+		bout b
+	JOIN 
+		athlete c ON b.challenger_id = c.athlete_id
+	JOIN 
+		athlete a ON b.acceptor_id = a.athlete_id
+	LEFT JOIN 
+		athlete_score cs ON b.challenger_id = cs.athlete_id AND b.style_id = cs.style_id
+	LEFT JOIN 
+		athlete_score ascore ON b.acceptor_id = ascore.athlete_id AND b.style_id = ascore.style_id
+	JOIN 
+		athlete r ON b.referee_id = r.athlete_id
+	JOIN 
+		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
+	WHERE 
+		b.status IN ('accepted', 'scheduled', 'in_progress')
+		AND b.scheduled_at + make_interval(mins => b.duration_minutes) > now()
+		AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = $1)
+	ORDER BY b.scheduled_at, b.bout_id`
+
+	err := repo.DB.Select(&bouts, sqlStmt, athleteId)
+	if err != nil {
+		return nil, err
+	}
+	return bouts, nil
+}
+
+// GetExpiredChallenges returns proposed bouts whose expiry time has passed, oldest first
+func (repo *BoutRepository) GetExpiredChallenges() ([]models.Bout, error) {
+	var bouts []models.Bout
+	sqlStmt := `SELECT * FROM bout WHERE status = 'proposed' AND expires_dt <= now() ORDER BY expires_dt, bout_id`
+	err := repo.DB.Select(&bouts, sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	return bouts, nil
+}
+
+// GetBoutStatusHistory returns every status change of a bout, oldest first
+func (repo *BoutRepository) GetBoutStatusHistory(boutId int) ([]models.BoutStatusHistory, error) {
+	var history []models.BoutStatusHistory
+	sqlStmt := `SELECT
+		history_id,
+		bout_id,
+		COALESCE(from_status, '') AS from_status,
+		to_status,
+		COALESCE(actor_id, 0) AS actor_id,
+		created_dt
+	FROM bout_status_history
+	WHERE bout_id = $1
+	ORDER BY created_dt, history_id`
+	err := repo.DB.Select(&history, sqlStmt, boutId)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
 }
 
 func (repo *BoutRepository) GetPendingBoutsByAthleteId(id string) ([]models.OutboundBout, error) {
@@ -176,7 +624,17 @@ func (repo *BoutRepository) GetPendingBoutsByAthleteId(id string) ([]models.Outb
 		ascore.score AS "acceptorScore",
 		r.athlete_id AS "refereeId",
 		r.first_name AS "refereeFirstName",
-		r.last_name AS "refereeLastName"
+		r.last_name AS "refereeLastName",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
 	FROM 
 		bout b
 	JOIN 
@@ -191,8 +649,12 @@ func (repo *BoutRepository) GetPendingBoutsByAthleteId(id string) ([]models.Outb
 		athlete r ON b.referee_id = r.athlete_id
 	JOIN 
 		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
 	WHERE 
-		b.accepted = false AND b.cancelled = false AND b.completed = false AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = 6)`
+		b.status = 'proposed'
+		AND (b.expires_dt IS NULL OR b.expires_dt > now())
+		AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = $1)`
 
 	rows, err := repo.DB.Queryx(sqlStmt, id)
 	if err != nil {
@@ -226,7 +688,17 @@ func (repo *BoutRepository) GetIncompleteBoutsByAthleteId(athleteId string) ([]m
 		COALESCE(ascore.score, 0) AS "acceptorScore",
 		r.athlete_id AS "refereeId",
 		r.first_name AS "refereeFirstName",
-		r.last_name AS "refereeLastName"
+		r.last_name AS "refereeLastName",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
 	FROM 
 		bout b
 	JOIN 
@@ -241,10 +713,10 @@ func (repo *BoutRepository) GetIncompleteBoutsByAthleteId(athleteId string) ([]m
 		athlete r ON b.referee_id = r.athlete_id
 	JOIN 
 		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
 	WHERE 
-		b.accepted = true 
-		AND b.cancelled = false 
-		AND b.completed = false 
+		b.status IN ('accepted', 'scheduled', 'in_progress')
 		AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = $1)`
 
 	rows, err := repo.DB.Queryx(sqlStmt, athleteId)
@@ -287,7 +759,17 @@ func (repo *BoutRepository) GetCompletedBoutsByAthleteId(athleteId string) ([]mo
 		ascore.score AS "acceptorScore",
 		r.athlete_id AS "refereeId",
 		r.first_name AS "refereeFirstName",
-		r.last_name AS "refereeLastName"
+		r.last_name AS "refereeLastName",
+		b.status AS "status",
+		b.responder_id AS "responderId",
+		b.expires_dt AS "expiresDate",
+		b.scheduled_at AS "scheduledAt",
+		b.duration_minutes AS "durationMinutes",
+		b.gym_id AS "gymId",
+		g.gym_name AS "gymName",
+		b.rated AS "rated",
+		b.created_dt AS "createdDate",
+		b.updated_dt AS "updatedDate"
 	FROM 
 		bout b
 	JOIN 
@@ -302,8 +784,10 @@ func (repo *BoutRepository) GetCompletedBoutsByAthleteId(athleteId string) ([]mo
 		athlete r ON b.referee_id = r.athlete_id
 	JOIN 
 		style s ON b.style_id = s.style_id
+	LEFT JOIN 
+		gym g ON b.gym_id = g.gym_id
 	WHERE 
-		b.accepted = true AND b.cancelled = false AND b.completed = true AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = $1)`
+		b.status = 'completed' AND (b.challenger_id = $1 OR b.acceptor_id = $1 OR b.referee_id = $1)`
 
 	rows, err := repo.DB.Queryx(sqlStmt, athleteId)
 	if err != nil {
@@ -320,11 +804,10 @@ func (repo *BoutRepository) GetCompletedBoutsByAthleteId(athleteId string) ([]mo
 	return bouts, nil
 }
 
-func (repo *BoutRepository) CancelBout(boutId, challengerId string) error {
-	sqlStmt := `UPDATE bout SET cancelled = true WHERE bout_id = $1 AND challenger_id = $2`
-	_, err := repo.DB.Exec(sqlStmt, boutId, challengerId)
-	if err != nil {
-		return err
+// nullableAthleteId stores an athlete ID of 0 as NULL
+func nullableAthleteId(athleteId int) interface{} {
+	if athleteId == 0 {
+		return nil
 	}
-	return nil
+	return athleteId
 }
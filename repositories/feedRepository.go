@@ -88,9 +88,7 @@ func (fr *FeedRepository) GetFeedByAthleteId(id string) ([]models.Feed, error) {
 	LEFT JOIN
 		latest_scores ls ON o.loser_id = ls.athlete_id AND ls.style_id = b.style_id AND ls.row_num = 1
 	WHERE 
-		b.cancelled != true 
-		AND b.completed = true 
-		AND b.accepted = true
+		b.status = 'completed'
 		AND (
 			-- Include bouts where the user is a participant
 			b.challenger_id = $1 
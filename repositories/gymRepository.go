@@ -48,8 +48,8 @@ func (repo *GymRepository) GetGymById(id string) (models.Gym, error) {
 }
 
 func (repo *GymRepository) CreateGym(gym models.Gym) (models.Gym, error) {
-	sqlStmt := `INSERT INTO gym (gym_name, gym_address, gym_city, gym_state, gym_zip, gym_phone, gym_email, gym_website, gym_description) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING gym_id`
-	_, err := repo.DB.Exec(sqlStmt, gym.Name, gym.Address, gym.City, gym.State, gym.Zip, gym.Phone, gym.Email, gym.Website, gym.Description)
+	sqlStmt := `INSERT INTO gym (gym_name, gym_address, gym_city, gym_state, gym_zip, gym_phone, gym_email, gym_website, gym_description, owner_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING gym_id`
+	_, err := repo.DB.Exec(sqlStmt, gym.Name, gym.Address, gym.City, gym.State, gym.Zip, gym.Phone, gym.Email, gym.Website, gym.Description, gym.OwnerId)
 	if err != nil {
 		return models.Gym{}, err
 	}
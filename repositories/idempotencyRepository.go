@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"ronin/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRepository struct {
+	DB *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		DB: db,
+	}
+}
+
+// ClaimIdempotencyKey records a new in-progress request under its athlete and key, kept for retention.
+// An expired row for the same key is taken over, as is a request left in progress for longer than
+// abandonAfter. When the key is already held, the existing row is returned with claimed set to false.
+func (repo *IdempotencyRepository) ClaimIdempotencyKey(key models.IdempotencyKey, retention time.Duration, abandonAfter time.Duration) (models.IdempotencyKey, bool, error) {
+	sqlStmt := `INSERT INTO idempotency_key (idempotency_key, athlete_id, request_method, request_path, request_hash, expires_dt)
+	VALUES ($1, $2, $3, $4, $5, now() + $6 * interval '1 second')
+	ON CONFLICT (athlete_id, idempotency_key) DO UPDATE SET
+		request_method = EXCLUDED.request_method,
+		request_path = EXCLUDED.request_path,
+		request_hash = EXCLUDED.request_hash,
+		status_code = NULL,
+		content_type = NULL,
+		response_body = NULL,
+		expires_dt = EXCLUDED.expires_dt,
+		created_dt = now()
+	WHERE idempotency_key.expires_dt <= now()
+		OR (idempotency_key.status_code IS NULL AND idempotency_key.created_dt <= now() - $7 * interval '1 second')
+	RETURNING *`
+	var claimed models.IdempotencyKey
+	err := repo.DB.QueryRowx(sqlStmt, key.Key, key.AthleteId, key.RequestMethod, key.RequestPath, key.RequestHash,
+		retention.Seconds(), abandonAfter.Seconds()).StructScan(&claimed)
+	if err == nil {
+		return claimed, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	var existing models.IdempotencyKey
+	err = repo.DB.QueryRowx(`SELECT * FROM idempotency_key WHERE athlete_id = $1 AND idempotency_key = $2`, key.AthleteId, key.Key).
+		StructScan(&existing)
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+	return existing, false, nil
+}
+
+// SaveIdempotentResponse stores the response to a claimed request so retries can replay it
+func (repo *IdempotencyRepository) SaveIdempotentResponse(idempotencyKeyId int, statusCode int, contentType string, body string) error {
+	_, err := repo.DB.Exec(`UPDATE idempotency_key SET status_code = $2, content_type = $3, response_body = $4 WHERE idempotency_key_id = $1`,
+		idempotencyKeyId, statusCode, contentType, body)
+	return err
+}
+
+// ReleaseIdempotencyKey deletes a claimed request that did not finish, so a retry runs it again
+func (repo *IdempotencyRepository) ReleaseIdempotencyKey(idempotencyKeyId int) error {
+	_, err := repo.DB.Exec(`DELETE FROM idempotency_key WHERE idempotency_key_id = $1`, idempotencyKeyId)
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys deletes every key past its retention window and returns how many there were
+func (repo *IdempotencyRepository) DeleteExpiredIdempotencyKeys() (int64, error) {
+	result, err := repo.DB.Exec(`DELETE FROM idempotency_key WHERE expires_dt <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type MatchmakingRepository struct {
+	DB *sqlx.DB
+}
+
+func NewMatchmakingRepository(db *sqlx.DB) *MatchmakingRepository {
+	return &MatchmakingRepository{
+		DB: db,
+	}
+}
+
+// GetMatchCandidates returns every other athlete rated in a style with their current rating, how many
+// of the athlete's styles they share, how close their gyms are and when the two last completed a bout.
+// Athletes who already have an open bout with the athlete are left out.
+func (repo *MatchmakingRepository) GetMatchCandidates(athleteId int, styleId int) ([]models.MatchCandidate, error) {
+	var candidates []models.MatchCandidate
+	sqlStmt := `WITH latest_scores AS (
+		SELECT DISTINCT ON (athlete_id) athlete_id, score
+		FROM athlete_score
+		WHERE style_id = $2
+		ORDER BY athlete_id, updated_dt DESC
+	),
+	my_styles AS (
+		SELECT style_id FROM athlete_style WHERE athlete_id = $1
+	),
+	my_gyms AS (
+		SELECT g.gym_id, g.gym_city, g.gym_state
+		FROM athlete_gym ag
+		JOIN gym g ON ag.gym_id = g.gym_id
+		WHERE ag.athlete_id = $1
+	)
+	SELECT
+		a.athlete_id,
+		a.first_name,
+		a.last_name,
+		a.username,
+		ls.score,
+		(SELECT COUNT(*) FROM athlete_style s
+			WHERE s.athlete_id = a.athlete_id AND s.style_id IN (SELECT style_id FROM my_styles)) AS shared_styles,
+		(SELECT COUNT(*) FROM my_styles) AS athlete_styles,
+		COALESCE((SELECT MAX(CASE
+				WHEN g.gym_id = mg.gym_id THEN $3
+				WHEN g.gym_city = mg.gym_city AND g.gym_state = mg.gym_state THEN $4
+				WHEN g.gym_state = mg.gym_state THEN $5
+				ELSE $6
+			END)
+			FROM athlete_gym ag
+			JOIN gym g ON ag.gym_id = g.gym_id
+			CROSS JOIN my_gyms mg
+			WHERE ag.athlete_id = a.athlete_id), $6) AS gym_proximity,
+		(SELECT MAX(b.updated_dt) FROM bout b
+			WHERE b.status = 'completed'
+				AND ((b.challenger_id = $1 AND b.acceptor_id = a.athlete_id) OR (b.challenger_id = a.athlete_id AND b.acceptor_id = $1))) AS last_bout_dt
+	FROM latest_scores ls
+	JOIN athlete a ON ls.athlete_id = a.athlete_id
+	WHERE a.athlete_id <> $1
+		AND ls.score IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM bout b
+			WHERE b.status IN ('proposed', 'accepted', 'scheduled', 'in_progress')
+				AND ((b.challenger_id = $1 AND b.acceptor_id = a.athlete_id) OR (b.challenger_id = a.athlete_id AND b.acceptor_id = $1))
+		)`
+	err := repo.DB.Select(&candidates, sqlStmt, athleteId, styleId,
+		models.GymProximitySameGym, models.GymProximitySameCity, models.GymProximitySameState, models.GymProximityNone)
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"database/sql"
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository struct {
+	DB *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{
+		DB: db,
+	}
+}
+
+// GetNotificationsByAthleteId returns an athlete's notifications, newest first
+func (repo *NotificationRepository) GetNotificationsByAthleteId(athleteId int, unreadOnly bool) ([]models.Notification, error) {
+	var notifications []models.Notification
+	sqlStmt := `SELECT * FROM notification
+	WHERE athlete_id = $1 AND (NOT $2 OR read_dt IS NULL)
+	ORDER BY created_dt DESC, notification_id DESC`
+	err := repo.DB.Select(&notifications, sqlStmt, athleteId, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead marks one of an athlete's notifications as read. It returns
+// sql.ErrNoRows if the athlete has no such notification.
+func (repo *NotificationRepository) MarkNotificationRead(notificationId int, athleteId int) error {
+	sqlStmt := `UPDATE notification SET read_dt = COALESCE(read_dt, now()) WHERE notification_id = $1 AND athlete_id = $2`
+	result, err := repo.DB.Exec(sqlStmt, notificationId, athleteId)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// insertNotification adds a notification inside an existing transaction
+func insertNotification(tx *sqlx.Tx, notification models.Notification) error {
+	_, err := tx.Exec(`INSERT INTO notification (athlete_id, bout_id, kind, message) VALUES ($1, $2, $3, $4)`,
+		notification.AthleteId, notification.BoutId, notification.Kind, notification.Message)
+	return err
+}
+
+// AddNotification adds a notification inside a shared transaction
+func (repo *NotificationRepository) AddNotification(tx *Tx, notification models.Notification) error {
+	return insertNotification(tx.tx, notification)
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrOpenChallengeUnavailable is returned when an open challenge was already claimed, cancelled or has expired
+var ErrOpenChallengeUnavailable = errors.New("open challenge is no longer available")
+
+type OpenChallengeRepository struct {
+	DB *sqlx.DB
+}
+
+func NewOpenChallengeRepository(db *sqlx.DB) *OpenChallengeRepository {
+	return &OpenChallengeRepository{
+		DB: db,
+	}
+}
+
+// CreateOpenChallenge posts an open challenge that expires after challenge.ExpiresInHours, or the
+// style's default when that is zero
+func (repo *OpenChallengeRepository) CreateOpenChallenge(challenge models.OpenChallenge) (int, error) {
+	sqlStmt := `INSERT INTO open_challenge (challenger_id, style_id, referee_id, min_rating, max_rating, gym_id, region, scheduled_at, duration_minutes, points, expires_dt)
+	SELECT $1, s.style_id, $3, $4, $5, $6, $7, $8, $9, $10, now() + make_interval(hours => COALESCE(NULLIF($11::int, 0), s.challenge_expiry_hours))
+	FROM style s
+	WHERE s.style_id = $2
+	RETURNING challenge_id`
+	var challengeId int
+	err := repo.DB.QueryRowx(sqlStmt, challenge.ChallengerId, challenge.StyleId, challenge.RefereeId, challenge.MinRating, challenge.MaxRating,
+		challenge.GymId, challenge.Region, challenge.ScheduledAt, challenge.DurationMinutes, challenge.Points, challenge.ExpiresInHours).Scan(&challengeId)
+	if err != nil {
+		return 0, err
+	}
+	return challengeId, nil
+}
+
+func (repo *OpenChallengeRepository) GetOpenChallengeById(challengeId int) (models.OpenChallenge, error) {
+	var challenge models.OpenChallenge
+	err := repo.DB.QueryRowx(`SELECT * FROM open_challenge WHERE challenge_id = $1`, challengeId).StructScan(&challenge)
+	if err != nil {
+		return models.OpenChallenge{}, err
+	}
+	return challenge, nil
+}
+
+// GetOpenChallenges returns unexpired open challenges, soonest to expire first. A styleId of 0 returns every style.
+func (repo *OpenChallengeRepository) GetOpenChallenges(styleId int) ([]models.OpenChallenge, error) {
+	var challenges []models.OpenChallenge
+	sqlStmt := `SELECT * FROM open_challenge
+	WHERE status = 'open' AND expires_dt > now() AND ($1 = 0 OR style_id = $1)
+	ORDER BY expires_dt, challenge_id`
+	err := repo.DB.Select(&challenges, sqlStmt, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// AthleteTrainsAt reports whether an athlete belongs to the given gym, or to any gym in the given
+// region. A nil gym or region is not checked.
+func (repo *OpenChallengeRepository) AthleteTrainsAt(athleteId int, gymId *int, region *string) (bool, error) {
+	sqlStmt := `SELECT EXISTS (
+		SELECT 1 FROM athlete_gym ag
+		JOIN gym g ON ag.gym_id = g.gym_id
+		WHERE ag.athlete_id = $1
+			AND ($2::int IS NULL OR g.gym_id = $2)
+			AND ($3::text IS NULL OR g.gym_state = $3)
+	)`
+	var trains bool
+	err := repo.DB.QueryRowx(sqlStmt, athleteId, gymId, region).Scan(&trains)
+	if err != nil {
+		return false, err
+	}
+	return trains, nil
+}
+
+// ClaimOpenChallenge makes an athlete the acceptor of an open challenge. In one transaction it
+// marks the challenge claimed, creates the bout already accepted (and scheduled when it has a
+// start time) and notifies the challenger. rated is whether the bout is rated under the style's
+// rematch limits. Only one of several simultaneous claims can succeed; the others get
+// ErrOpenChallengeUnavailable.
+func (repo *OpenChallengeRepository) ClaimOpenChallenge(challengeId int, claimerId int, rated bool, message string) (int, error) {
+	tx, err := repo.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var challenge models.OpenChallenge
+	err = tx.QueryRowx(`UPDATE open_challenge SET status = 'claimed', claimed_by = $2
+	WHERE challenge_id = $1 AND status = 'open' AND expires_dt > now()
+	RETURNING *`, challengeId, claimerId).StructScan(&challenge)
+	if errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return 0, ErrOpenChallengeUnavailable
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	boutId, err := insertBout(tx, models.Bout{
+		ChallengerId:    challenge.ChallengerId,
+		AcceptorId:      claimerId,
+		RefereeId:       challenge.RefereeId,
+		StyleId:         challenge.StyleId,
+		Points:          challenge.Points,
+		ScheduledAt:     challenge.ScheduledAt,
+		DurationMinutes: challenge.DurationMinutes,
+		GymId:           challenge.GymId,
+		Rated:           rated,
+	})
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := transitionBoutStatus(tx, boutId, models.BoutStatusProposed, models.BoutStatusAccepted, claimerId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if challenge.ScheduledAt != nil {
+		if err := transitionBoutStatus(tx, boutId, models.BoutStatusAccepted, models.BoutStatusScheduled, claimerId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE open_challenge SET bout_id = $2 WHERE challenge_id = $1`, challengeId, boutId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = insertNotification(tx, models.Notification{
+		AthleteId: challenge.ChallengerId,
+		BoutId:    &boutId,
+		Kind:      models.NotificationChallengeClaimed,
+		Message:   message,
+	})
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return boutId, nil
+}
+
+// CancelOpenChallenge withdraws a challenger's open challenge. It returns ErrOpenChallengeUnavailable
+// if the challenge is not open or not theirs.
+func (repo *OpenChallengeRepository) CancelOpenChallenge(challengeId int, challengerId int) error {
+	result, err := repo.DB.Exec(`UPDATE open_challenge SET status = 'cancelled' WHERE challenge_id = $1 AND challenger_id = $2 AND status = 'open'`,
+		challengeId, challengerId)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrOpenChallengeUnavailable
+	}
+	return nil
+}
+
+// ExpireOpenChallenges marks every open challenge past its expiry time as expired and notifies
+// each challenger. It returns how many challenges expired.
+func (repo *OpenChallengeRepository) ExpireOpenChallenges(message string) (int, error) {
+	tx, err := repo.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var challengerIds []int
+	err = tx.Select(&challengerIds, `UPDATE open_challenge SET status = 'expired'
+	WHERE status = 'open' AND expires_dt <= now()
+	RETURNING challenger_id`)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, challengerId := range challengerIds {
+		err := insertNotification(tx, models.Notification{
+			AthleteId: challengerId,
+			Kind:      models.NotificationChallengeExpired,
+			Message:   message,
+		})
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(challengerIds), nil
+}
@@ -1,11 +1,41 @@
 package repositories
 
 import (
+	"database/sql"
+	"errors"
 	"ronin/models"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	// ErrOutcomeAlreadyVoided is returned when voiding an outcome that has already been voided
+	ErrOutcomeAlreadyVoided = errors.New("outcome has already been voided")
+	// ErrOutcomeStatusChanged is returned when an outcome is no longer in the confirmation status a change expected
+	ErrOutcomeStatusChanged = errors.New("outcome confirmation status has changed")
+	// ErrOutcomeAlreadyAnswered is returned when an athlete confirms or disputes an outcome a second time
+	ErrOutcomeAlreadyAnswered = errors.New("athlete has already answered this outcome")
+)
+
+// outcomeColumns selects an outcome with its nullable bout, winner, loser and draw flag defaulted,
+// as outcomes recorded without a bout or as draws can leave them empty
+const outcomeColumns = `
+		outcome_id,
+		COALESCE(bout_id, 0) AS bout_id,
+		COALESCE(winner_id, 0) AS winner_id,
+		COALESCE(loser_id, 0) AS loser_id,
+		style_id,
+		COALESCE(is_draw, false) AS is_draw,
+		finish_method,
+		score_margin,
+		rated,
+		confirmation_status,
+		confirm_by_dt,
+		confirmed_dt,
+		voided_dt,
+		created_dt,
+		updated_dt`
+
 type OutcomeRepository struct {
 	DB *sqlx.DB
 }
@@ -20,7 +50,7 @@ func (repo *OutcomeRepository) GetAllOutcomes() ([]models.Outcome, error) {
 	var outcomes []models.Outcome
 	var tempOutcome models.Outcome
 
-	sqlStmt := `SELECT * FROM outcome`
+	sqlStmt := `SELECT ` + outcomeColumns + ` FROM outcome ORDER BY outcome_id`
 	rows, err := repo.DB.Queryx(sqlStmt)
 	if err != nil {
 		return nil, err
@@ -39,7 +69,7 @@ func (repo *OutcomeRepository) GetAllOutcomes() ([]models.Outcome, error) {
 
 func (repo *OutcomeRepository) GetOutcomeById(id string) (models.Outcome, error) {
 	var outcome models.Outcome
-	sqlStmt := `SELECT * FROM outcome WHERE outcome_id = $1`
+	sqlStmt := `SELECT ` + outcomeColumns + ` FROM outcome WHERE outcome_id = $1`
 	err := repo.DB.QueryRowx(sqlStmt, id).StructScan(&outcome)
 	if err != nil {
 		return models.Outcome{}, err
@@ -47,62 +77,265 @@ func (repo *OutcomeRepository) GetOutcomeById(id string) (models.Outcome, error)
 	return outcome, nil
 }
 
-func (repo *OutcomeRepository) CreateOutcome(outcome models.Outcome) (models.Outcome, error) {
-	sqlStmt := `INSERT INTO outcome (bout_id, winner_id, loser_id, style_id) VALUES ($1, $2, $3, $4) RETURNING outcome_id`
-	_, err := repo.DB.Exec(sqlStmt, outcome.BoutId, outcome.WinnerId, outcome.LoserId, outcome.StyleId)
+// InsertOutcome records an outcome with its finish and scorecard inside a shared transaction. A
+// confirmed outcome is counted in both athletes' win, loss and draw records straight away; a pending
+// one waits for ConfirmOutcome. The returned outcome carries its new ID.
+func (repo *OutcomeRepository) InsertOutcome(tx *Tx, outcome models.Outcome) (models.Outcome, error) {
+	sqlStmt := `INSERT INTO outcome (bout_id, winner_id, loser_id, style_id, is_draw, finish_method, score_margin, rated,
+		confirmation_status, confirm_by_dt, confirmed_dt)
+	VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, $7, $8, $9, $10::timestamptz, CASE WHEN $9 = 'confirmed' THEN now() END)
+	RETURNING outcome_id, confirmed_dt, created_dt, updated_dt`
+	err := tx.tx.QueryRowx(sqlStmt, outcome.BoutId, outcome.WinnerId, outcome.LoserId, outcome.StyleId, outcome.IsDraw,
+		outcome.FinishMethod, outcome.ScoreMargin, outcome.Rated, outcome.ConfirmationStatus, outcome.ConfirmByDate).
+		Scan(&outcome.OutcomeId, &outcome.ConfirmedDate, &outcome.CreatedDate, &outcome.UpdatedDate)
 	if err != nil {
 		return models.Outcome{}, err
 	}
+
+	if outcome.Finish != nil {
+		finish := *outcome.Finish
+		err := tx.tx.QueryRowx(`INSERT INTO outcome_finish (outcome_id, submission_type, finish_round, finish_time_seconds)
+		VALUES ($1, $2, $3, $4) RETURNING *`, outcome.OutcomeId, finish.SubmissionType, finish.FinishRound, finish.FinishTimeSeconds).
+			StructScan(&finish)
+		if err != nil {
+			return models.Outcome{}, err
+		}
+		outcome.Finish = &finish
+	}
+
+	for i, score := range outcome.Scorecard {
+		err := tx.tx.QueryRowx(`INSERT INTO outcome_round_score (outcome_id, round_number, athlete_id, points, advantages, penalties)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`, outcome.OutcomeId, score.RoundNumber, score.AthleteId, score.Points, score.Advantages, score.Penalties).
+			StructScan(&outcome.Scorecard[i])
+		if err != nil {
+			return models.Outcome{}, err
+		}
+	}
+
+	if outcome.ConfirmationStatus == models.OutcomeStatusConfirmed {
+		if err := adjustAthleteRecords(tx.tx, outcome, 1); err != nil {
+			return models.Outcome{}, err
+		}
+	}
+	return outcome, nil
+}
+
+// ConfirmOutcome confirms a pending or disputed outcome inside a shared transaction, with the
+// result given in outcome, and counts it in both athletes' records. It returns
+// ErrOutcomeStatusChanged if the outcome is no longer in fromStatus.
+func (repo *OutcomeRepository) ConfirmOutcome(tx *Tx, outcome models.Outcome, fromStatus string) (models.Outcome, error) {
+	sqlStmt := `UPDATE outcome SET confirmation_status = 'confirmed', confirmed_dt = now(), winner_id = $3, loser_id = $4, is_draw = $5
+	WHERE outcome_id = $1 AND confirmation_status = $2 AND voided_dt IS NULL
+	RETURNING confirmation_status, confirmed_dt, updated_dt`
+	err := tx.tx.QueryRowx(sqlStmt, outcome.OutcomeId, fromStatus, outcome.WinnerId, outcome.LoserId, outcome.IsDraw).
+		Scan(&outcome.ConfirmationStatus, &outcome.ConfirmedDate, &outcome.UpdatedDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Outcome{}, ErrOutcomeStatusChanged
+	}
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	if err := adjustAthleteRecords(tx.tx, outcome, 1); err != nil {
+		return models.Outcome{}, err
+	}
 	return outcome, nil
 }
 
-func (repo *OutcomeRepository) CreateOutcomeByBoutIdNotDraw(outcome *models.Outcome, boutId string) error {
-	sqlStmt := `INSERT INTO outcome (bout_id, winner_id, loser_id, is_draw, style_id) VALUES ($1, $2, $3, $4, $5) RETURNING outcome_id`
-	err := repo.DB.QueryRowx(sqlStmt, boutId, outcome.WinnerId, outcome.LoserId, outcome.IsDraw, outcome.StyleId).StructScan(outcome)
+// DisputeOutcome moves a pending outcome to disputed inside a shared transaction. It returns
+// ErrOutcomeStatusChanged if the outcome is no longer pending.
+func (repo *OutcomeRepository) DisputeOutcome(tx *Tx, outcomeId int) error {
+	result, err := tx.tx.Exec(`UPDATE outcome SET confirmation_status = 'disputed'
+	WHERE outcome_id = $1 AND confirmation_status = 'pending' AND voided_dt IS NULL`, outcomeId)
 	if err != nil {
 		return err
 	}
-	sqlStmt = `UPDATE athlete_record SET wins = wins + 1 WHERE athlete_id = $1`
-	_, err = repo.DB.Exec(sqlStmt, outcome.WinnerId)
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return ErrOutcomeStatusChanged
+	}
+	return nil
+}
+
+// GetOverdueOutcomes returns pending outcomes whose confirmation window has ended, oldest first
+func (repo *OutcomeRepository) GetOverdueOutcomes() ([]models.Outcome, error) {
+	var outcomes []models.Outcome
+	sqlStmt := `SELECT ` + outcomeColumns + ` FROM outcome
+	WHERE confirmation_status = 'pending' AND voided_dt IS NULL AND confirm_by_dt <= now()
+	ORDER BY confirm_by_dt, outcome_id`
+	err := repo.DB.Select(&outcomes, sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
 
-	sqlStmt = `UPDATE athlete_record SET losses = losses + 1 WHERE athlete_id = $1`
-	_, err = repo.DB.Exec(sqlStmt, outcome.LoserId)
+// InsertOutcomeResponse records an athlete's confirmation or dispute inside a shared transaction.
+// It returns ErrOutcomeAlreadyAnswered if the athlete has already answered the outcome.
+func (repo *OutcomeRepository) InsertOutcomeResponse(tx *Tx, response models.OutcomeResponse) error {
+	result, err := tx.tx.Exec(`INSERT INTO outcome_response (outcome_id, athlete_id, response, reason) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (outcome_id, athlete_id) DO NOTHING`, response.OutcomeId, response.AthleteId, response.Response, response.Reason)
 	if err != nil {
 		return err
 	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrOutcomeAlreadyAnswered
+	}
 	return nil
 }
 
-func (repo *OutcomeRepository) CreateOutcomeByBoutIdDraw(outcome *models.Outcome, boutId string) error {
-	sqlStmt := `INSERT INTO outcome (bout_id, winner_id, loser_id, is_draw, style_id) VALUES ($1, null, null, true, $2) RETURNING outcome_id`
-	err := repo.DB.QueryRowx(sqlStmt, boutId, outcome.StyleId).StructScan(outcome)
+// LockPendingOutcome locks a pending outcome's row inside a shared transaction and returns every
+// athlete's answer to it, oldest first. Other answers to the outcome wait until the transaction ends.
+// It returns ErrOutcomeStatusChanged if the outcome is no longer pending.
+func (repo *OutcomeRepository) LockPendingOutcome(tx *Tx, outcomeId int) ([]models.OutcomeResponse, error) {
+	var status string
+	err := tx.tx.QueryRow(`SELECT confirmation_status FROM outcome WHERE outcome_id = $1 AND voided_dt IS NULL FOR UPDATE`, outcomeId).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && status != models.OutcomeStatusPending) {
+		return nil, ErrOutcomeStatusChanged
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	sqlStmt = `UPDATE athlete_record SET draws = draws + 1 WHERE athlete_id = $1`
-	_, err = repo.DB.Exec(sqlStmt, outcome.WinnerId)
+	responses := []models.OutcomeResponse{}
+	err = tx.tx.Select(&responses, `SELECT * FROM outcome_response WHERE outcome_id = $1 ORDER BY created_dt, response_id`, outcomeId)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return responses, nil
+}
 
-	sqlStmt = `UPDATE athlete_record SET draws = draws + 1 WHERE athlete_id = $1`
-	_, err = repo.DB.Exec(sqlStmt, outcome.LoserId)
+// GetOutcomeResponses returns every athlete's answer to an outcome, oldest first
+func (repo *OutcomeRepository) GetOutcomeResponses(outcomeId int) ([]models.OutcomeResponse, error) {
+	responses := []models.OutcomeResponse{}
+	err := repo.DB.Select(&responses, `SELECT * FROM outcome_response WHERE outcome_id = $1 ORDER BY created_dt, response_id`, outcomeId)
 	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// InsertOutcomeResolution audits how a disputed outcome was resolved inside a shared transaction
+func (repo *OutcomeRepository) InsertOutcomeResolution(tx *Tx, resolution models.OutcomeResolution) (models.OutcomeResolution, error) {
+	err := tx.tx.QueryRowx(`INSERT INTO outcome_resolution (outcome_id, resolved_by, action, reason) VALUES ($1, $2, $3, $4) RETURNING *`,
+		resolution.OutcomeId, resolution.ResolvedBy, resolution.Action, resolution.Reason).StructScan(&resolution)
+	if err != nil {
+		return models.OutcomeResolution{}, err
+	}
+	return resolution, nil
+}
+
+// GetOutcomeResolution returns how a disputed outcome was resolved
+func (repo *OutcomeRepository) GetOutcomeResolution(outcomeId int) (models.OutcomeResolution, error) {
+	var resolution models.OutcomeResolution
+	err := repo.DB.QueryRowx(`SELECT * FROM outcome_resolution WHERE outcome_id = $1`, outcomeId).StructScan(&resolution)
+	if err != nil {
+		return models.OutcomeResolution{}, err
+	}
+	return resolution, nil
+}
+
+// adjustAthleteRecords adds delta to the wins, losses or draws an outcome counts for inside an open transaction
+func adjustAthleteRecords(tx *sqlx.Tx, outcome models.Outcome, delta int) error {
+	if outcome.IsDraw {
+		_, err := tx.Exec(`UPDATE athlete_record SET draws = draws + $3 WHERE athlete_id IN ($1, $2)`, outcome.WinnerId, outcome.LoserId, delta)
 		return err
 	}
-	return nil
+
+	if _, err := tx.Exec(`UPDATE athlete_record SET wins = wins + $2 WHERE athlete_id = $1`, outcome.WinnerId, delta); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE athlete_record SET losses = losses + $2 WHERE athlete_id = $1`, outcome.LoserId, delta)
+	return err
 }
 
 func (repo *OutcomeRepository) GetOutcomeByBoutId(boutId string) (models.Outcome, error) {
 	var outcome models.Outcome
-	sqlStmt := `SELECT * FROM outcome WHERE bout_id = $1`
+	sqlStmt := `SELECT ` + outcomeColumns + ` FROM outcome WHERE bout_id = $1`
 	err := repo.DB.QueryRowx(sqlStmt, boutId).StructScan(&outcome)
 	if err != nil {
 		return models.Outcome{}, err
 	}
 	return outcome, nil
 }
+
+// GetOutcomeFinish returns how an outcome ended
+func (repo *OutcomeRepository) GetOutcomeFinish(outcomeId int) (models.OutcomeFinish, error) {
+	var finish models.OutcomeFinish
+	err := repo.DB.QueryRowx(`SELECT * FROM outcome_finish WHERE outcome_id = $1`, outcomeId).StructScan(&finish)
+	if err != nil {
+		return models.OutcomeFinish{}, err
+	}
+	return finish, nil
+}
+
+// GetOutcomeScorecard returns an outcome's round scores, by round and then by athlete
+func (repo *OutcomeRepository) GetOutcomeScorecard(outcomeId int) ([]models.OutcomeRoundScore, error) {
+	scorecard := []models.OutcomeRoundScore{}
+	err := repo.DB.Select(&scorecard, `SELECT * FROM outcome_round_score WHERE outcome_id = $1 ORDER BY round_number, athlete_id`, outcomeId)
+	if err != nil {
+		return nil, err
+	}
+	return scorecard, nil
+}
+
+// GetOutcomesByStyle returns every confirmed outcome in a style that has not been voided, in the order
+// they were confirmed, read inside a shared transaction
+func (repo *OutcomeRepository) GetOutcomesByStyle(tx *Tx, styleId int) ([]models.Outcome, error) {
+	var outcomes []models.Outcome
+	sqlStmt := `SELECT ` + outcomeColumns + `
+	FROM outcome
+	WHERE style_id = $1 AND confirmation_status = 'confirmed' AND voided_dt IS NULL
+	ORDER BY confirmed_dt, outcome_id`
+	err := tx.tx.Select(&outcomes, sqlStmt, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// VoidOutcome marks an outcome voided, takes it back out of both athletes' win, loss and draw
+// records if it had been confirmed, and audits who voided it and why, inside a shared transaction.
+// It returns ErrOutcomeAlreadyVoided if the outcome was voided first.
+func (repo *OutcomeRepository) VoidOutcome(tx *Tx, outcome models.Outcome, voidedBy int, reason string) (models.OutcomeVoid, error) {
+	var status string
+	err := tx.tx.QueryRowx(`UPDATE outcome SET voided_dt = now() WHERE outcome_id = $1 AND voided_dt IS NULL RETURNING confirmation_status`,
+		outcome.OutcomeId).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.OutcomeVoid{}, ErrOutcomeAlreadyVoided
+	}
+	if err != nil {
+		return models.OutcomeVoid{}, err
+	}
+
+	if status == models.OutcomeStatusConfirmed {
+		if err := adjustAthleteRecords(tx.tx, outcome, -1); err != nil {
+			return models.OutcomeVoid{}, err
+		}
+	}
+
+	var void models.OutcomeVoid
+	err = tx.tx.QueryRowx(`INSERT INTO outcome_void (outcome_id, bout_id, voided_by, reason) VALUES ($1, NULLIF($2, 0), $3, $4) RETURNING *`,
+		outcome.OutcomeId, outcome.BoutId, voidedBy, reason).StructScan(&void)
+	if err != nil {
+		return models.OutcomeVoid{}, err
+	}
+	return void, nil
+}
+
+// GetOutcomeVoid returns the audit record of a voided outcome
+func (repo *OutcomeRepository) GetOutcomeVoid(outcomeId int) (models.OutcomeVoid, error) {
+	var void models.OutcomeVoid
+	err := repo.DB.QueryRowx(`SELECT * FROM outcome_void WHERE outcome_id = $1`, outcomeId).StructScan(&void)
+	if err != nil {
+		return models.OutcomeVoid{}, err
+	}
+	return void, nil
+}
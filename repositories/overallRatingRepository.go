@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type OverallRatingRepository struct {
+	DB *sqlx.DB
+}
+
+func NewOverallRatingRepository(db *sqlx.DB) *OverallRatingRepository {
+	return &OverallRatingRepository{
+		DB: db,
+	}
+}
+
+func (repo *OverallRatingRepository) GetOverallRatingConfig() (models.OverallRatingConfig, error) {
+	var config models.OverallRatingConfig
+	sqlStmt := `SELECT COALESCE(settings, '{}'::jsonb) AS settings, updated_dt FROM overall_rating_config WHERE config_id = 1`
+	err := repo.DB.QueryRowx(sqlStmt).StructScan(&config)
+	if err != nil {
+		return models.OverallRatingConfig{}, err
+	}
+	return config, nil
+}
+
+func (repo *OverallRatingRepository) UpsertOverallRatingConfig(config models.OverallRatingConfig) error {
+	settings := string(config.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	sqlStmt := `INSERT INTO overall_rating_config (config_id, settings) VALUES (1, $1::jsonb)
+		ON CONFLICT (config_id) DO UPDATE SET settings = EXCLUDED.settings`
+	_, err := repo.DB.Exec(sqlStmt, settings)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAthleteStyleRatings returns the current score and rated bout count of every style an athlete is registered in
+func (repo *OverallRatingRepository) GetAthleteStyleRatings(athleteId int) ([]models.OverallRatingStyle, error) {
+	var styles []models.OverallRatingStyle
+	sqlStmt := `SELECT
+		s.style_id,
+		s.style_name,
+		latest.score,
+		(SELECT COUNT(*) FROM athlete_score_history h
+			WHERE h.athlete_id = ast.athlete_id AND h.style_id = ast.style_id AND h.outcome_id IS NOT NULL) AS rated_bouts
+	FROM athlete_style AS ast
+	JOIN style AS s ON s.style_id = ast.style_id
+	JOIN LATERAL (
+		SELECT score FROM athlete_score
+		WHERE athlete_id = ast.athlete_id AND style_id = ast.style_id
+		ORDER BY updated_dt DESC
+		LIMIT 1
+	) AS latest ON true
+	WHERE ast.athlete_id = $1
+	ORDER BY s.style_id`
+	err := repo.DB.Select(&styles, sqlStmt, athleteId)
+	if err != nil {
+		return nil, err
+	}
+	return styles, nil
+}
+
+// GetRatedAthleteIds returns every athlete with a score in at least one style
+func (repo *OverallRatingRepository) GetRatedAthleteIds() ([]int, error) {
+	var athleteIds []int
+	err := repo.DB.Select(&athleteIds, `SELECT DISTINCT athlete_id FROM athlete_score ORDER BY athlete_id`)
+	if err != nil {
+		return nil, err
+	}
+	return athleteIds, nil
+}
+
+func (repo *OverallRatingRepository) UpsertOverallRating(rating models.OverallRating) error {
+	sqlStmt := `INSERT INTO athlete_overall_rating (athlete_id, overall_score, styles_rated, rated_bouts) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (athlete_id) DO UPDATE SET overall_score = EXCLUDED.overall_score,
+			styles_rated = EXCLUDED.styles_rated, rated_bouts = EXCLUDED.rated_bouts`
+	_, err := repo.DB.Exec(sqlStmt, rating.AthleteId, rating.OverallScore, rating.StylesRated, rating.RatedBouts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *OverallRatingRepository) GetOverallRating(athleteId int) (models.OverallRating, error) {
+	var rating models.OverallRating
+	sqlStmt := `SELECT
+		o.athlete_id,
+		a.username,
+		o.overall_score,
+		o.styles_rated,
+		o.rated_bouts,
+		o.updated_dt
+	FROM athlete_overall_rating AS o
+	JOIN athlete AS a ON a.athlete_id = o.athlete_id
+	WHERE o.athlete_id = $1`
+	err := repo.DB.QueryRowx(sqlStmt, athleteId).StructScan(&rating)
+	if err != nil {
+		return models.OverallRating{}, err
+	}
+	return rating, nil
+}
+
+// GetOverallLeaderboard returns a page of overall ratings, highest first, with tied scores sharing a rank
+func (repo *OverallRatingRepository) GetOverallLeaderboard(limit, offset int) ([]models.OverallRating, error) {
+	var ratings []models.OverallRating
+	sqlStmt := `SELECT
+		o.athlete_id,
+		a.username,
+		RANK() OVER (ORDER BY o.overall_score DESC) AS overall_rank,
+		o.overall_score,
+		o.styles_rated,
+		o.rated_bouts,
+		o.updated_dt
+	FROM athlete_overall_rating AS o
+	JOIN athlete AS a ON a.athlete_id = o.athlete_id
+	ORDER BY o.overall_score DESC, o.athlete_id
+	LIMIT $1 OFFSET $2`
+	err := repo.DB.Select(&ratings, sqlStmt, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
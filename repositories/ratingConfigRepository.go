@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RatingConfigRepository struct {
+	DB *sqlx.DB
+}
+
+func NewRatingConfigRepository(db *sqlx.DB) *RatingConfigRepository {
+	return &RatingConfigRepository{
+		DB: db,
+	}
+}
+
+func (repo *RatingConfigRepository) GetRatingConfigByStyle(styleId int) (models.StyleRatingConfig, error) {
+	var config models.StyleRatingConfig
+	sqlStmt := `SELECT
+		style_id,
+		engine,
+		COALESCE(settings, '{}'::jsonb) AS settings,
+		created_dt,
+		updated_dt
+	FROM style_rating_config
+	WHERE style_id = $1`
+	err := repo.DB.QueryRowx(sqlStmt, styleId).StructScan(&config)
+	if err != nil {
+		return models.StyleRatingConfig{}, err
+	}
+	return config, nil
+}
+
+func (repo *RatingConfigRepository) UpsertRatingConfig(config models.StyleRatingConfig) error {
+	settings := string(config.Settings)
+	if settings == "" {
+		settings = "{}"
+	}
+
+	sqlStmt := `INSERT INTO style_rating_config (style_id, engine, settings) VALUES ($1, $2, $3::jsonb)
+		ON CONFLICT (style_id) DO UPDATE SET engine = EXCLUDED.engine, settings = EXCLUDED.settings`
+	_, err := repo.DB.Exec(sqlStmt, config.StyleId, config.Engine, settings)
+	if err != nil {
+		return err
+	}
+	return nil
+}
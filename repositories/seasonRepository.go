@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"errors"
+	"ronin/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrSeasonAlreadyClosed is returned when closing a season that another request already closed
+var ErrSeasonAlreadyClosed = errors.New("season is already closed")
+
+type SeasonRepository struct {
+	DB *sqlx.DB
+}
+
+func NewSeasonRepository(db *sqlx.DB) *SeasonRepository {
+	return &SeasonRepository{
+		DB: db,
+	}
+}
+
+const seasonColumns = `season_id,
+		style_id,
+		season_name,
+		to_char(start_dt, 'YYYY-MM-DD') AS start_dt,
+		to_char(end_dt, 'YYYY-MM-DD') AS end_dt,
+		COALESCE(reset_mean, 0) AS reset_mean,
+		COALESCE(reset_weight, 0) AS reset_weight,
+		closed_dt,
+		created_dt,
+		updated_dt`
+
+func (repo *SeasonRepository) CreateSeason(season models.Season) (int, error) {
+	var seasonId int
+	sqlStmt := `INSERT INTO season (style_id, season_name, start_dt, end_dt) VALUES ($1, $2, $3, $4) RETURNING season_id`
+	err := repo.DB.QueryRow(sqlStmt, season.StyleId, season.SeasonName, season.StartDate, season.EndDate).Scan(&seasonId)
+	if err != nil {
+		return 0, err
+	}
+	return seasonId, nil
+}
+
+func (repo *SeasonRepository) GetSeasonById(seasonId int) (models.Season, error) {
+	var season models.Season
+	sqlStmt := `SELECT ` + seasonColumns + ` FROM season WHERE season_id = $1`
+	err := repo.DB.QueryRowx(sqlStmt, seasonId).StructScan(&season)
+	if err != nil {
+		return models.Season{}, err
+	}
+	return season, nil
+}
+
+// GetSeasonsByStyle returns every season of a style, most recent first
+func (repo *SeasonRepository) GetSeasonsByStyle(styleId int) ([]models.Season, error) {
+	var seasons []models.Season
+	sqlStmt := `SELECT ` + seasonColumns + ` FROM season WHERE style_id = $1 ORDER BY start_dt DESC`
+	err := repo.DB.Select(&seasons, sqlStmt, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// GetClosedSeasonsByStyle returns the closed seasons of a style in the order they were closed, read inside a shared transaction
+func (repo *SeasonRepository) GetClosedSeasonsByStyle(tx *Tx, styleId int) ([]models.Season, error) {
+	var seasons []models.Season
+	sqlStmt := `SELECT ` + seasonColumns + ` FROM season WHERE style_id = $1 AND closed_dt IS NOT NULL ORDER BY closed_dt, season_id`
+	err := tx.tx.Select(&seasons, sqlStmt, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// GetEndedOpenSeasons returns every season whose end date has passed but has not been closed yet
+func (repo *SeasonRepository) GetEndedOpenSeasons() ([]models.Season, error) {
+	var seasons []models.Season
+	sqlStmt := `SELECT ` + seasonColumns + ` FROM season WHERE closed_dt IS NULL AND end_dt < CURRENT_DATE ORDER BY end_dt, season_id`
+	err := repo.DB.Select(&seasons, sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// HasOverlappingSeason reports whether a style already has a season covering any day between startDate and endDate
+func (repo *SeasonRepository) HasOverlappingSeason(styleId int, startDate, endDate string) (bool, error) {
+	var exists bool
+	sqlStmt := `SELECT EXISTS (SELECT 1 FROM season WHERE style_id = $1 AND start_dt <= $3 AND end_dt >= $2)`
+	err := repo.DB.QueryRow(sqlStmt, styleId, startDate, endDate).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// CloseSeason archives the final standings, writes the soft-reset ratings and marks the season
+// closed in one transaction. It returns ErrSeasonAlreadyClosed if the season was closed concurrently.
+func (repo *SeasonRepository) CloseSeason(season models.Season, standings []models.SeasonStanding, resets []models.RatingChange) error {
+	tx, err := repo.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err := lockStyleScores(tx, season.StyleId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec(`UPDATE season SET closed_dt = now(), reset_mean = $2, reset_weight = $3
+		WHERE season_id = $1 AND closed_dt IS NULL`, season.SeasonId, season.ResetMean, season.ResetWeight)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		tx.Rollback()
+		if err != nil {
+			return err
+		}
+		return ErrSeasonAlreadyClosed
+	}
+
+	for _, standing := range standings {
+		_, err = tx.Exec(`INSERT INTO season_standing (season_id, athlete_id, style_id, final_rank, final_score, rating_deviation, volatility, rated_bouts)
+			SELECT $1, $2, $3, $4, $5, $6, $7, COUNT(h.history_id)
+			FROM season s
+			LEFT JOIN athlete_score_history h ON h.athlete_id = $2 AND h.style_id = s.style_id AND h.outcome_id IS NOT NULL
+				AND h.created_dt >= s.start_dt AND h.created_dt < s.end_dt + 1
+			WHERE s.season_id = $1`, season.SeasonId, standing.AthleteId, standing.StyleId, standing.Rank, standing.Score,
+			standing.RatingDeviation, standing.Volatility)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, reset := range resets {
+		if err := appendAthleteScore(tx, reset, 0); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSeasonStandings returns the archived standings of a season, best rank first
+func (repo *SeasonRepository) GetSeasonStandings(seasonId int) ([]models.SeasonStanding, error) {
+	var standings []models.SeasonStanding
+	sqlStmt := `SELECT
+		season_id,
+		athlete_id,
+		style_id,
+		final_rank,
+		final_score,
+		COALESCE(rating_deviation, 0) AS rating_deviation,
+		COALESCE(volatility, 0) AS volatility,
+		rated_bouts,
+		created_dt
+	FROM season_standing
+	WHERE season_id = $1
+	ORDER BY final_rank, athlete_id`
+	err := repo.DB.Select(&standings, sqlStmt, seasonId)
+	if err != nil {
+		return nil, err
+	}
+	return standings, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"database/sql"
 	"ronin/models"
 
 	"github.com/jmoiron/sqlx"
@@ -37,12 +38,34 @@ func (repo *StyleRepository) GetAllStyles() ([]models.Style, error) {
 	return styles, nil
 }
 
+// CreateStyle inserts a style. A zero ChallengeExpiryHours keeps the column default.
 func (repo *StyleRepository) CreateStyle(style models.Style) error {
-	sqlStmt := `INSERT INTO style (style_name) VALUES ($1) RETURNING style_id`
-	err := repo.DB.QueryRowx(sqlStmt, style.StyleName).Scan(&style)
+	if style.ChallengeExpiryHours == 0 {
+		sqlStmt := `INSERT INTO style (style_name) VALUES ($1) RETURNING style_id`
+		return repo.DB.QueryRowx(sqlStmt, style.StyleName).Scan(&style.StyleId)
+	}
+
+	sqlStmt := `INSERT INTO style (style_name, challenge_expiry_hours) VALUES ($1, $2) RETURNING style_id`
+	err := repo.DB.QueryRowx(sqlStmt, style.StyleName, style.ChallengeExpiryHours).Scan(&style.StyleId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetChallengeExpiryHours sets how long new challenges in a style stay open by default
+func (repo *StyleRepository) SetChallengeExpiryHours(styleId int, hours int) error {
+	result, err := repo.DB.Exec(`UPDATE style SET challenge_expiry_hours = $2 WHERE style_id = $1`, styleId, hours)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
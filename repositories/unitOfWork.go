@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// Tx is a transaction shared by several repositories. Repository methods that take a *Tx write
+// inside it instead of committing on their own.
+type Tx struct {
+	tx *sqlx.Tx
+}
+
+// UnitOfWork runs writes from several repositories as one transaction
+type UnitOfWork struct {
+	DB *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{
+		DB: db,
+	}
+}
+
+// Run calls fn inside a transaction and commits it if fn returns nil. An error or panic from fn
+// rolls back everything written through the transaction.
+func (u *UnitOfWork) Run(fn func(tx *Tx) error) error {
+	tx, err := u.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Tx{tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
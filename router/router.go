@@ -3,23 +3,32 @@ package router
 import (
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"ronin/services"
+	"ronin/utils"
 )
 
 const base_url = "/api/v1"
 
 var (
-	outcomeHandler      *services.OutcomeHandler
-	athleteHandler      *services.AthleteHandler
-	athleteScoreHandler *services.AthleteScoreHandler
-	boutHandler         *services.BoutHandler
-	feedHandler         *services.FeedHandler
-	gymHandler          *services.GymHandler
-	styleHandler        *services.StyleHandler
+	outcomeHandler       *services.OutcomeHandler
+	athleteHandler       *services.AthleteHandler
+	athleteScoreHandler  *services.AthleteScoreHandler
+	boutHandler          *services.BoutHandler
+	feedHandler          *services.FeedHandler
+	gymHandler           *services.GymHandler
+	styleHandler         *services.StyleHandler
+	ratingReplayHandler  *services.RatingReplayHandler
+	seasonHandler        *services.SeasonHandler
+	overallRatingHandler *services.OverallRatingHandler
+	notificationHandler  *services.NotificationHandler
+	openChallengeHandler *services.OpenChallengeHandler
+	matchmakingHandler   *services.MatchmakingHandler
+	idempotencyService   *services.IdempotencyService
 )
 
 func SetOutcomeHandler(h *services.OutcomeHandler) {
@@ -50,6 +59,34 @@ func SetStyleHandler(h *services.StyleHandler) {
 	styleHandler = h
 }
 
+func SetRatingReplayHandler(h *services.RatingReplayHandler) {
+	ratingReplayHandler = h
+}
+
+func SetSeasonHandler(h *services.SeasonHandler) {
+	seasonHandler = h
+}
+
+func SetOverallRatingHandler(h *services.OverallRatingHandler) {
+	overallRatingHandler = h
+}
+
+func SetNotificationHandler(h *services.NotificationHandler) {
+	notificationHandler = h
+}
+
+func SetOpenChallengeHandler(h *services.OpenChallengeHandler) {
+	openChallengeHandler = h
+}
+
+func SetMatchmakingHandler(h *services.MatchmakingHandler) {
+	matchmakingHandler = h
+}
+
+func SetIdempotencyService(s *services.IdempotencyService) {
+	idempotencyService = s
+}
+
 // LoggingMiddleware logs all incoming requests
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -71,6 +108,31 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// AuthMiddleware resolves the athlete from an "Authorization: Bearer <token>" header.
+// Requests without the header continue anonymously; handlers decide whether they need an athlete.
+func AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !strings.HasPrefix(header, "Bearer ") {
+			services.SendError(w, "Authorization header must use the Bearer scheme", http.StatusUnauthorized)
+			return
+		}
+
+		athleteId, err := utils.ParseAuthToken(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			services.SendError(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(utils.WithAthleteId(r.Context(), athleteId)))
+	})
+}
+
 // Custom response writer to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -85,8 +147,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 func CreateRouter() *mux.Router {
 	router := mux.NewRouter()
 
-	// Apply logging middleware to all routes
+	// Apply logging and auth middleware to all routes
 	router.Use(LoggingMiddleware)
+	router.Use(AuthMiddleware)
 
 	// Athlete routes
 	router.HandleFunc(base_url+"/athletes", athleteHandler.GetAllAthletes).Methods("GET")
@@ -102,24 +165,47 @@ func CreateRouter() *mux.Router {
 	router.HandleFunc(base_url+"/athletes/following/{id}", athleteHandler.GetAthletesFollowed).Methods("GET")
 
 	// Bout routes
-	router.HandleFunc(base_url+"/bouts", boutHandler.GetAllBouts).Methods("GET")
+	router.HandleFunc(base_url+"/bouts", boutHandler.ListBouts).Methods("GET")
 	router.HandleFunc(base_url+"/bout/{bout_id}", boutHandler.GetBout).Methods("GET")
-	router.HandleFunc(base_url+"/bout", boutHandler.CreateBout).Methods("POST")
-	router.HandleFunc(base_url+"/bout/{bout_id}", boutHandler.UpdateBout).Methods("PUT")
-	router.HandleFunc(base_url+"/bout/{bout_id}", boutHandler.DeleteBout).Methods("DELETE")
-	router.HandleFunc(base_url+"/bout/{bout_id}/accept", boutHandler.AcceptBout).Methods("PUT")
-	router.HandleFunc(base_url+"/bout/{bout_id}/decline", boutHandler.DeclineBout).Methods("PUT")
-	router.HandleFunc(base_url+"/bout/{bout_id}/complete/{referee_id}", boutHandler.CompleteBout).Methods("PUT")
-	router.HandleFunc(base_url+"/bout/cancel/{bout_id}/{challenger_id}", boutHandler.CancelBout).Methods("PUT")
+	router.HandleFunc(base_url+"/bout", idempotencyService.Idempotent(boutHandler.CreateBout)).Methods("POST")
+	router.HandleFunc(base_url+"/bout/{bout_id}", idempotencyService.Idempotent(boutHandler.UpdateBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}", idempotencyService.Idempotent(boutHandler.DeleteBout)).Methods("DELETE")
+	router.HandleFunc(base_url+"/bout/{bout_id}/accept", idempotencyService.Idempotent(boutHandler.AcceptBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/decline", idempotencyService.Idempotent(boutHandler.DeclineBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/counter", idempotencyService.Idempotent(boutHandler.CounterBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/proposals", boutHandler.GetBoutProposals).Methods("GET")
+	router.HandleFunc(base_url+"/bout/{bout_id}/schedule", idempotencyService.Idempotent(boutHandler.ScheduleBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/start/{referee_id}", idempotencyService.Idempotent(boutHandler.StartBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/complete/{referee_id}", idempotencyService.Idempotent(boutHandler.CompleteBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/cancel/{bout_id}/{challenger_id}", idempotencyService.Idempotent(boutHandler.CancelBout)).Methods("PUT")
+	router.HandleFunc(base_url+"/bout/{bout_id}/history", boutHandler.GetBoutStatusHistory).Methods("GET")
 	router.HandleFunc(base_url+"/bouts/pending/{athlete_id}", boutHandler.GetPendingBouts).Methods("GET")
 	router.HandleFunc(base_url+"/bouts/incomplete/{athlete_id}", boutHandler.GetIncompleteBouts).Methods("GET")
+	router.HandleFunc(base_url+"/athlete/{athlete_id}/schedule", boutHandler.GetUpcomingBouts).Methods("GET")
+	router.HandleFunc(base_url+"/athlete/{athlete_id}/bouts.ics", boutHandler.GetBoutCalendar).Methods("GET")
+
+	// Open challenge routes
+	router.HandleFunc(base_url+"/challenges/open", openChallengeHandler.GetOpenChallenges).Methods("GET")
+	router.HandleFunc(base_url+"/challenge/open", openChallengeHandler.PostOpenChallenge).Methods("POST")
+	router.HandleFunc(base_url+"/challenge/open/{challenge_id}", openChallengeHandler.GetOpenChallenge).Methods("GET")
+	router.HandleFunc(base_url+"/challenge/open/{challenge_id}/claim", openChallengeHandler.ClaimOpenChallenge).Methods("POST")
+	router.HandleFunc(base_url+"/challenge/open/{challenge_id}/cancel", openChallengeHandler.CancelOpenChallenge).Methods("PUT")
+
+	// Matchmaking routes
+	router.HandleFunc(base_url+"/athlete/{athlete_id}/matchmaking/style/{style_id}", matchmakingHandler.SuggestOpponents).Methods("GET")
 
 	// Outcome routes
 	router.HandleFunc(base_url+"/outcomes", outcomeHandler.GetAllOutcomes).Methods("GET")
 	router.HandleFunc(base_url+"/outcome/{outcome_id}", outcomeHandler.GetOutcome).Methods("GET")
-	router.HandleFunc(base_url+"/outcome", outcomeHandler.CreateOutcome).Methods("POST")
+	router.HandleFunc(base_url+"/outcome", idempotencyService.Idempotent(outcomeHandler.CreateOutcome)).Methods("POST")
 	router.HandleFunc(base_url+"/outcome/bout/{bout_id}", outcomeHandler.GetOutcomeByBout).Methods("GET")
-	router.HandleFunc(base_url+"/outcome/bout/{bout_id}", outcomeHandler.CreateOutcomeByBout).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/bout/{bout_id}", idempotencyService.Idempotent(outcomeHandler.CreateOutcomeByBout)).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/void", idempotencyService.Idempotent(outcomeHandler.VoidOutcome)).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/void", outcomeHandler.GetOutcomeVoid).Methods("GET")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/confirm", idempotencyService.Idempotent(outcomeHandler.ConfirmOutcome)).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/dispute", idempotencyService.Idempotent(outcomeHandler.DisputeOutcome)).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/resolve", idempotencyService.Idempotent(outcomeHandler.ResolveOutcome)).Methods("POST")
+	router.HandleFunc(base_url+"/outcome/{outcome_id}/review", outcomeHandler.GetOutcomeReview).Methods("GET")
 
 	// Style routes
 	router.HandleFunc(base_url+"/styles", styleHandler.GetAllStyles).Methods("GET")
@@ -127,11 +213,34 @@ func CreateRouter() *mux.Router {
 	router.HandleFunc(base_url+"/style/athlete/{athlete_id}", styleHandler.RegisterAthleteToStyle).Methods("POST")
 	router.HandleFunc(base_url+"/styles/athlete/{athlete_id}", styleHandler.RegisterMultipleStylesToAthlete).Methods("POST")
 	router.HandleFunc(base_url+"/styles/common/{athlete_id}/{challenger_id}", styleHandler.GetCommonStyles).Methods("GET")
+	router.HandleFunc(base_url+"/style/{style_id}/challenge-expiry", styleHandler.SetChallengeExpiry).Methods("PUT")
 
 	// Athlete Score routes
 	router.HandleFunc(base_url+"/score/{athlete_id}", athleteScoreHandler.GetAthleteScore).Methods("GET")
 	router.HandleFunc(base_url+"/score/{athlete_id}/all", athleteScoreHandler.GetAthleteScore).Methods("GET")
 	router.HandleFunc(base_url+"/score/{athlete_id}/style/{style_id}", athleteScoreHandler.GetAthleteScoreByStyle).Methods("GET")
+	router.HandleFunc(base_url+"/score/{athlete_id}/style/{style_id}/history", athleteScoreHandler.GetAthleteScoreHistoryByStyle).Methods("GET")
+	router.HandleFunc(base_url+"/score/preview/{challenger_id}/{acceptor_id}/style/{style_id}", athleteScoreHandler.PreviewBout).Methods("GET")
+	router.HandleFunc(base_url+"/style/{style_id}/rating-config", athleteScoreHandler.GetRatingConfig).Methods("GET")
+	router.HandleFunc(base_url+"/style/{style_id}/rating-config", athleteScoreHandler.SetRatingConfig).Methods("PUT")
+	router.HandleFunc(base_url+"/ratings/replay", ratingReplayHandler.ReplayRatings).Methods("POST")
+
+	// Overall rating routes
+	router.HandleFunc(base_url+"/athlete/{athlete_id}/overall-rating", overallRatingHandler.GetAthleteOverallRating).Methods("GET")
+	router.HandleFunc(base_url+"/leaderboard/overall", overallRatingHandler.GetOverallLeaderboard).Methods("GET")
+	router.HandleFunc(base_url+"/ratings/overall-config", overallRatingHandler.GetOverallRatingConfig).Methods("GET")
+	router.HandleFunc(base_url+"/ratings/overall-config", overallRatingHandler.SetOverallRatingConfig).Methods("PUT")
+
+	// Season routes
+	router.HandleFunc(base_url+"/style/{style_id}/seasons", seasonHandler.GetSeasonsByStyle).Methods("GET")
+	router.HandleFunc(base_url+"/style/{style_id}/season", seasonHandler.CreateSeason).Methods("POST")
+	router.HandleFunc(base_url+"/season/{season_id}", seasonHandler.GetSeason).Methods("GET")
+	router.HandleFunc(base_url+"/season/{season_id}/close", seasonHandler.CloseSeason).Methods("POST")
+	router.HandleFunc(base_url+"/season/{season_id}/standings", seasonHandler.GetSeasonStandings).Methods("GET")
+
+	// Notification routes
+	router.HandleFunc(base_url+"/athlete/{athlete_id}/notifications", notificationHandler.GetNotifications).Methods("GET")
+	router.HandleFunc(base_url+"/notification/{notification_id}/read", notificationHandler.MarkNotificationRead).Methods("PUT")
 
 	// Feed routes
 	router.HandleFunc(base_url+"/feed/{athlete_id}", feedHandler.GetFeedByAthleteID).Methods("GET")
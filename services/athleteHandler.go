@@ -7,6 +7,7 @@ import (
 
 	"ronin/interfaces"
 	"ronin/models"
+	"ronin/utils"
 
 	"github.com/gorilla/mux"
 )
@@ -140,9 +141,16 @@ func (h *AthleteHandler) IsAuthorizedUser(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	token, err := utils.IssueAuthToken(returnedAthlete.AthleteId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	SendJSON(w, map[string]interface{}{
 		"athleteId": returnedAthlete.AthleteId,
 		"success":   true,
+		"token":     token,
 	})
 }
 
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"ronin/models"
+
 	"github.com/gorilla/mux"
 )
 
@@ -76,3 +78,126 @@ func (h *AthleteScoreHandler) GetAthleteScoreByStyle(w http.ResponseWriter, r *h
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
+
+func (h *AthleteScoreHandler) GetAthleteScoreHistoryByStyle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["athlete_id"])
+	if err != nil {
+		log.Printf("Invalid athlete_id: %v\n", err)
+		http.Error(w, "Invalid athlete_id", http.StatusBadRequest)
+		return
+	}
+
+	style, err := strconv.Atoi(vars["style_id"])
+	if err != nil {
+		log.Printf("Invalid style_id: %v\n", err)
+		http.Error(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetAthleteScoreHistoryByStyle(id, style)
+	if err != nil {
+		log.Printf("Error getting athlete score history: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		log.Printf("Error encoding response: %v\n", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+func (h *AthleteScoreHandler) PreviewBout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	challenger, err := strconv.Atoi(vars["challenger_id"])
+	if err != nil {
+		log.Printf("Invalid challenger_id: %v\n", err)
+		http.Error(w, "Invalid challenger_id", http.StatusBadRequest)
+		return
+	}
+
+	acceptor, err := strconv.Atoi(vars["acceptor_id"])
+	if err != nil {
+		log.Printf("Invalid acceptor_id: %v\n", err)
+		http.Error(w, "Invalid acceptor_id", http.StatusBadRequest)
+		return
+	}
+
+	style, err := strconv.Atoi(vars["style_id"])
+	if err != nil {
+		log.Printf("Invalid style_id: %v\n", err)
+		http.Error(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.service.PreviewBout(challenger, acceptor, style)
+	if err != nil {
+		log.Printf("Error previewing bout: %v\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(preview); err != nil {
+		log.Printf("Error encoding response: %v\n", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+func (h *AthleteScoreHandler) GetRatingConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	style, err := strconv.Atoi(vars["style_id"])
+	if err != nil {
+		log.Printf("Invalid style_id: %v\n", err)
+		http.Error(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.service.GetRatingConfig(style)
+	if err != nil {
+		log.Printf("Error getting rating config: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		log.Printf("Error encoding response: %v\n", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+func (h *AthleteScoreHandler) SetRatingConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	style, err := strconv.Atoi(vars["style_id"])
+	if err != nil {
+		log.Printf("Invalid style_id: %v\n", err)
+		http.Error(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	var config models.StyleRatingConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	config.StyleId = style
+
+	if err := h.service.SetRatingConfig(config, authenticatedAthleteId(r)); err != nil {
+		log.Printf("Error saving rating config: %v\n", err)
+		http.Error(w, err.Error(), authorizationErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		log.Printf("Error encoding response: %v\n", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
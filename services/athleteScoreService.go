@@ -1,22 +1,25 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
-	"log"
-	"math"
+	"fmt"
 	"strconv"
+	"time"
 
 	"ronin/models"
 	"ronin/repositories"
 )
 
 type AthleteScoreService struct {
-	repo *repositories.AthleteScoreRepository
+	repo       *repositories.AthleteScoreRepository
+	configRepo *repositories.RatingConfigRepository
 }
 
-func NewAthleteScoreService(repo *repositories.AthleteScoreRepository) *AthleteScoreService {
+func NewAthleteScoreService(repo *repositories.AthleteScoreRepository, configRepo *repositories.RatingConfigRepository) *AthleteScoreService {
 	return &AthleteScoreService{
-		repo: repo,
+		repo:       repo,
+		configRepo: configRepo,
 	}
 }
 
@@ -36,41 +39,262 @@ func (s *AthleteScoreService) GetAthleteScoreByStyle(athleteId, styleId int) (mo
 	return score, nil
 }
 
+// GetAthleteScoreHistoryByStyle returns every rating change for an athlete in a style, oldest first
+func (s *AthleteScoreService) GetAthleteScoreHistoryByStyle(athleteId, styleId int) ([]models.AthleteScoreHistory, error) {
+	history, err := s.repo.GetAthleteScoreHistoryByStyle(athleteId, styleId)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (s *AthleteScoreService) CreateAthleteScoreUponRegistration(athleteId, styleId int) error {
 	return s.repo.CreateAthleteScoreUponRegistration(athleteId, styleId)
 }
 
-func (s *AthleteScoreService) CalculateNewScores(winnerScore, loserScore *models.AthleteScore, isDraw bool, outcomeId int) error {
-	if winnerScore == nil || loserScore == nil {
-		return errors.New("both winner and loser scores must be provided")
+// GetRatingConfig returns the rating config for a style, falling back to the default Elo engine
+func (s *AthleteScoreService) GetRatingConfig(styleId int) (models.StyleRatingConfig, error) {
+	config, err := s.configRepo.GetRatingConfigByStyle(styleId)
+	if err == sql.ErrNoRows {
+		return models.StyleRatingConfig{StyleId: styleId, Engine: EngineElo}, nil
+	}
+	if err != nil {
+		return models.StyleRatingConfig{}, fmt.Errorf("failed to get rating config for style %d: %w", styleId, err)
 	}
+	return config, nil
+}
 
-	// Calculate Elo rating changes
-	expectedScoreWinner := 1.0 / (1.0 + math.Pow(10, (loserScore.Score-winnerScore.Score)/400.0))
-	expectedScoreLoser := 1.0 / (1.0 + math.Pow(10, (winnerScore.Score-loserScore.Score)/400.0))
+// SetRatingConfig validates and stores the rating engine selection for a style. Only an admin can change it.
+func (s *AthleteScoreService) SetRatingConfig(config models.StyleRatingConfig, actorID int) error {
+	if err := requireAdmin(actorID); err != nil {
+		return err
+	}
+	if config.StyleId == 0 {
+		return errors.New("style ID is required")
+	}
+	if config.Engine == "" {
+		config.Engine = EngineElo
+	}
 
-	// K-factor for rating adjustment (can be adjusted based on requirements)
-	k := 32.0
+	if _, err := newStyleRating(config); err != nil {
+		return fmt.Errorf("invalid rating config: %w", err)
+	}
 
-	var newWinnerScore, newLoserScore float64
-	if isDraw {
-		newWinnerScore = winnerScore.Score + k*(0.5-expectedScoreWinner)
-		newLoserScore = loserScore.Score + k*(0.5-expectedScoreLoser)
-	} else {
-		newWinnerScore = winnerScore.Score + k*(1.0-expectedScoreWinner)
-		newLoserScore = loserScore.Score + k*(0.0-expectedScoreLoser)
+	if err := s.configRepo.UpsertRatingConfig(config); err != nil {
+		return fmt.Errorf("failed to save rating config: %w", err)
 	}
+	return nil
+}
 
-	// Update scores in repository
-	if err := s.repo.UpdateAthleteScore(int(newWinnerScore), winnerScore.AthleteId, winnerScore.StyleId, outcomeId); err != nil {
-		log.Printf("Error updating winner score: %v", err)
-		return err
+// ratingForStyle resolves the rating engine and margin-of-victory rules configured for a style
+func (s *AthleteScoreService) ratingForStyle(styleId int) (styleRating, error) {
+	config, err := s.GetRatingConfig(styleId)
+	if err != nil {
+		return styleRating{}, err
 	}
 
-	if err := s.repo.UpdateAthleteScore(int(newLoserScore), loserScore.AthleteId, loserScore.StyleId, outcomeId); err != nil {
-		log.Printf("Error updating loser score: %v", err)
-		return err
+	rating, err := newStyleRating(config)
+	if err != nil {
+		return styleRating{}, fmt.Errorf("failed to build rating engine for style %d: %w", styleId, err)
+	}
+	return rating, nil
+}
+
+// LockStyleRatings takes a style's rating lock inside a shared transaction. RateOutcome takes it too;
+// transactions that lock other rows before rating take it first so every path locks in the same order.
+func (s *AthleteScoreService) LockStyleRatings(tx *repositories.Tx, styleId int) error {
+	if err := s.repo.LockStyleScores(tx, styleId); err != nil {
+		return fmt.Errorf("failed to lock ratings of style %d: %w", styleId, err)
+	}
+	return nil
+}
+
+// RateOutcome works out both athletes' new ratings for an outcome without saving them. Both scores
+// are read and locked inside the shared transaction, so a concurrent outcome for either athlete waits
+// for it to commit instead of rating from the same scores. Outcomes of unrated bouts, and those the
+// style's rules leave unrated, return an unrated result.
+func (s *AthleteScoreService) RateOutcome(tx *repositories.Tx, outcome models.Outcome) (models.RatingResult, error) {
+	if !outcome.Rated {
+		return models.RatingResult{Unrated: true}, nil
+	}
+
+	scores, err := s.repo.GetAthleteScoresByStyleForUpdate(tx, outcome.StyleId, outcome.WinnerId, outcome.LoserId)
+	if err != nil {
+		return models.RatingResult{}, fmt.Errorf("failed to get athlete scores: %w", err)
+	}
+	winnerScore, loserScore := scores[outcome.WinnerId], scores[outcome.LoserId]
+
+	rating, err := s.ratingForStyle(outcome.StyleId)
+	if err != nil {
+		return models.RatingResult{}, err
+	}
+
+	now := time.Now()
+	rematch, err := s.rematchHistory(rating, outcome.WinnerId, outcome.LoserId, outcome.StyleId, now)
+	if err != nil {
+		return models.RatingResult{}, err
 	}
 
+	return s.rateOutcome(rating, models.RatingInput{
+		Winner:       winnerScore,
+		Loser:        loserScore,
+		IsDraw:       outcome.IsDraw,
+		FinishMethod: outcome.FinishMethod,
+		ScoreMargin:  outcome.ScoreMargin,
+		PlayedAt:     now,
+		Rematch:      rematch,
+	})
+}
+
+// SaveRatingResult writes both athletes' new ratings for a recorded outcome inside a shared
+// transaction. Unrated results write nothing, and a winner whose rating is unchanged gets no new row.
+func (s *AthleteScoreService) SaveRatingResult(tx *repositories.Tx, result models.RatingResult, outcomeId int) error {
+	if result.Unrated {
+		return nil
+	}
+
+	if !result.WinnerUnchanged {
+		if err := s.repo.AppendAthleteScore(tx, result.Winner, outcomeId); err != nil {
+			return fmt.Errorf("failed to update winner score: %w", err)
+		}
+	}
+	if err := s.repo.AppendAthleteScore(tx, result.Loser, outcomeId); err != nil {
+		return fmt.Errorf("failed to update loser score: %w", err)
+	}
 	return nil
 }
+
+// rateOutcome runs an outcome through a style's rating engine and margin-of-victory rules without
+// persisting anything. Live outcomes and rating replays both go through here so they share the same math.
+func (s *AthleteScoreService) rateOutcome(rating styleRating, input models.RatingInput) (models.RatingResult, error) {
+	result, err := rating.engine.Rate(input)
+	if err != nil {
+		return models.RatingResult{}, fmt.Errorf("%s engine failed to rate outcome: %w", rating.engine.Name(), err)
+	}
+
+	result = rating.margin.apply(input, result)
+	if result.Unrated {
+		return result, nil
+	}
+
+	result = rating.rematch.apply(input, result)
+	if result.Unrated {
+		return result, nil
+	}
+
+	// Completing a bout clears the provisional flag set on returning athletes
+	for _, change := range ratedChanges(&result) {
+		change.RatedBouts++
+		change.Provisional = false
+		change.Reason = models.ScoreReasonOutcome
+	}
+	return result, nil
+}
+
+// ratedChanges returns the rating changes an outcome counts as a rated bout for: both athletes',
+// or only the loser's when the winner's rating is left unchanged
+func ratedChanges(result *models.RatingResult) []*models.RatingChange {
+	if result.WinnerUnchanged {
+		return []*models.RatingChange{&result.Loser}
+	}
+	return []*models.RatingChange{&result.Winner, &result.Loser}
+}
+
+// rematchHistory loads how often two athletes were already rated against each other in a style's
+// rematch period
+func (s *AthleteScoreService) rematchHistory(rating styleRating, athleteA, athleteB, styleId int, now time.Time) (models.RematchHistory, error) {
+	history, err := s.repo.GetRematchHistory(athleteA, athleteB, styleId, rating.rematch.periodStart(now))
+	if err != nil {
+		return models.RematchHistory{}, fmt.Errorf("failed to get rematch history: %w", err)
+	}
+	return history, nil
+}
+
+// RematchViolation reports why a new rated bout between two athletes would break the style's rematch
+// limits, along with the style's rule for such bouts. An empty reason means the bout can be rated.
+func (s *AthleteScoreService) RematchViolation(athleteA, athleteB, styleId int) (string, string, error) {
+	rating, err := s.ratingForStyle(styleId)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	history, err := s.rematchHistory(rating, athleteA, athleteB, styleId, now)
+	if err != nil {
+		return "", "", err
+	}
+	return rating.rematch.violation(history, now), rating.rematch.RematchOverLimitRule, nil
+}
+
+// PreviewBout returns each athlete's win probability and the rating change for every result of a
+// proposed bout. It runs the same math as RateOutcome and writes nothing.
+func (s *AthleteScoreService) PreviewBout(challengerId, acceptorId, styleId int) (models.BoutPreview, error) {
+	if challengerId == acceptorId {
+		return models.BoutPreview{}, errors.New("challenger and acceptor cannot be the same athlete")
+	}
+
+	challenger, err := s.repo.GetAthleteScoreByStyle(challengerId, styleId)
+	if err != nil {
+		return models.BoutPreview{}, fmt.Errorf("failed to get challenger score: %w", err)
+	}
+
+	acceptor, err := s.repo.GetAthleteScoreByStyle(acceptorId, styleId)
+	if err != nil {
+		return models.BoutPreview{}, fmt.Errorf("failed to get acceptor score: %w", err)
+	}
+
+	rating, err := s.ratingForStyle(styleId)
+	if err != nil {
+		return models.BoutPreview{}, err
+	}
+
+	now := time.Now()
+	rematch, err := s.rematchHistory(rating, challengerId, acceptorId, styleId, now)
+	if err != nil {
+		return models.BoutPreview{}, err
+	}
+
+	challengerWins, err := s.rateOutcome(rating, models.RatingInput{Winner: challenger, Loser: acceptor, PlayedAt: now, Rematch: rematch})
+	if err != nil {
+		return models.BoutPreview{}, err
+	}
+	acceptorWins, err := s.rateOutcome(rating, models.RatingInput{Winner: acceptor, Loser: challenger, PlayedAt: now, Rematch: rematch})
+	if err != nil {
+		return models.BoutPreview{}, err
+	}
+	draw, err := s.rateOutcome(rating, models.RatingInput{Winner: challenger, Loser: acceptor, IsDraw: true, PlayedAt: now, Rematch: rematch})
+	if err != nil {
+		return models.BoutPreview{}, err
+	}
+
+	return models.BoutPreview{
+		StyleId: styleId,
+		Engine:  rating.engine.Name(),
+		Challenger: models.BoutPreviewSide{
+			AthleteId:       challengerId,
+			Score:           challenger.Score,
+			RatingDeviation: challenger.RatingDeviation,
+			WinProbability:  rating.engine.ExpectedScore(challenger, acceptor),
+			DeltaOnWin:      ratingDelta(challenger, challengerWins.Winner, challengerWins.Unrated),
+			DeltaOnLoss:     ratingDelta(challenger, acceptorWins.Loser, acceptorWins.Unrated),
+			DeltaOnDraw:     ratingDelta(challenger, draw.Winner, draw.Unrated),
+		},
+		Acceptor: models.BoutPreviewSide{
+			AthleteId:       acceptorId,
+			Score:           acceptor.Score,
+			RatingDeviation: acceptor.RatingDeviation,
+			WinProbability:  rating.engine.ExpectedScore(acceptor, challenger),
+			DeltaOnWin:      ratingDelta(acceptor, acceptorWins.Winner, acceptorWins.Unrated),
+			DeltaOnLoss:     ratingDelta(acceptor, challengerWins.Loser, challengerWins.Unrated),
+			DeltaOnDraw:     ratingDelta(acceptor, draw.Loser, draw.Unrated),
+		},
+	}, nil
+}
+
+// ratingDelta is how far a rating change moves an athlete's score
+func ratingDelta(before models.AthleteScore, after models.RatingChange, unrated bool) float64 {
+	if unrated {
+		return 0
+	}
+	return after.Score - before.Score
+}
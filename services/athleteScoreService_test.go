@@ -0,0 +1,126 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestExpectedScore(t *testing.T) {
+	elo, err := NewEloRatingEngine(DefaultEloSettings())
+	if err != nil {
+		t.Fatalf("NewEloRatingEngine() error = %v", err)
+	}
+	glicko, err := NewGlicko2RatingEngine(DefaultGlicko2Settings())
+	if err != nil {
+		t.Fatalf("NewGlicko2RatingEngine() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		engine   string
+		athlete  models.AthleteScore
+		opponent models.AthleteScore
+		want     float64
+	}{
+		{name: "elo equals", engine: EngineElo, athlete: models.AthleteScore{Score: 400}, opponent: models.AthleteScore{Score: 400}, want: 0.5},
+		{name: "elo favourite", engine: EngineElo, athlete: models.AthleteScore{Score: 800}, opponent: models.AthleteScore{Score: 400}, want: 10.0 / 11},
+		{name: "elo underdog", engine: EngineElo, athlete: models.AthleteScore{Score: 400}, opponent: models.AthleteScore{Score: 800}, want: 1.0 / 11},
+		{name: "glicko-2 equals", engine: EngineGlicko2, athlete: models.AthleteScore{Score: 1500, RatingDeviation: 50}, opponent: models.AthleteScore{Score: 1500, RatingDeviation: 50}, want: 0.5},
+		{name: "glicko-2 certain favourite", engine: EngineGlicko2, athlete: models.AthleteScore{Score: 1700, RatingDeviation: 50}, opponent: models.AthleteScore{Score: 1500, RatingDeviation: 50}, want: 0.754610},
+		{name: "glicko-2 uncertain favourite", engine: EngineGlicko2, athlete: models.AthleteScore{Score: 1700, RatingDeviation: 350}, opponent: models.AthleteScore{Score: 1500, RatingDeviation: 350}, want: 0.649820},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := elo
+			if tt.engine == EngineGlicko2 {
+				engine = glicko
+			}
+			got := engine.ExpectedScore(tt.athlete, tt.opponent)
+			if math.Abs(got-tt.want) > 0.000001 {
+				t.Errorf("ExpectedScore() = %v, want %v", got, tt.want)
+			}
+			if reverse := engine.ExpectedScore(tt.opponent, tt.athlete); math.Abs(got+reverse-1) > 0.000001 {
+				t.Errorf("win probabilities add up to %v, want 1", got+reverse)
+			}
+		})
+	}
+}
+
+func TestRatingDelta(t *testing.T) {
+	before := models.AthleteScore{Score: 400}
+	after := models.RatingChange{AthleteScore: models.AthleteScore{Score: 416}}
+
+	if got := ratingDelta(before, after, false); got != 16 {
+		t.Errorf("ratingDelta() = %v, want 16", got)
+	}
+	if got := ratingDelta(before, after, true); got != 0 {
+		t.Errorf("ratingDelta() for an unrated result = %v, want 0", got)
+	}
+}
+
+func TestRateOutcomeCountsBoutAndClearsProvisional(t *testing.T) {
+	rating, err := newStyleRating(models.StyleRatingConfig{Engine: EngineElo})
+	if err != nil {
+		t.Fatalf("newStyleRating() error = %v", err)
+	}
+
+	result, err := (&AthleteScoreService{}).rateOutcome(rating, models.RatingInput{
+		Winner: models.AthleteScore{AthleteId: 1, Score: 400, RatedBouts: 3, Provisional: true},
+		Loser:  models.AthleteScore{AthleteId: 2, Score: 400, RatedBouts: 7},
+	})
+	if err != nil {
+		t.Fatalf("rateOutcome() error = %v", err)
+	}
+
+	for _, change := range []struct {
+		who       string
+		got       models.RatingChange
+		wantRated int
+	}{
+		{who: "winner", got: result.Winner, wantRated: 4},
+		{who: "loser", got: result.Loser, wantRated: 8},
+	} {
+		if change.got.RatedBouts != change.wantRated {
+			t.Errorf("%s rated bouts = %d, want %d", change.who, change.got.RatedBouts, change.wantRated)
+		}
+		if change.got.Provisional {
+			t.Errorf("%s is still provisional after a rated bout", change.who)
+		}
+		if change.got.Reason != models.ScoreReasonOutcome {
+			t.Errorf("%s reason = %q, want %q", change.who, change.got.Reason, models.ScoreReasonOutcome)
+		}
+	}
+}
+
+func TestRateOutcomeLoserOnlyLeavesWinnerUnchanged(t *testing.T) {
+	rating, err := newStyleRating(models.StyleRatingConfig{Engine: EngineElo, Settings: []byte(`{"forfeitRule": "loser_only"}`)})
+	if err != nil {
+		t.Fatalf("newStyleRating() error = %v", err)
+	}
+
+	winner := models.AthleteScore{AthleteId: 1, Score: 400, RatedBouts: 3, Provisional: true}
+	result, err := (&AthleteScoreService{}).rateOutcome(rating, models.RatingInput{
+		Winner:       winner,
+		Loser:        models.AthleteScore{AthleteId: 2, Score: 400, RatedBouts: 7, Provisional: true},
+		FinishMethod: models.FinishForfeit,
+	})
+	if err != nil {
+		t.Fatalf("rateOutcome() error = %v", err)
+	}
+
+	if !result.WinnerUnchanged {
+		t.Fatalf("WinnerUnchanged = false for a loser-only forfeit")
+	}
+	if result.Winner.AthleteScore != winner {
+		t.Errorf("winner = %+v, want unchanged %+v", result.Winner.AthleteScore, winner)
+	}
+	if result.Loser.RatedBouts != 8 || result.Loser.Provisional {
+		t.Errorf("loser rated bouts = %d, provisional = %v, want 8 and false", result.Loser.RatedBouts, result.Loser.Provisional)
+	}
+	if got := ratedChanges(&result); len(got) != 1 || got[0] != &result.Loser {
+		t.Errorf("ratedChanges() = %v, want only the loser", got)
+	}
+}
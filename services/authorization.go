@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ronin/models"
+	"ronin/utils"
+)
+
+// ErrUnauthenticated is returned when an action needs a signed-in athlete and there is none
+var ErrUnauthenticated = errors.New("authentication required")
+
+// Roles an athlete can hold in a bout
+const (
+	BoutRoleChallenger = "challenger"
+	BoutRoleAcceptor   = "acceptor"
+	BoutRoleReferee    = "referee"
+	// BoutRoleCompetitor is held by both the challenger and the acceptor
+	BoutRoleCompetitor = "competitor"
+	// BoutRoleResponder is held by the competitor whose turn it is to answer a proposed bout
+	BoutRoleResponder = "responder"
+)
+
+// AuthorizationError is returned when the signed-in athlete does not hold the role an action
+// requires, either platform-wide or on a bout
+type AuthorizationError struct {
+	AthleteId int
+	BoutId    int
+	Role      string
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.BoutId == 0 {
+		return fmt.Sprintf("athlete %d is not an %s", e.AthleteId, e.Role)
+	}
+	return fmt.Sprintf("athlete %d is not the %s of bout %d", e.AthleteId, e.Role, e.BoutId)
+}
+
+// requireBoutRole checks that the acting athlete holds the given role in a bout
+func requireBoutRole(bout models.Bout, actorId int, role string) error {
+	if actorId == 0 {
+		return ErrUnauthenticated
+	}
+
+	var holdsRole bool
+	switch role {
+	case BoutRoleChallenger:
+		holdsRole = actorId == bout.ChallengerId
+	case BoutRoleAcceptor:
+		holdsRole = actorId == bout.AcceptorId
+	case BoutRoleReferee:
+		holdsRole = actorId == bout.RefereeId
+	case BoutRoleCompetitor:
+		holdsRole = actorId == bout.ChallengerId || actorId == bout.AcceptorId
+	case BoutRoleResponder:
+		holdsRole = actorId == bout.ResponderId
+	}
+
+	if !holdsRole {
+		return &AuthorizationError{AthleteId: actorId, BoutId: bout.BoutId, Role: role}
+	}
+	return nil
+}
+
+// requireAdmin checks that the acting athlete is a platform admin
+func requireAdmin(actorId int) error {
+	if actorId == 0 {
+		return ErrUnauthenticated
+	}
+	if !utils.IsAdmin(actorId) {
+		return &AuthorizationError{AthleteId: actorId, Role: "admin"}
+	}
+	return nil
+}
+
+// requireBoutRoleOrAdmin checks that the acting athlete holds the given role in a bout or is a
+// platform admin. Without a bout only admins pass.
+func requireBoutRoleOrAdmin(bout *models.Bout, actorId int, role string) error {
+	if actorId == 0 {
+		return ErrUnauthenticated
+	}
+	if utils.IsAdmin(actorId) {
+		return nil
+	}
+	if bout == nil {
+		return requireAdmin(actorId)
+	}
+	return requireBoutRole(*bout, actorId, role)
+}
+
+// requireAdminOrGymOwner checks that the acting athlete is a platform admin or owns the gym a bout
+// was held at. gymOwnerId is 0 when the bout has no gym or the gym has no owner.
+func requireAdminOrGymOwner(bout models.Bout, gymOwnerId int, actorId int) error {
+	if actorId == 0 {
+		return ErrUnauthenticated
+	}
+	if utils.IsAdmin(actorId) || (gymOwnerId != 0 && actorId == gymOwnerId) {
+		return nil
+	}
+	return &AuthorizationError{AthleteId: actorId, BoutId: bout.BoutId, Role: "gym owner"}
+}
+
+// authorizationErrorStatus maps an authentication or authorization error to 401 or 403, and any
+// other error to otherwise
+func authorizationErrorStatus(err error, otherwise int) int {
+	var authorizationErr *AuthorizationError
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.As(err, &authorizationErr):
+		return http.StatusForbidden
+	}
+	return otherwise
+}
+
+// authenticatedAthleteId returns the athlete resolved from the request's auth token, or 0 if anonymous
+func authenticatedAthleteId(r *http.Request) int {
+	return utils.AthleteIdFromContext(r.Context())
+}
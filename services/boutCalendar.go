@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ronin/models"
+)
+
+const (
+	calendarTimeFormat = "20060102T150405Z"
+	calendarLineLimit  = 75
+)
+
+// calendarEscaper escapes TEXT values as required by RFC 5545
+var calendarEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// boutCalendarUID is the stable identifier of a bout's event, so calendar apps update and remove
+// the same event as the bout changes
+func boutCalendarUID(boutId int) string {
+	return fmt.Sprintf("bout-%d@ronin", boutId)
+}
+
+// buildBoutCalendar renders an athlete's bouts as an iCalendar feed. Bouts without a start time
+// are left out because an event needs one.
+func buildBoutCalendar(athleteId int, bouts []models.OutboundBout, now time.Time) (string, error) {
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Ronin//Bouts//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Ronin bouts",
+	)
+
+	for _, bout := range bouts {
+		if bout.ScheduledAt == nil {
+			continue
+		}
+		event, err := boutCalendarEvent(athleteId, bout, now)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, event...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var calendar strings.Builder
+	for _, line := range lines {
+		calendar.WriteString(foldCalendarLine(line))
+		calendar.WriteString("\r\n")
+	}
+	return calendar.String(), nil
+}
+
+// boutCalendarEvent renders one bout as a VEVENT from the athlete's point of view
+func boutCalendarEvent(athleteId int, bout models.OutboundBout, now time.Time) ([]string, error) {
+	start, err := time.Parse(time.RFC3339Nano, *bout.ScheduledAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse start time of bout %d: %w", bout.BoutId, err)
+	}
+	end := start.Add(time.Duration(bout.DurationMinutes) * time.Minute)
+
+	challenger := bout.ChallengerFirstName + " " + bout.ChallengerLastName
+	acceptor := bout.AcceptorFirstName + " " + bout.AcceptorLastName
+	referee := bout.RefereeFirstName + " " + bout.RefereeLastName
+
+	var summary string
+	switch athleteId {
+	case bout.ChallengerId:
+		summary = fmt.Sprintf("%s vs %s", bout.Style, acceptor)
+	case bout.AcceptorId:
+		summary = fmt.Sprintf("%s vs %s", bout.Style, challenger)
+	default:
+		summary = fmt.Sprintf("Refereeing %s: %s vs %s", bout.Style, challenger, acceptor)
+	}
+	description := fmt.Sprintf("Style: %s\nChallenger: %s\nAcceptor: %s\nReferee: %s", bout.Style, challenger, acceptor, referee)
+
+	status := "CONFIRMED"
+	if bout.Status == models.BoutStatusAccepted {
+		status = "TENTATIVE"
+	}
+
+	event := []string{
+		"BEGIN:VEVENT",
+		"UID:" + boutCalendarUID(bout.BoutId),
+		"DTSTAMP:" + now.UTC().Format(calendarTimeFormat),
+		"DTSTART:" + start.UTC().Format(calendarTimeFormat),
+		"DTEND:" + end.UTC().Format(calendarTimeFormat),
+		"SUMMARY:" + calendarEscaper.Replace(summary),
+		"DESCRIPTION:" + calendarEscaper.Replace(description),
+		"STATUS:" + status,
+	}
+	if bout.GymName != nil {
+		event = append(event, "LOCATION:"+calendarEscaper.Replace(*bout.GymName))
+	}
+	if updated, err := time.Parse(time.RFC3339Nano, bout.UpdatedDate); err == nil {
+		event = append(event, "LAST-MODIFIED:"+updated.UTC().Format(calendarTimeFormat))
+	}
+	return append(event, "END:VEVENT"), nil
+}
+
+// foldCalendarLine splits a content line into 75-octet pieces joined by CRLF and a space,
+// without breaking a multi-byte character
+func foldCalendarLine(line string) string {
+	if len(line) <= calendarLineLimit {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := calendarLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = calendarLineLimit - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
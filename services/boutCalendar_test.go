@@ -0,0 +1,111 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"ronin/models"
+)
+
+func TestBuildBoutCalendar(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := "2026-03-05T19:30:00+01:00"
+	gym := "Dojo North, Hall 2"
+	bouts := []models.OutboundBout{
+		{
+			BoutId: 7, ChallengerId: 1, ChallengerFirstName: "Ana", ChallengerLastName: "Silva",
+			AcceptorId: 2, AcceptorFirstName: "Ben", AcceptorLastName: "Okafor",
+			RefereeId: 3, RefereeFirstName: "Cy", RefereeLastName: "Tan",
+			Style: "Judo", Status: models.BoutStatusAccepted, ScheduledAt: &scheduled, DurationMinutes: 45,
+			GymName: &gym, UpdatedDate: "2026-02-20T08:00:00Z",
+		},
+		// Not scheduled yet, so it has no event
+		{BoutId: 8, ChallengerId: 1, AcceptorId: 4, Style: "Judo", Status: models.BoutStatusAccepted},
+	}
+
+	tests := []struct {
+		name        string
+		athleteId   int
+		wantSummary string
+	}{
+		{name: "challenger", athleteId: 1, wantSummary: "SUMMARY:Judo vs Ben Okafor"},
+		{name: "acceptor", athleteId: 2, wantSummary: "SUMMARY:Judo vs Ana Silva"},
+		{name: "referee", athleteId: 3, wantSummary: "SUMMARY:Refereeing Judo: Ana Silva vs Ben Okafor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar, err := buildBoutCalendar(tt.athleteId, bouts, now)
+			if err != nil {
+				t.Fatalf("buildBoutCalendar() error = %v", err)
+			}
+			if !strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(calendar, "END:VCALENDAR\r\n") {
+				t.Errorf("calendar is not wrapped in VCALENDAR with CRLF line endings:\n%s", calendar)
+			}
+			if got := strings.Count(calendar, "BEGIN:VEVENT"); got != 1 {
+				t.Errorf("calendar has %d events, want 1", got)
+			}
+
+			lines := strings.Split(calendar, "\r\n")
+			for _, want := range []string{
+				tt.wantSummary,
+				"UID:bout-7@ronin",
+				"DTSTAMP:20260301T120000Z",
+				"DTSTART:20260305T183000Z",
+				"DTEND:20260305T191500Z",
+				`LOCATION:Dojo North\, Hall 2`,
+				"STATUS:TENTATIVE",
+				"LAST-MODIFIED:20260220T080000Z",
+			} {
+				if !containsLine(lines, want) {
+					t.Errorf("calendar is missing line %q:\n%s", want, calendar)
+				}
+			}
+		})
+	}
+}
+
+func TestFoldCalendarLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "short line", line: "SUMMARY:Judo"},
+		{name: "exactly the limit", line: strings.Repeat("a", calendarLineLimit)},
+		{name: "long ascii line", line: "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{name: "long multi-byte line", line: "DESCRIPTION:" + strings.Repeat("柔道é", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := foldCalendarLine(tt.line)
+			pieces := strings.Split(folded, "\r\n")
+			for i, piece := range pieces {
+				if len(piece) > calendarLineLimit {
+					t.Errorf("piece %d is %d octets, want at most %d", i, len(piece), calendarLineLimit)
+				}
+				if i > 0 && !strings.HasPrefix(piece, " ") {
+					t.Errorf("continuation piece %d does not start with a space", i)
+				}
+				if !utf8.ValidString(piece) {
+					t.Errorf("piece %d splits a multi-byte character", i)
+				}
+			}
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolding gives %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+// containsLine reports whether lines has an exact match for want
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
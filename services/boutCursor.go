@@ -0,0 +1,52 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ronin/models"
+)
+
+// boutSortOrder names the direction of a bout listing
+func boutSortOrder(descending bool) string {
+	if descending {
+		return "desc"
+	}
+	return "asc"
+}
+
+// encodeBoutCursor builds the opaque cursor that continues a listing after the given bout
+func encodeBoutCursor(sort, order string, last models.OutboundBout) (string, error) {
+	cursor := models.BoutCursor{Sort: sort, Order: order, BoutId: last.BoutId}
+	switch sort {
+	case models.BoutSortCreated:
+		cursor.Value = last.CreatedDate
+	case models.BoutSortUpdated:
+		cursor.Value = last.UpdatedDate
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeBoutCursor reads a cursor produced by encodeBoutCursor
+func decodeBoutCursor(value string) (*models.BoutCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor models.BoutCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.BoutId == 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursor.Sort != models.BoutSortId && cursor.Value == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
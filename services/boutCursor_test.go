@@ -0,0 +1,94 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"ronin/models"
+)
+
+func TestBoutCursorRoundTrip(t *testing.T) {
+	last := models.OutboundBout{BoutId: 42, CreatedDate: "2026-03-01T10:00:00Z", UpdatedDate: "2026-03-02T11:30:00Z"}
+
+	tests := []struct {
+		sort      string
+		order     string
+		wantValue string
+	}{
+		{sort: models.BoutSortCreated, order: "desc", wantValue: last.CreatedDate},
+		{sort: models.BoutSortUpdated, order: "asc", wantValue: last.UpdatedDate},
+		{sort: models.BoutSortId, order: "asc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort+" "+tt.order, func(t *testing.T) {
+			encoded, err := encodeBoutCursor(tt.sort, tt.order, last)
+			if err != nil {
+				t.Fatalf("encodeBoutCursor() error = %v", err)
+			}
+			cursor, err := decodeBoutCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeBoutCursor() error = %v", err)
+			}
+			want := models.BoutCursor{Sort: tt.sort, Order: tt.order, Value: tt.wantValue, BoutId: last.BoutId}
+			if *cursor != want {
+				t.Errorf("decodeBoutCursor() = %+v, want %+v", *cursor, want)
+			}
+		})
+	}
+}
+
+func TestDecodeBoutCursorRejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "not json", cursor: encode("created,desc")},
+		{name: "no bout", cursor: encode(`{"sort":"id","order":"asc"}`)},
+		{name: "no sort value", cursor: encode(`{"sort":"created","order":"asc","boutId":3}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeBoutCursor(tt.cursor); err == nil {
+				t.Errorf("decodeBoutCursor(%q) succeeded, want an error", tt.cursor)
+			}
+		})
+	}
+}
+
+func TestListRejectsInvalidFilters(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name   string
+		filter models.BoutListFilter
+	}{
+		{name: "unknown sort", filter: models.BoutListFilter{Sort: "rating"}},
+		{name: "negative limit", filter: models.BoutListFilter{Limit: -1}},
+		{name: "limit above the maximum", filter: models.BoutListFilter{Limit: maxBoutPageLimit + 1}},
+		{name: "unknown status", filter: models.BoutListFilter{Statuses: []string{models.BoutStatusProposed, "pending"}}},
+		{name: "from after to", filter: models.BoutListFilter{From: &from, To: &to}},
+		{name: "cursor for another sort", filter: models.BoutListFilter{Sort: models.BoutSortId,
+			After: &models.BoutCursor{Sort: models.BoutSortCreated, Order: "asc", Value: "2026-03-01T10:00:00Z", BoutId: 3}}},
+		{name: "cursor for another order", filter: models.BoutListFilter{Descending: true,
+			After: &models.BoutCursor{Sort: models.BoutSortCreated, Order: "asc", Value: "2026-03-01T10:00:00Z", BoutId: 3}}},
+	}
+
+	// Invalid filters are rejected before the repository is queried
+	service := &boutService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.List(tt.filter); err == nil {
+				t.Errorf("List() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -2,10 +2,17 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"ronin/interfaces"
 	"ronin/models"
+	"ronin/repositories"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -22,14 +29,87 @@ func NewBoutHandler(service interfaces.BoutService) *BoutHandler {
 	}
 }
 
-// GetAllBouts handles GET requests to retrieve all bouts
-func (h *BoutHandler) GetAllBouts(w http.ResponseWriter, r *http.Request) {
-	bouts, err := h.service.GetAll()
+// ListBouts handles GET requests for a page of bouts. Optional query parameters: athlete_id,
+// style_id, referee_id, status (comma separated), from and to (RFC 3339 or YYYY-MM-DD),
+// sort (created, updated or id), order (asc or desc, default desc), limit and cursor.
+func (h *BoutHandler) ListBouts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseBoutListFilter(r.URL.Query())
 	if err != nil {
-		SendError(w, "Failed to get bouts", http.StatusInternalServerError)
+		SendError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	SendJSON(w, bouts)
+
+	page, err := h.service.List(filter)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	SendJSON(w, page)
+}
+
+// parseBoutListFilter reads a bout listing filter from query parameters
+func parseBoutListFilter(query url.Values) (models.BoutListFilter, error) {
+	filter := models.BoutListFilter{
+		Sort:       query.Get("sort"),
+		Descending: true,
+	}
+
+	for param, target := range map[string]*int{
+		"athlete_id": &filter.AthleteId,
+		"style_id":   &filter.StyleId,
+		"referee_id": &filter.RefereeId,
+		"limit":      &filter.Limit,
+	} {
+		if value := query.Get(param); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return models.BoutListFilter{}, fmt.Errorf("invalid %s", param)
+			}
+			*target = parsed
+		}
+	}
+
+	if status := query.Get("status"); status != "" {
+		filter.Statuses = strings.Split(status, ",")
+	}
+
+	for param, target := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if value := query.Get(param); value != "" {
+			parsed, err := parseBoutListDate(value)
+			if err != nil {
+				return models.BoutListFilter{}, fmt.Errorf("invalid %s: use RFC 3339 or YYYY-MM-DD", param)
+			}
+			*target = &parsed
+		}
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return models.BoutListFilter{}, errors.New("order must be asc or desc")
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := decodeBoutCursor(cursor)
+		if err != nil {
+			return models.BoutListFilter{}, err
+		}
+		filter.After = after
+	}
+	return filter, nil
+}
+
+// parseBoutListDate accepts a full RFC 3339 timestamp or a plain date
+func parseBoutListDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
 }
 
 // GetBout handles GET requests to retrieve a specific bout
@@ -49,7 +129,7 @@ func (h *BoutHandler) GetBout(w http.ResponseWriter, r *http.Request) {
 	SendJSON(w, bout)
 }
 
-// CreateBout handles POST requests to create a new bout
+// CreateBout handles POST requests from the signed-in challenger to create a new bout
 func (h *BoutHandler) CreateBout(w http.ResponseWriter, r *http.Request) {
 	var bout models.Bout
 	if err := json.NewDecoder(r.Body).Decode(&bout); err != nil {
@@ -57,15 +137,15 @@ func (h *BoutHandler) CreateBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdBout, err := h.service.Create(bout)
+	createdBout, err := h.service.Create(bout, authenticatedAthleteId(r))
 	if err != nil {
-		SendError(w, "Failed to create bout", http.StatusInternalServerError)
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, createdBout)
 }
 
-// UpdateBout handles PUT requests to update an existing bout
+// UpdateBout handles PUT requests from the challenger to update a proposed bout
 func (h *BoutHandler) UpdateBout(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["bout_id"]
@@ -80,14 +160,14 @@ func (h *BoutHandler) UpdateBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Update(id, bout); err != nil {
-		SendError(w, "Failed to update bout", http.StatusInternalServerError)
+	if err := h.service.Update(id, bout, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, bout)
 }
 
-// DeleteBout handles DELETE requests to remove a bout
+// DeleteBout handles DELETE requests from an admin to remove a bout
 func (h *BoutHandler) DeleteBout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["bout_id"]
@@ -96,14 +176,14 @@ func (h *BoutHandler) DeleteBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		SendError(w, err.Error(), http.StatusBadRequest)
+	if err := h.service.Delete(id, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, id)
 }
 
-// AcceptBout handles PUT requests to accept a bout
+// AcceptBout handles PUT requests from the athlete whose turn it is to accept a bout
 func (h *BoutHandler) AcceptBout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["bout_id"]
@@ -112,14 +192,84 @@ func (h *BoutHandler) AcceptBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Accept(id); err != nil {
-		SendError(w, err.Error(), http.StatusBadRequest)
+	if err := h.service.Accept(id, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, id)
 }
 
-// DeclineBout handles PUT requests to decline a bout
+// ScheduleBout handles PUT requests from either competitor to set a bout's time, duration and venue gym
+func (h *BoutHandler) ScheduleBout(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["bout_id"]
+	if id == "" {
+		SendError(w, "Invalid bout ID", http.StatusBadRequest)
+		return
+	}
+
+	var schedule models.BoutSchedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Schedule(id, schedule, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
+		return
+	}
+
+	bout, err := h.service.GetByID(id)
+	if err != nil {
+		SendError(w, "Failed to get bout", http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, bout)
+}
+
+// CounterBout handles PUT requests from the athlete whose turn it is to answer a bout with different terms
+func (h *BoutHandler) CounterBout(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["bout_id"]
+	if id == "" {
+		SendError(w, "Invalid bout ID", http.StatusBadRequest)
+		return
+	}
+
+	var counter models.BoutCounterProposal
+	if err := json.NewDecoder(r.Body).Decode(&counter); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Counter(id, counter, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
+		return
+	}
+
+	bout, err := h.service.GetByID(id)
+	if err != nil {
+		SendError(w, "Failed to get bout", http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, bout)
+}
+
+// GetBoutProposals handles GET requests for every round of terms offered for a bout
+func (h *BoutHandler) GetBoutProposals(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["bout_id"]
+	if id == "" {
+		SendError(w, "Invalid bout ID", http.StatusBadRequest)
+		return
+	}
+
+	proposals, err := h.service.GetProposals(id)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	SendJSON(w, proposals)
+}
+
+// DeclineBout handles PUT requests from the athlete whose turn it is to decline a bout
 func (h *BoutHandler) DeclineBout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["bout_id"]
@@ -128,14 +278,15 @@ func (h *BoutHandler) DeclineBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Decline(id); err != nil {
-		SendError(w, err.Error(), http.StatusBadRequest)
+	if err := h.service.Decline(id, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, id)
 }
 
-// CompleteBout handles PUT requests to complete a bout
+// CompleteBout handles PUT requests from the bout's referee to complete a bout.
+// The referee_id path segment is kept for compatibility; the authenticated athlete is checked instead.
 func (h *BoutHandler) CompleteBout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	boutId := vars["bout_id"]
@@ -145,13 +296,48 @@ func (h *BoutHandler) CompleteBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Complete(boutId, refereeId); err != nil {
-		SendError(w, err.Error(), http.StatusBadRequest)
+	if err := h.service.Complete(boutId, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
+		return
+	}
+	SendJSON(w, boutId)
+}
+
+// StartBout handles PUT requests from the bout's referee to start a bout.
+// The referee_id path segment is kept for compatibility; the authenticated athlete is checked instead.
+func (h *BoutHandler) StartBout(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	boutId := vars["bout_id"]
+	refereeId := vars["referee_id"]
+	if boutId == "" || refereeId == "" {
+		SendError(w, "Invalid bout or referee ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Start(boutId, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, boutId)
 }
 
+// GetBoutStatusHistory handles GET requests for the status changes of a bout
+func (h *BoutHandler) GetBoutStatusHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["bout_id"]
+	if id == "" {
+		SendError(w, "Invalid bout ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(id)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	SendJSON(w, history)
+}
+
 // GetPendingBouts handles GET requests to retrieve pending bouts for an athlete
 func (h *BoutHandler) GetPendingBouts(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -200,7 +386,45 @@ func (h *BoutHandler) GetIncompleteBouts(w http.ResponseWriter, r *http.Request)
 	SendJSON(w, bouts)
 }
 
-// CancelBout handles PUT requests to cancel a bout
+// GetUpcomingBouts handles GET requests for an athlete's upcoming schedule, soonest first
+func (h *BoutHandler) GetUpcomingBouts(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["athlete_id"]
+	if id == "" {
+		SendError(w, "Athlete ID is required", http.StatusBadRequest)
+		return
+	}
+
+	bouts, err := h.service.GetUpcomingBouts(id)
+	if err != nil {
+		log.Printf("Error getting upcoming bouts for athlete %s: %v", id, err)
+		SendError(w, "Failed to get upcoming bouts", http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, bouts)
+}
+
+// GetBoutCalendar handles GET requests for an athlete's bouts as an iCalendar (.ics) feed
+func (h *BoutHandler) GetBoutCalendar(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["athlete_id"]
+	if id == "" {
+		SendError(w, "Athlete ID is required", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.service.GetCalendar(id)
+	if err != nil {
+		log.Printf("Error building calendar for athlete %s: %v", id, err)
+		SendError(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="bouts.ics"`)
+	w.Write([]byte(calendar))
+}
+
+// CancelBout handles PUT requests from the bout's challenger to cancel a bout.
+// The challenger_id path segment is kept for compatibility; the authenticated athlete is checked instead.
 func (h *BoutHandler) CancelBout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	boutId := vars["bout_id"]
@@ -210,9 +434,26 @@ func (h *BoutHandler) CancelBout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Cancel(boutId, challengerId); err != nil {
-		SendError(w, err.Error(), http.StatusBadRequest)
+	if err := h.service.Cancel(boutId, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 	SendJSON(w, boutId)
 }
+
+// boutErrorStatus maps a bout lifecycle error to an HTTP status code
+func boutErrorStatus(err error) int {
+	var transitionErr *BoutTransitionError
+	var authorizationErr *AuthorizationError
+	var conflictErr *ScheduleConflictError
+	var rematchErr *RematchLimitError
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.As(err, &authorizationErr):
+		return http.StatusForbidden
+	case errors.As(err, &transitionErr), errors.As(err, &conflictErr), errors.As(err, &rematchErr), errors.Is(err, repositories.ErrBoutStatusChanged):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
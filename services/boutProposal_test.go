@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"ronin/models"
+)
+
+func TestCounterTerms(t *testing.T) {
+	gymId := 4
+	bout := models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 3, StyleId: 5, DurationMinutes: 60}
+
+	tests := []struct {
+		name    string
+		counter models.BoutCounterProposal
+		want    models.Bout
+		wantErr bool
+	}{
+		{
+			name:    "new referee",
+			counter: models.BoutCounterProposal{RefereeId: 6},
+			want:    models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 6, StyleId: 5, DurationMinutes: 60},
+		},
+		{
+			name:    "longer bout at a gym",
+			counter: models.BoutCounterProposal{DurationMinutes: 90, GymId: &gymId},
+			want:    models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 3, StyleId: 5, DurationMinutes: 90, GymId: &gymId},
+		},
+		{name: "challenger as referee", counter: models.BoutCounterProposal{RefereeId: 1}, wantErr: true},
+		{name: "acceptor as referee", counter: models.BoutCounterProposal{RefereeId: 2}, wantErr: true},
+		{name: "same terms", counter: models.BoutCounterProposal{StyleId: 5, RefereeId: 3, DurationMinutes: 60}, wantErr: true},
+		{name: "nothing offered", counter: models.BoutCounterProposal{}, wantErr: true},
+		{name: "negative duration", counter: models.BoutCounterProposal{DurationMinutes: -30}, wantErr: true},
+	}
+
+	// None of these change the style or time, so no repository is needed
+	service := &boutService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms, err := service.counterTerms(bout, tt.counter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("counterTerms() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if terms.RefereeId != tt.want.RefereeId || terms.StyleId != tt.want.StyleId ||
+				terms.DurationMinutes != tt.want.DurationMinutes || terms.GymId != tt.want.GymId {
+				t.Errorf("counterTerms() = referee %d style %d %d minutes gym %v, want referee %d style %d %d minutes gym %v",
+					terms.RefereeId, terms.StyleId, terms.DurationMinutes, terms.GymId,
+					tt.want.RefereeId, tt.want.StyleId, tt.want.DurationMinutes, tt.want.GymId)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// ScheduleConflictError is returned when someone in a bout already has another open bout in an overlapping window
+type ScheduleConflictError struct {
+	BoutId            int
+	ConflictingBoutId int
+	ScheduledAt       string
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("bout %d at %s overlaps bout %d for one of its athletes", e.BoutId, e.ScheduledAt, e.ConflictingBoutId)
+}
+
+// boutScheduleWindow parses a bout's start time and fills in the default duration
+func boutScheduleWindow(scheduledAt string, durationMinutes int) (time.Time, int, error) {
+	start, err := time.Parse(time.RFC3339, scheduledAt)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("scheduled time must be an RFC 3339 timestamp: %w", err)
+	}
+	if durationMinutes < 0 {
+		return time.Time{}, 0, errors.New("duration minutes cannot be negative")
+	}
+	if durationMinutes == 0 {
+		durationMinutes = models.DefaultBoutDurationMinutes
+	}
+	return start, durationMinutes, nil
+}
+
+// checkScheduleConflict rejects a window that overlaps another open bout of the challenger,
+// acceptor or referee. The bout itself is left out of the search so it can be rescheduled.
+func checkScheduleConflict(repo *repositories.BoutRepository, bout models.Bout, start time.Time, durationMinutes int) error {
+	athleteIds := []int{bout.ChallengerId, bout.AcceptorId, bout.RefereeId}
+	conflict, err := repo.GetScheduleConflict(bout.BoutId, athleteIds, start, durationMinutes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check schedule conflicts: %w", err)
+	}
+	return &ScheduleConflictError{BoutId: bout.BoutId, ConflictingBoutId: conflict.BoutId, ScheduledAt: start.Format(time.RFC3339)}
+}
+
+// prepareBoutSchedule normalizes a bout's schedule fields and checks its window for conflicts.
+// A bout without a start time only gets the default duration.
+func prepareBoutSchedule(repo *repositories.BoutRepository, bout *models.Bout, now time.Time) error {
+	if bout.ScheduledAt != nil && *bout.ScheduledAt == "" {
+		bout.ScheduledAt = nil
+	}
+	if bout.ScheduledAt == nil {
+		if bout.DurationMinutes < 0 {
+			return errors.New("duration minutes cannot be negative")
+		}
+		if bout.DurationMinutes == 0 {
+			bout.DurationMinutes = models.DefaultBoutDurationMinutes
+		}
+		return nil
+	}
+
+	start, durationMinutes, err := boutScheduleWindow(*bout.ScheduledAt, bout.DurationMinutes)
+	if err != nil {
+		return err
+	}
+	if !start.After(now) {
+		return errors.New("scheduled time must be in the future")
+	}
+	bout.DurationMinutes = durationMinutes
+
+	return checkScheduleConflict(repo, *bout, start, durationMinutes)
+}
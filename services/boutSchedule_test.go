@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ronin/models"
+)
+
+func TestBoutScheduleWindow(t *testing.T) {
+	tests := []struct {
+		name         string
+		scheduledAt  string
+		duration     int
+		wantStart    time.Time
+		wantDuration int
+		wantErr      bool
+	}{
+		{name: "explicit duration", scheduledAt: "2026-03-01T18:00:00Z", duration: 45,
+			wantStart: time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), wantDuration: 45},
+		{name: "default duration", scheduledAt: "2026-03-01T18:00:00+02:00",
+			wantStart: time.Date(2026, 3, 1, 16, 0, 0, 0, time.UTC), wantDuration: models.DefaultBoutDurationMinutes},
+		{name: "not a timestamp", scheduledAt: "tomorrow evening", wantErr: true},
+		{name: "date without a time", scheduledAt: "2026-03-01", wantErr: true},
+		{name: "negative duration", scheduledAt: "2026-03-01T18:00:00Z", duration: -5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, duration, err := boutScheduleWindow(tt.scheduledAt, tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("boutScheduleWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !start.Equal(tt.wantStart) || duration != tt.wantDuration {
+				t.Errorf("boutScheduleWindow() = %v for %d minutes, want %v for %d minutes", start, duration, tt.wantStart, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestPrepareBoutScheduleWithoutConflictCheck(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	empty := ""
+	past := "2026-03-01T11:00:00Z"
+
+	tests := []struct {
+		name         string
+		bout         models.Bout
+		wantDuration int
+		wantErr      bool
+	}{
+		{name: "unscheduled gets the default duration", bout: models.Bout{}, wantDuration: models.DefaultBoutDurationMinutes},
+		{name: "unscheduled keeps its duration", bout: models.Bout{DurationMinutes: 90}, wantDuration: 90},
+		{name: "empty start time is unscheduled", bout: models.Bout{ScheduledAt: &empty}, wantDuration: models.DefaultBoutDurationMinutes},
+		{name: "unscheduled with a negative duration", bout: models.Bout{DurationMinutes: -1}, wantErr: true},
+		{name: "start time in the past", bout: models.Bout{ScheduledAt: &past}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bout := tt.bout
+			// None of these reach the conflict check, so no repository is needed
+			err := prepareBoutSchedule(nil, &bout, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("prepareBoutSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if bout.ScheduledAt != nil {
+				t.Errorf("prepareBoutSchedule() kept start time %q, want none", *bout.ScheduledAt)
+			}
+			if bout.DurationMinutes != tt.wantDuration {
+				t.Errorf("prepareBoutSchedule() duration = %d, want %d", bout.DurationMinutes, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestRequireBoutRole(t *testing.T) {
+	bout := models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 3, ResponderId: 1}
+
+	tests := []struct {
+		name    string
+		actorId int
+		role    string
+		wantErr bool
+	}{
+		{name: "challenger", actorId: 1, role: BoutRoleChallenger},
+		{name: "acceptor", actorId: 2, role: BoutRoleAcceptor},
+		{name: "referee", actorId: 3, role: BoutRoleReferee},
+		{name: "challenger is a competitor", actorId: 1, role: BoutRoleCompetitor},
+		{name: "acceptor is a competitor", actorId: 2, role: BoutRoleCompetitor},
+		{name: "referee is not a competitor", actorId: 3, role: BoutRoleCompetitor, wantErr: true},
+		{name: "responder after a counter", actorId: 1, role: BoutRoleResponder},
+		{name: "acceptor waiting on a counter", actorId: 2, role: BoutRoleResponder, wantErr: true},
+		{name: "acceptor is not the referee", actorId: 2, role: BoutRoleReferee, wantErr: true},
+		{name: "anonymous", actorId: 0, role: BoutRoleChallenger, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireBoutRole(bout, tt.actorId, tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("requireBoutRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
+
 	"ronin/interfaces"
 	"ronin/models"
 	"ronin/repositories"
@@ -11,41 +14,87 @@ import (
 
 // boutService implements the interfaces.BoutService interface
 type boutService struct {
-	repo *repositories.BoutRepository
+	repo                *repositories.BoutRepository
+	styleRepo           *repositories.StyleRepository
+	athleteScoreService *AthleteScoreService
 }
 
 // NewBoutService creates a new instance of BoutService
-func NewBoutService(repo *repositories.BoutRepository) interfaces.BoutService {
+func NewBoutService(
+	repo *repositories.BoutRepository,
+	styleRepo *repositories.StyleRepository,
+	athleteScoreService *AthleteScoreService,
+) interfaces.BoutService {
 	return &boutService{
-		repo: repo,
+		repo:                repo,
+		styleRepo:           styleRepo,
+		athleteScoreService: athleteScoreService,
 	}
 }
 
-// GetAll retrieves all bouts
-func (s *boutService) GetAll() ([]models.OutboundBout, error) {
-	log.Println("Getting all bouts")
-	// First get all bout IDs
-	bouts, err := s.repo.GetAllBouts()
+const (
+	defaultBoutPageLimit = 25
+	maxBoutPageLimit     = 100
+)
+
+// List retrieves one page of bouts matching a filter. The filter's cursor must come from a previous
+// page listed with the same sort and order.
+func (s *boutService) List(filter models.BoutListFilter) (models.BoutPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.BoutSortCreated
+	}
+	if filter.Sort != models.BoutSortCreated && filter.Sort != models.BoutSortUpdated && filter.Sort != models.BoutSortId {
+		return models.BoutPage{}, fmt.Errorf("sort must be %s, %s or %s", models.BoutSortCreated, models.BoutSortUpdated, models.BoutSortId)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultBoutPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxBoutPageLimit {
+		return models.BoutPage{}, fmt.Errorf("limit must be between 1 and %d", maxBoutPageLimit)
+	}
+	for _, status := range filter.Statuses {
+		if !isBoutStatus(status) {
+			return models.BoutPage{}, fmt.Errorf("unknown bout status %q", status)
+		}
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return models.BoutPage{}, errors.New("from must be before to")
+	}
+
+	order := boutSortOrder(filter.Descending)
+	if filter.After != nil && (filter.After.Sort != filter.Sort || filter.After.Order != order) {
+		return models.BoutPage{}, errors.New("cursor was issued for a different sort or order")
+	}
+
+	// Fetch one extra bout to learn whether there is another page
+	bouts, err := s.repo.ListBouts(filter, filter.Limit+1)
 	if err != nil {
-		log.Printf("Error getting all bouts: %v", err)
-		return nil, fmt.Errorf("failed to get all bouts: %w", err)
+		return models.BoutPage{}, fmt.Errorf("failed to list bouts: %w", err)
+	}
+
+	page := models.BoutPage{
+		Bouts: bouts,
+		Paging: models.BoutPaging{
+			Limit: filter.Limit,
+			Sort:  filter.Sort,
+			Order: order,
+		},
 	}
-	log.Printf("Found %d bouts", len(bouts))
+	if page.Bouts == nil {
+		page.Bouts = []models.OutboundBout{}
+	}
+	if len(page.Bouts) > filter.Limit {
+		page.Bouts = page.Bouts[:filter.Limit]
+		page.Paging.HasMore = true
 
-	// Convert each bout to outbound bout
-	outboundBouts := make([]models.OutboundBout, 0, len(bouts))
-	for _, bout := range bouts {
-		log.Printf("Converting bout ID %d to outbound bout", bout.BoutId)
-		outboundBout, err := s.repo.GetOutboundBoutByBoutId(bout.BoutId)
+		cursor, err := encodeBoutCursor(filter.Sort, order, page.Bouts[len(page.Bouts)-1])
 		if err != nil {
-			log.Printf("Error getting outbound bout for bout ID %d: %v", bout.BoutId, err)
-			return nil, fmt.Errorf("failed to get outbound bout for bout ID %d: %w", bout.BoutId, err)
+			return models.BoutPage{}, err
 		}
-		outboundBouts = append(outboundBouts, outboundBout)
+		page.Paging.NextCursor = &cursor
 	}
-
-	log.Printf("Successfully retrieved %d outbound bouts", len(outboundBouts))
-	return outboundBouts, nil
+	page.Paging.Count = len(page.Bouts)
+	return page, nil
 }
 
 // GetByID retrieves a bout by its ID
@@ -75,12 +124,26 @@ func (s *boutService) GetByID(id string) (models.OutboundBout, error) {
 	return outboundBout, nil
 }
 
-// Create creates a new bout
-func (s *boutService) Create(bout models.Bout) (models.OutboundBout, error) {
+// Create creates a new bout with the signed-in athlete as challenger. A bout that breaks the
+// style's rematch limits is rejected or created unrated, depending on the style's rating config.
+func (s *boutService) Create(bout models.Bout, actorID int) (models.OutboundBout, error) {
+	if actorID == 0 {
+		return models.OutboundBout{}, ErrUnauthenticated
+	}
+	bout.ChallengerId = actorID
+
 	if err := s.validateBout(bout); err != nil {
 		return models.OutboundBout{}, fmt.Errorf("invalid bout: %w", err)
 	}
 
+	if err := checkRematchLimits(s.athleteScoreService, &bout); err != nil {
+		return models.OutboundBout{}, err
+	}
+
+	if err := prepareBoutSchedule(s.repo, &bout, time.Now()); err != nil {
+		return models.OutboundBout{}, fmt.Errorf("failed to schedule bout: %w", err)
+	}
+
 	boutID, err := s.repo.CreateBout(bout)
 	if err != nil {
 		return models.OutboundBout{}, fmt.Errorf("failed to create bout: %w", err)
@@ -94,8 +157,10 @@ func (s *boutService) Create(bout models.Bout) (models.OutboundBout, error) {
 	return createdBout, nil
 }
 
-// Update updates an existing bout
-func (s *boutService) Update(id string, bout models.Bout) error {
+// Update rewrites the terms of a proposed bout. Only the bout's challenger can edit it, and the
+// challenger cannot hand the bout over to another athlete. A new style or acceptor is checked
+// against the style's rematch limits again.
+func (s *boutService) Update(id string, bout models.Bout, actorID int) error {
 	if id == "" {
 		return errors.New("bout ID cannot be empty")
 	}
@@ -104,6 +169,32 @@ func (s *boutService) Update(id string, bout models.Bout) error {
 		return fmt.Errorf("invalid bout: %w", err)
 	}
 
+	current, err := s.repo.GetBoutById(id)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
+	}
+	if err := requireBoutRole(current, actorID, BoutRoleChallenger); err != nil {
+		return err
+	}
+	if current.Status != models.BoutStatusProposed {
+		return fmt.Errorf("bout %s can only be edited while proposed, it is %s", id, current.Status)
+	}
+	if bout.ChallengerId != current.ChallengerId {
+		return errors.New("the challenger of a bout cannot be changed")
+	}
+
+	bout.BoutId = current.BoutId
+	bout.Rated = current.Rated
+	if bout.StyleId != current.StyleId || bout.AcceptorId != current.AcceptorId {
+		if err := checkRematchLimits(s.athleteScoreService, &bout); err != nil {
+			return err
+		}
+	}
+
+	if err := prepareBoutSchedule(s.repo, &bout, time.Now()); err != nil {
+		return fmt.Errorf("failed to schedule bout: %w", err)
+	}
+
 	if err := s.repo.UpdateBout(id, bout); err != nil {
 		return fmt.Errorf("failed to update bout: %w", err)
 	}
@@ -111,12 +202,26 @@ func (s *boutService) Update(id string, bout models.Bout) error {
 	return nil
 }
 
-// Delete deletes a bout
-func (s *boutService) Delete(id string) error {
+// Delete deletes a bout. Only an admin can delete one, and never once it has an outcome.
+func (s *boutService) Delete(id string, actorID int) error {
 	if id == "" {
 		return errors.New("bout ID cannot be empty")
 	}
 
+	if err := requireAdmin(actorID); err != nil {
+		return err
+	}
+
+	bout, err := s.repo.GetBoutById(id)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
+	}
+	switch bout.Status {
+	case models.BoutStatusCompleted, models.BoutStatusDisputed, models.BoutStatusVoided:
+		// Its outcome still references it; void the outcome instead
+		return &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: "deleted"}
+	}
+
 	if err := s.repo.DeleteBout(id); err != nil {
 		return fmt.Errorf("failed to delete bout: %w", err)
 	}
@@ -124,64 +229,274 @@ func (s *boutService) Delete(id string) error {
 	return nil
 }
 
-// Accept accepts a bout
-func (s *boutService) Accept(id string) error {
-	if id == "" {
-		return errors.New("bout ID cannot be empty")
+// Accept agrees to the current terms of a proposed bout and moves it to accepted, or on to scheduled
+// when it already has a start time. Only the athlete whose turn it is can accept.
+func (s *boutService) Accept(id string, actorID int) error {
+	if err := s.transition(id, actorID, BoutRoleResponder, models.BoutStatusAccepted, "accept"); err != nil {
+		return err
 	}
 
-	if err := s.repo.AcceptBout(id); err != nil {
-		return fmt.Errorf("failed to accept bout: %w", err)
+	bout, err := s.repo.GetBoutById(id)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
+	}
+	if bout.ScheduledAt == nil || bout.Status != models.BoutStatusAccepted {
+		return nil
 	}
 
+	if err := transitionBout(s.repo, bout, models.BoutStatusScheduled, actorID); err != nil {
+		return fmt.Errorf("failed to schedule bout: %w", err)
+	}
 	return nil
 }
 
-// Decline declines a bout
-func (s *boutService) Decline(id string) error {
-	if id == "" {
+// Schedule sets when and where an agreed bout takes place. Either competitor can schedule an
+// accepted or scheduled bout; an accepted bout moves to scheduled. Proposed bouts change time
+// through Counter instead.
+func (s *boutService) Schedule(boutID string, schedule models.BoutSchedule, actorID int) error {
+	if boutID == "" {
 		return errors.New("bout ID cannot be empty")
 	}
 
-	if err := s.repo.DeclineBout(id); err != nil {
-		return fmt.Errorf("failed to decline bout: %w", err)
+	bout, err := s.repo.GetBoutById(boutID)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
+	}
+
+	if err := requireBoutRole(bout, actorID, BoutRoleCompetitor); err != nil {
+		return err
+	}
+
+	toStatus := bout.Status
+	switch bout.Status {
+	case models.BoutStatusScheduled:
+	case models.BoutStatusAccepted:
+		toStatus = models.BoutStatusScheduled
+	default:
+		return &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: models.BoutStatusScheduled}
+	}
+
+	start, durationMinutes, err := boutScheduleWindow(schedule.ScheduledAt, schedule.DurationMinutes)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	if !start.After(time.Now()) {
+		return errors.New("invalid schedule: scheduled time must be in the future")
+	}
+	schedule.DurationMinutes = durationMinutes
+
+	if err := checkScheduleConflict(s.repo, bout, start, durationMinutes); err != nil {
+		return err
 	}
 
+	err = s.repo.ScheduleBout(bout.BoutId, bout.Status, toStatus, schedule, actorID)
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return staleBoutError(s.repo, bout, toStatus)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to schedule bout %s: %w", boutID, err)
+	}
 	return nil
 }
 
-// Complete completes a bout
-func (s *boutService) Complete(boutID string, refereeID string) error {
+// Decline moves a proposed bout to declined. Only the athlete whose turn it is can decline.
+func (s *boutService) Decline(id string, actorID int) error {
+	return s.transition(id, actorID, BoutRoleResponder, models.BoutStatusDeclined, "decline")
+}
+
+// Counter answers a proposed bout with different terms: another common style, another referee or
+// another time. It becomes the other athlete's turn to accept, decline or counter again. A new style
+// is checked against its rematch limits again.
+func (s *boutService) Counter(boutID string, counter models.BoutCounterProposal, actorID int) error {
 	if boutID == "" {
 		return errors.New("bout ID cannot be empty")
 	}
-	if refereeID == "" {
-		return errors.New("referee ID cannot be empty")
+
+	bout, err := s.repo.GetBoutById(boutID)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
 	}
 
-	if err := s.repo.CompleteBout(boutID, refereeID); err != nil {
-		return fmt.Errorf("failed to complete bout: %w", err)
+	if err := requireBoutRole(bout, actorID, BoutRoleResponder); err != nil {
+		return err
+	}
+	if bout.Status != models.BoutStatusProposed {
+		return fmt.Errorf("bout %s can only be countered while proposed, it is %s", boutID, bout.Status)
 	}
 
+	terms, err := s.counterTerms(bout, counter)
+	if err != nil {
+		return fmt.Errorf("invalid counter-proposal: %w", err)
+	}
+	if terms.StyleId != bout.StyleId {
+		if err := checkRematchLimits(s.athleteScoreService, &terms); err != nil {
+			return err
+		}
+	}
+
+	nextResponder := bout.ChallengerId
+	if actorID == bout.ChallengerId {
+		nextResponder = bout.AcceptorId
+	}
+	message := fmt.Sprintf("Athlete %d countered bout %d with new terms", actorID, bout.BoutId)
+
+	if err := s.repo.CounterBout(terms, actorID, nextResponder, message); err != nil {
+		return fmt.Errorf("failed to counter bout %s: %w", boutID, err)
+	}
 	return nil
 }
 
-// Cancel cancels a bout
-func (s *boutService) Cancel(boutID string, challengerID string) error {
+// checkRematchLimits decides whether a bout is rated under its style's rematch limits. A bout that
+// breaks them is rejected or left unrated, depending on the style's rating config.
+func checkRematchLimits(athleteScoreService *AthleteScoreService, bout *models.Bout) error {
+	reason, rule, err := athleteScoreService.RematchViolation(bout.ChallengerId, bout.AcceptorId, bout.StyleId)
+	if err != nil {
+		return fmt.Errorf("failed to check rematch limits: %w", err)
+	}
+	bout.Rated = reason == ""
+	if !bout.Rated {
+		if rule == RematchRuleReject {
+			return &RematchLimitError{StyleId: bout.StyleId, Reason: reason}
+		}
+		log.Printf("Leaving bout between athletes %d and %d in style %d unrated: %s", bout.ChallengerId, bout.AcceptorId, bout.StyleId, reason)
+	}
+	return nil
+}
+
+// counterTerms applies a counter-proposal to a bout's current terms and validates the result
+func (s *boutService) counterTerms(bout models.Bout, counter models.BoutCounterProposal) (models.Bout, error) {
+	terms := bout
+	changed := false
+
+	if counter.StyleId != 0 && counter.StyleId != bout.StyleId {
+		styles, err := s.styleRepo.GetCommonStyles(strconv.Itoa(bout.AcceptorId), strconv.Itoa(bout.ChallengerId))
+		if err != nil {
+			return models.Bout{}, fmt.Errorf("failed to get common styles: %w", err)
+		}
+		common := false
+		for _, style := range styles {
+			common = common || style.StyleId == counter.StyleId
+		}
+		if !common {
+			return models.Bout{}, fmt.Errorf("style %d is not shared by both athletes", counter.StyleId)
+		}
+		terms.StyleId = counter.StyleId
+		changed = true
+	}
+
+	if counter.RefereeId != 0 && counter.RefereeId != bout.RefereeId {
+		if counter.RefereeId == bout.ChallengerId || counter.RefereeId == bout.AcceptorId {
+			return models.Bout{}, errors.New("referee cannot be one of the competitors")
+		}
+		terms.RefereeId = counter.RefereeId
+		changed = true
+	}
+
+	if counter.ScheduledAt != nil {
+		terms.ScheduledAt = counter.ScheduledAt
+		changed = true
+	}
+	if counter.DurationMinutes != 0 && counter.DurationMinutes != bout.DurationMinutes {
+		terms.DurationMinutes = counter.DurationMinutes
+		changed = true
+	}
+	if counter.GymId != nil {
+		terms.GymId = counter.GymId
+		changed = true
+	}
+
+	if !changed {
+		return models.Bout{}, errors.New("a counter-proposal must change the style, referee, time or venue")
+	}
+
+	// A new referee or time has to fit everyone's schedule
+	if err := prepareBoutSchedule(s.repo, &terms, time.Now()); err != nil {
+		return models.Bout{}, err
+	}
+	return terms, nil
+}
+
+// GetProposals retrieves every round of terms offered for a bout, first round first
+func (s *boutService) GetProposals(boutID string) ([]models.BoutProposal, error) {
+	if boutID == "" {
+		return nil, errors.New("bout ID cannot be empty")
+	}
+
+	bout, err := s.repo.GetBoutById(boutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bout: %w", err)
+	}
+
+	proposals, err := s.repo.GetBoutProposals(bout.BoutId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposals for bout %s: %w", boutID, err)
+	}
+	if proposals == nil {
+		proposals = []models.BoutProposal{}
+	}
+	return proposals, nil
+}
+
+// Start moves an accepted or scheduled bout to in progress. Only the bout's referee can start it.
+func (s *boutService) Start(boutID string, actorID int) error {
+	return s.transition(boutID, actorID, BoutRoleReferee, models.BoutStatusInProgress, "start")
+}
+
+// Complete moves a bout to completed. Only the bout's referee can complete it.
+func (s *boutService) Complete(boutID string, actorID int) error {
+	return s.transition(boutID, actorID, BoutRoleReferee, models.BoutStatusCompleted, "complete")
+}
+
+// Cancel moves a bout to cancelled. Only the bout's challenger can cancel it.
+func (s *boutService) Cancel(boutID string, actorID int) error {
+	return s.transition(boutID, actorID, BoutRoleChallenger, models.BoutStatusCancelled, "cancel")
+}
+
+// transition checks that the acting athlete holds the required role in a bout and moves it to a new status
+func (s *boutService) transition(boutID string, actorID int, role string, toStatus string, action string) error {
 	if boutID == "" {
 		return errors.New("bout ID cannot be empty")
 	}
-	if challengerID == "" {
-		return errors.New("challenger ID cannot be empty")
+
+	bout, err := s.repo.GetBoutById(boutID)
+	if err != nil {
+		return fmt.Errorf("failed to get bout: %w", err)
+	}
+
+	if err := requireBoutRole(bout, actorID, role); err != nil {
+		return err
+	}
+
+	// A disputed bout is settled by resolving its outcome
+	if bout.Status == models.BoutStatusDisputed {
+		return &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: toStatus}
 	}
 
-	if err := s.repo.CancelBout(boutID, challengerID); err != nil {
-		return fmt.Errorf("failed to cancel bout: %w", err)
+	if err := transitionBout(s.repo, bout, toStatus, actorID); err != nil {
+		return fmt.Errorf("failed to %s bout: %w", action, err)
 	}
 
 	return nil
 }
 
+// GetStatusHistory retrieves every status change of a bout, oldest first
+func (s *boutService) GetStatusHistory(boutID string) ([]models.BoutStatusHistory, error) {
+	if boutID == "" {
+		return nil, errors.New("bout ID cannot be empty")
+	}
+
+	bout, err := s.repo.GetBoutById(boutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bout: %w", err)
+	}
+
+	history, err := s.repo.GetBoutStatusHistory(bout.BoutId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history for bout %s: %w", boutID, err)
+	}
+	return history, nil
+}
+
 // GetPendingBouts retrieves all pending bouts for an athlete
 func (s *boutService) GetPendingBouts(athleteID string) ([]models.OutboundBout, error) {
 	log.Printf("Getting pending bouts for athlete ID: %s", athleteID)
@@ -234,6 +549,37 @@ func (s *boutService) GetIncompleteBouts(athleteID string) ([]models.OutboundBou
 	return bouts, nil
 }
 
+// GetUpcomingBouts retrieves the scheduled bouts an athlete competes in or referees that have not finished yet
+func (s *boutService) GetUpcomingBouts(athleteID string) ([]models.OutboundBout, error) {
+	if athleteID == "" {
+		return nil, errors.New("athlete ID cannot be empty")
+	}
+
+	bouts, err := s.repo.GetUpcomingBoutsByAthleteId(athleteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming bouts: %w", err)
+	}
+	if bouts == nil {
+		bouts = []models.OutboundBout{}
+	}
+	return bouts, nil
+}
+
+// GetCalendar renders an athlete's incomplete bouts that have a start time as an iCalendar feed
+func (s *boutService) GetCalendar(athleteID string) (string, error) {
+	athleteId, err := strconv.Atoi(athleteID)
+	if err != nil {
+		return "", fmt.Errorf("invalid athlete ID %q", athleteID)
+	}
+
+	bouts, err := s.repo.GetIncompleteBoutsByAthleteId(athleteID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get incomplete bouts: %w", err)
+	}
+
+	return buildBoutCalendar(athleteId, bouts, time.Now())
+}
+
 // validateBout validates the bout data
 func (s *boutService) validateBout(bout models.Bout) error {
 	if bout.ChallengerId == 0 {
@@ -245,5 +591,14 @@ func (s *boutService) validateBout(bout models.Bout) error {
 	if bout.ChallengerId == bout.AcceptorId {
 		return errors.New("challenger and acceptor cannot be the same athlete")
 	}
+	if bout.RefereeId == 0 {
+		return errors.New("referee ID is required")
+	}
+	if bout.RefereeId == bout.ChallengerId || bout.RefereeId == bout.AcceptorId {
+		return errors.New("referee cannot be one of the competitors")
+	}
+	if bout.ExpiresInHours < 0 {
+		return errors.New("expires in hours cannot be negative")
+	}
 	return nil
 }
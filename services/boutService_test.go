@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestValidateBout(t *testing.T) {
+	tests := []struct {
+		name    string
+		bout    models.Bout
+		wantErr bool
+	}{
+		{name: "valid", bout: models.Bout{ChallengerId: 1, AcceptorId: 2, RefereeId: 3}},
+		{name: "no challenger", bout: models.Bout{AcceptorId: 2, RefereeId: 3}, wantErr: true},
+		{name: "no acceptor", bout: models.Bout{ChallengerId: 1, RefereeId: 3}, wantErr: true},
+		{name: "challenger is acceptor", bout: models.Bout{ChallengerId: 1, AcceptorId: 1, RefereeId: 3}, wantErr: true},
+		{name: "no referee", bout: models.Bout{ChallengerId: 1, AcceptorId: 2}, wantErr: true},
+		{name: "challenger as referee", bout: models.Bout{ChallengerId: 1, AcceptorId: 2, RefereeId: 1}, wantErr: true},
+		{name: "acceptor as referee", bout: models.Bout{ChallengerId: 1, AcceptorId: 2, RefereeId: 2}, wantErr: true},
+		{name: "negative expiry", bout: models.Bout{ChallengerId: 1, AcceptorId: 2, RefereeId: 3, ExpiresInHours: -1}, wantErr: true},
+	}
+
+	service := &boutService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateBout(tt.bout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateBoutRequiresSignedInChallenger(t *testing.T) {
+	service := &boutService{}
+	_, err := service.Create(models.Bout{ChallengerId: 1, AcceptorId: 2, RefereeId: 3}, 0)
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Create() without a signed-in athlete error = %v, want ErrUnauthenticated", err)
+	}
+}
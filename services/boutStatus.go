@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// boutTransitions lists the statuses each bout status may move to. Statuses without an
+// entry are final.
+var boutTransitions = map[string][]string{
+	models.BoutStatusProposed:   {models.BoutStatusAccepted, models.BoutStatusDeclined, models.BoutStatusCancelled, models.BoutStatusExpired},
+	models.BoutStatusAccepted:   {models.BoutStatusScheduled, models.BoutStatusInProgress, models.BoutStatusCompleted, models.BoutStatusCancelled},
+	models.BoutStatusScheduled:  {models.BoutStatusInProgress, models.BoutStatusCompleted, models.BoutStatusCancelled},
+	models.BoutStatusInProgress: {models.BoutStatusCompleted},
+	models.BoutStatusCompleted:  {models.BoutStatusDisputed, models.BoutStatusVoided},
+	models.BoutStatusDisputed:   {models.BoutStatusCompleted, models.BoutStatusVoided},
+}
+
+// boutStatuses lists every status a bout can be in
+var boutStatuses = []string{
+	models.BoutStatusProposed,
+	models.BoutStatusAccepted,
+	models.BoutStatusDeclined,
+	models.BoutStatusScheduled,
+	models.BoutStatusInProgress,
+	models.BoutStatusCompleted,
+	models.BoutStatusDisputed,
+	models.BoutStatusCancelled,
+	models.BoutStatusVoided,
+	models.BoutStatusExpired,
+}
+
+// isBoutStatus reports whether status is a known bout status
+func isBoutStatus(status string) bool {
+	for _, known := range boutStatuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
+
+// BoutTransitionError is returned when a bout cannot move from its current status to the requested one
+type BoutTransitionError struct {
+	BoutId int
+	From   string
+	To     string
+}
+
+func (e *BoutTransitionError) Error() string {
+	return fmt.Sprintf("bout %d cannot move from %s to %s", e.BoutId, e.From, e.To)
+}
+
+// canTransitionBout reports whether a bout in status from may move to status to
+func canTransitionBout(from, to string) bool {
+	for _, allowed := range boutTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionBout moves a bout to a new status and records who did it. It returns a
+// *BoutTransitionError if the move is not allowed from the bout's current status.
+func transitionBout(repo *repositories.BoutRepository, bout models.Bout, toStatus string, actorId int) error {
+	if !canTransitionBout(bout.Status, toStatus) {
+		return &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: toStatus}
+	}
+
+	err := repo.TransitionBoutStatus(bout.BoutId, bout.Status, toStatus, actorId)
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return staleBoutError(repo, bout, toStatus)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to move bout %d to %s: %w", bout.BoutId, toStatus, err)
+	}
+	return nil
+}
+
+// staleBoutError reports a move that lost a race: someone else changed the bout first, so the
+// *BoutTransitionError is built against the status the bout is in now
+func staleBoutError(repo *repositories.BoutRepository, bout models.Bout, toStatus string) error {
+	current, err := repo.GetBoutById(strconv.Itoa(bout.BoutId))
+	if err != nil {
+		return fmt.Errorf("failed to reload bout %d: %w", bout.BoutId, err)
+	}
+	return &BoutTransitionError{BoutId: bout.BoutId, From: current.Status, To: toStatus}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"ronin/models"
+)
+
+func TestCanTransitionBout(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: models.BoutStatusProposed, to: models.BoutStatusAccepted, want: true},
+		{from: models.BoutStatusProposed, to: models.BoutStatusDeclined, want: true},
+		{from: models.BoutStatusProposed, to: models.BoutStatusCancelled, want: true},
+		{from: models.BoutStatusProposed, to: models.BoutStatusExpired, want: true},
+		{from: models.BoutStatusProposed, to: models.BoutStatusScheduled, want: false},
+		{from: models.BoutStatusProposed, to: models.BoutStatusCompleted, want: false},
+		{from: models.BoutStatusAccepted, to: models.BoutStatusScheduled, want: true},
+		{from: models.BoutStatusAccepted, to: models.BoutStatusInProgress, want: true},
+		{from: models.BoutStatusAccepted, to: models.BoutStatusCompleted, want: true},
+		{from: models.BoutStatusAccepted, to: models.BoutStatusCancelled, want: true},
+		{from: models.BoutStatusAccepted, to: models.BoutStatusDeclined, want: false},
+		{from: models.BoutStatusScheduled, to: models.BoutStatusInProgress, want: true},
+		{from: models.BoutStatusScheduled, to: models.BoutStatusCompleted, want: true},
+		{from: models.BoutStatusScheduled, to: models.BoutStatusCancelled, want: true},
+		{from: models.BoutStatusScheduled, to: models.BoutStatusAccepted, want: false},
+		{from: models.BoutStatusInProgress, to: models.BoutStatusCompleted, want: true},
+		{from: models.BoutStatusInProgress, to: models.BoutStatusCancelled, want: false},
+		{from: models.BoutStatusCompleted, to: models.BoutStatusDisputed, want: true},
+		{from: models.BoutStatusCompleted, to: models.BoutStatusVoided, want: true},
+		{from: models.BoutStatusCompleted, to: models.BoutStatusCancelled, want: false},
+		{from: models.BoutStatusDisputed, to: models.BoutStatusCompleted, want: true},
+		{from: models.BoutStatusDisputed, to: models.BoutStatusVoided, want: true},
+		{from: models.BoutStatusDisputed, to: models.BoutStatusCancelled, want: false},
+		{from: "unknown", to: models.BoutStatusAccepted, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := canTransitionBout(tt.from, tt.to); got != tt.want {
+				t.Errorf("canTransitionBout(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalBoutStatuses(t *testing.T) {
+	final := []string{models.BoutStatusDeclined, models.BoutStatusCancelled, models.BoutStatusVoided, models.BoutStatusExpired}
+
+	for _, from := range final {
+		t.Run(from, func(t *testing.T) {
+			if targets, found := boutTransitions[from]; found {
+				t.Errorf("boutTransitions lets final status %q move to %v", from, targets)
+			}
+		})
+	}
+}
+
+func TestBoutTransitionsUseKnownStatuses(t *testing.T) {
+	for from, targets := range boutTransitions {
+		if !isBoutStatus(from) {
+			t.Errorf("boutTransitions has unknown status %q", from)
+		}
+		for _, to := range targets {
+			if !isBoutStatus(to) {
+				t.Errorf("boutTransitions moves %q to unknown status %q", from, to)
+			}
+		}
+	}
+}
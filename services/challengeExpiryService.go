@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// ChallengeExpiryService moves proposed bouts that were not answered in time, and open challenges
+// nobody claimed in time, to expired
+type ChallengeExpiryService struct {
+	repo              *repositories.BoutRepository
+	openChallengeRepo *repositories.OpenChallengeRepository
+}
+
+// NewChallengeExpiryService creates a new instance of ChallengeExpiryService
+func NewChallengeExpiryService(repo *repositories.BoutRepository, openChallengeRepo *repositories.OpenChallengeRepository) *ChallengeExpiryService {
+	return &ChallengeExpiryService{
+		repo:              repo,
+		openChallengeRepo: openChallengeRepo,
+	}
+}
+
+// Run expires every proposed bout past its expiry time and notifies the athlete who made the last proposal.
+// Bouts that are accepted or declined while the job runs are left alone.
+func (s *ChallengeExpiryService) Run() error {
+	bouts, err := s.repo.GetExpiredChallenges()
+	if err != nil {
+		return fmt.Errorf("failed to get expired challenges: %w", err)
+	}
+
+	expired := 0
+	for _, bout := range bouts {
+		if !canTransitionBout(bout.Status, models.BoutStatusExpired) {
+			continue
+		}
+
+		message := fmt.Sprintf("Your proposal for bout %d expired before it was answered", bout.BoutId)
+		err := s.repo.ExpireChallenge(bout, message)
+		if errors.Is(err, repositories.ErrBoutStatusChanged) {
+			log.Printf("Bout %d was answered before it could expire", bout.BoutId)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to expire bout %d: %w", bout.BoutId, err)
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		log.Printf("Expired %d unanswered challenges", expired)
+	}
+
+	unclaimed, err := s.openChallengeRepo.ExpireOpenChallenges("Your open challenge expired before anyone claimed it")
+	if err != nil {
+		return fmt.Errorf("failed to expire open challenges: %w", err)
+	}
+	if unclaimed > 0 {
+		log.Printf("Expired %d unclaimed open challenges", unclaimed)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"ronin/interfaces"
+	"ronin/models"
+)
+
+// EloSettings are the tunable parameters of the Elo rating engine.
+// KFactor is the base K; the remaining fields form the style's K-factor policy.
+type EloSettings struct {
+	KFactor            float64          `json:"kFactor"`
+	ProvisionalBouts   int              `json:"provisionalBouts"`
+	ProvisionalKFactor float64          `json:"provisionalKFactor"`
+	ExperienceBands    []ExperienceBand `json:"experienceBands"`
+	RatingBands        []RatingBand     `json:"ratingBands"`
+}
+
+// ExperienceBand sets the K-factor for athletes with at least MinBouts rated bouts
+type ExperienceBand struct {
+	MinBouts int     `json:"minBouts"`
+	KFactor  float64 `json:"kFactor"`
+}
+
+// RatingBand caps the K-factor for athletes rated at or above MinScore
+type RatingBand struct {
+	MinScore float64 `json:"minScore"`
+	KFactor  float64 `json:"kFactor"`
+}
+
+// DefaultEloSettings returns the settings used when a style has no overrides
+func DefaultEloSettings() EloSettings {
+	return EloSettings{
+		KFactor: 32.0,
+	}
+}
+
+// eloRatingEngine implements the interfaces.RatingEngine interface using classic Elo
+type eloRatingEngine struct {
+	settings EloSettings
+}
+
+// NewEloRatingEngine creates a new Elo rating engine
+func NewEloRatingEngine(settings EloSettings) (interfaces.RatingEngine, error) {
+	if settings.KFactor <= 0 {
+		return nil, errors.New("elo k-factor must be greater than zero")
+	}
+	if settings.ProvisionalBouts < 0 {
+		return nil, errors.New("provisional bouts cannot be negative")
+	}
+	if settings.ProvisionalBouts > 0 && settings.ProvisionalKFactor <= 0 {
+		return nil, errors.New("provisional k-factor must be greater than zero")
+	}
+	for _, band := range settings.ExperienceBands {
+		if band.KFactor <= 0 {
+			return nil, fmt.Errorf("k-factor for experience band at %d bouts must be greater than zero", band.MinBouts)
+		}
+	}
+	for _, band := range settings.RatingBands {
+		if band.KFactor <= 0 {
+			return nil, fmt.Errorf("k-factor for rating band at %.0f must be greater than zero", band.MinScore)
+		}
+	}
+	return &eloRatingEngine{
+		settings: settings,
+	}, nil
+}
+
+// Name returns the engine identifier stored in style_rating_config
+func (e *eloRatingEngine) Name() string {
+	return EngineElo
+}
+
+// Rate applies a single Elo update to both athletes
+func (e *eloRatingEngine) Rate(input models.RatingInput) (models.RatingResult, error) {
+	winner := input.Winner
+	loser := input.Loser
+
+	expectedScoreWinner := eloExpectedScore(winner.Score, loser.Score)
+	expectedScoreLoser := eloExpectedScore(loser.Score, winner.Score)
+
+	winnerK := e.kFactorFor(input.Winner)
+	loserK := e.kFactorFor(input.Loser)
+
+	if input.IsDraw {
+		winner.Score += winnerK * (0.5 - expectedScoreWinner)
+		loser.Score += loserK * (0.5 - expectedScoreLoser)
+	} else {
+		winner.Score += winnerK * (1.0 - expectedScoreWinner)
+		loser.Score += loserK * (0.0 - expectedScoreLoser)
+	}
+
+	return models.RatingResult{
+		Winner: models.RatingChange{AthleteScore: winner, KFactor: winnerK},
+		Loser:  models.RatingChange{AthleteScore: loser, KFactor: loserK},
+	}, nil
+}
+
+// ExpectedScore returns the probability that athlete beats opponent
+func (e *eloRatingEngine) ExpectedScore(athlete, opponent models.AthleteScore) float64 {
+	return eloExpectedScore(athlete.Score, opponent.Score)
+}
+
+// kFactorFor applies the style's K-factor policy to an athlete going into a bout.
+// Athletes in their first rated bouts, or flagged provisional after returning from inactivity,
+// get the provisional K. Otherwise the experience band for their bout count replaces the base K,
+// and a matching rating band can only lower it.
+func (e *eloRatingEngine) kFactorFor(score models.AthleteScore) float64 {
+	provisional := score.RatedBouts < e.settings.ProvisionalBouts || score.Provisional
+	if provisional && e.settings.ProvisionalKFactor > 0 {
+		return e.settings.ProvisionalKFactor
+	}
+
+	k := e.settings.KFactor
+	bestMinBouts := -1
+	for _, band := range e.settings.ExperienceBands {
+		if score.RatedBouts >= band.MinBouts && band.MinBouts > bestMinBouts {
+			k = band.KFactor
+			bestMinBouts = band.MinBouts
+		}
+	}
+
+	for _, band := range e.settings.RatingBands {
+		if score.Score >= band.MinScore && band.KFactor < k {
+			k = band.KFactor
+		}
+	}
+	return k
+}
+
+// eloExpectedScore returns the probability that an athlete rated score beats one rated opponentScore
+func eloExpectedScore(score, opponentScore float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10, (opponentScore-score)/400.0))
+}
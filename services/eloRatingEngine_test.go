@@ -0,0 +1,146 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"ronin/models"
+)
+
+// ratingTolerance is how far a computed rating may drift from the expected value in tests
+const ratingTolerance = 0.0001
+
+func TestEloRate(t *testing.T) {
+	tests := []struct {
+		name       string
+		winner     float64
+		loser      float64
+		isDraw     bool
+		wantWinner float64
+		wantLoser  float64
+	}{
+		{name: "equal ratings", winner: 400, loser: 400, wantWinner: 416, wantLoser: 384},
+		{name: "draw between equals", winner: 400, loser: 400, isDraw: true, wantWinner: 400, wantLoser: 400},
+		{name: "upset", winner: 400, loser: 800, wantWinner: 400 + 32*10.0/11, wantLoser: 800 - 32*10.0/11},
+		{name: "expected win", winner: 800, loser: 400, wantWinner: 800 + 32*1.0/11, wantLoser: 400 - 32*1.0/11},
+		{name: "draw against stronger", winner: 400, loser: 800, isDraw: true, wantWinner: 400 + 32*(0.5-1.0/11), wantLoser: 800 + 32*(0.5-10.0/11)},
+	}
+
+	engine, err := NewEloRatingEngine(DefaultEloSettings())
+	if err != nil {
+		t.Fatalf("NewEloRatingEngine() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Rate(models.RatingInput{
+				Winner: models.AthleteScore{AthleteId: 1, Score: tt.winner},
+				Loser:  models.AthleteScore{AthleteId: 2, Score: tt.loser},
+				IsDraw: tt.isDraw,
+			})
+			if err != nil {
+				t.Fatalf("Rate() error = %v", err)
+			}
+			if math.Abs(result.Winner.Score-tt.wantWinner) > ratingTolerance {
+				t.Errorf("winner score = %v, want %v", result.Winner.Score, tt.wantWinner)
+			}
+			if math.Abs(result.Loser.Score-tt.wantLoser) > ratingTolerance {
+				t.Errorf("loser score = %v, want %v", result.Loser.Score, tt.wantLoser)
+			}
+			if result.Winner.KFactor != 32 || result.Loser.KFactor != 32 {
+				t.Errorf("k-factors = %v and %v, want 32", result.Winner.KFactor, result.Loser.KFactor)
+			}
+		})
+	}
+}
+
+func TestEloKFactor(t *testing.T) {
+	settings := EloSettings{
+		KFactor:            32,
+		ProvisionalBouts:   10,
+		ProvisionalKFactor: 40,
+		ExperienceBands:    []ExperienceBand{{MinBouts: 30, KFactor: 24}, {MinBouts: 60, KFactor: 16}},
+		RatingBands:        []RatingBand{{MinScore: 2000, KFactor: 20}, {MinScore: 2400, KFactor: 10}},
+	}
+
+	tests := []struct {
+		name  string
+		score models.AthleteScore
+		want  float64
+	}{
+		{name: "new athlete", score: models.AthleteScore{Score: 400, RatedBouts: 0}, want: 40},
+		{name: "last provisional bout", score: models.AthleteScore{Score: 400, RatedBouts: 9}, want: 40},
+		{name: "base k", score: models.AthleteScore{Score: 400, RatedBouts: 10}, want: 32},
+		{name: "flagged provisional after decay", score: models.AthleteScore{Score: 400, RatedBouts: 100, Provisional: true}, want: 40},
+		{name: "experience band", score: models.AthleteScore{Score: 400, RatedBouts: 30}, want: 24},
+		{name: "highest experience band", score: models.AthleteScore{Score: 400, RatedBouts: 75}, want: 16},
+		{name: "rating band lowers k", score: models.AthleteScore{Score: 2100, RatedBouts: 30}, want: 20},
+		{name: "highest rating band", score: models.AthleteScore{Score: 2500, RatedBouts: 30}, want: 10},
+		{name: "rating band never raises k", score: models.AthleteScore{Score: 2100, RatedBouts: 75}, want: 16},
+	}
+
+	engine, err := NewEloRatingEngine(settings)
+	if err != nil {
+		t.Fatalf("NewEloRatingEngine() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.(*eloRatingEngine).kFactorFor(tt.score); got != tt.want {
+				t.Errorf("kFactorFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEloRatingEngineValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings EloSettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: DefaultEloSettings()},
+		{name: "zero k-factor", settings: EloSettings{KFactor: 0}, wantErr: true},
+		{name: "negative provisional bouts", settings: EloSettings{KFactor: 32, ProvisionalBouts: -1}, wantErr: true},
+		{name: "provisional bouts without k-factor", settings: EloSettings{KFactor: 32, ProvisionalBouts: 5}, wantErr: true},
+		{name: "zero experience band k-factor", settings: EloSettings{KFactor: 32, ExperienceBands: []ExperienceBand{{MinBouts: 10}}}, wantErr: true},
+		{name: "zero rating band k-factor", settings: EloSettings{KFactor: 32, RatingBands: []RatingBand{{MinScore: 2000}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEloRatingEngine(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEloRatingEngine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRatingEngine(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   models.StyleRatingConfig
+		wantName string
+		wantErr  bool
+	}{
+		{name: "no config uses elo", config: models.StyleRatingConfig{}, wantName: EngineElo},
+		{name: "elo with settings", config: models.StyleRatingConfig{Engine: EngineElo, Settings: []byte(`{"kFactor": 24}`)}, wantName: EngineElo},
+		{name: "invalid settings", config: models.StyleRatingConfig{Engine: EngineElo, Settings: []byte(`{"kFactor": 0}`)}, wantErr: true},
+		{name: "malformed settings", config: models.StyleRatingConfig{Engine: EngineElo, Settings: []byte(`{`)}, wantErr: true},
+		{name: "glicko2", config: models.StyleRatingConfig{Engine: EngineGlicko2, Settings: []byte(`{"tau": 0.3}`)}, wantName: EngineGlicko2},
+		{name: "unknown engine", config: models.StyleRatingConfig{Engine: "trueskill"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewRatingEngine(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRatingEngine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && engine.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", engine.Name(), tt.wantName)
+			}
+		})
+	}
+}
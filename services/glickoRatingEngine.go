@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"ronin/interfaces"
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// EngineGlicko2 selects the Glicko-2 rating engine in style_rating_config
+const EngineGlicko2 = "glicko2"
+
+// glickoScale converts between the displayed rating scale and the Glicko-2 internal scale
+const glickoScale = 173.7178
+
+// glickoConvergence is the tolerance of the volatility iteration
+const glickoConvergence = 0.000001
+
+// Glicko2Settings are the tunable parameters of the Glicko-2 rating engine
+type Glicko2Settings struct {
+	Tau                float64 `json:"tau"`
+	MaxRatingDeviation float64 `json:"maxRatingDeviation"`
+	RatingPeriodDays   float64 `json:"ratingPeriodDays"`
+}
+
+// DefaultGlicko2Settings returns the settings used when a style has no overrides
+func DefaultGlicko2Settings() Glicko2Settings {
+	return Glicko2Settings{
+		Tau:                0.5,
+		MaxRatingDeviation: 350.0,
+		RatingPeriodDays:   30.0,
+	}
+}
+
+// glicko2RatingEngine implements the interfaces.RatingEngine interface using Glicko-2,
+// treating every bout as its own rating period
+type glicko2RatingEngine struct {
+	settings Glicko2Settings
+}
+
+// NewGlicko2RatingEngine creates a new Glicko-2 rating engine
+func NewGlicko2RatingEngine(settings Glicko2Settings) (interfaces.RatingEngine, error) {
+	if settings.Tau <= 0 {
+		return nil, errors.New("glicko-2 tau must be greater than zero")
+	}
+	if settings.MaxRatingDeviation <= 0 {
+		return nil, errors.New("glicko-2 max rating deviation must be greater than zero")
+	}
+	if settings.RatingPeriodDays <= 0 {
+		return nil, errors.New("glicko-2 rating period must be greater than zero")
+	}
+	return &glicko2RatingEngine{
+		settings: settings,
+	}, nil
+}
+
+// Name returns the engine identifier stored in style_rating_config
+func (e *glicko2RatingEngine) Name() string {
+	return EngineGlicko2
+}
+
+// Rate applies a Glicko-2 update to both athletes
+func (e *glicko2RatingEngine) Rate(input models.RatingInput) (models.RatingResult, error) {
+	playedAt := input.PlayedAt
+	if playedAt.IsZero() {
+		playedAt = time.Now().UTC()
+	}
+
+	// Inactive athletes become less certain before the bout is applied
+	winner := withGlickoDefaults(input.Winner)
+	loser := withGlickoDefaults(input.Loser)
+	winner.RatingDeviation = e.inflateDeviation(winner, playedAt)
+	loser.RatingDeviation = e.inflateDeviation(loser, playedAt)
+
+	winnerResult, loserResult := 1.0, 0.0
+	if input.IsDraw {
+		winnerResult, loserResult = 0.5, 0.5
+	}
+
+	return models.RatingResult{
+		Winner: models.RatingChange{AthleteScore: e.update(winner, loser, winnerResult)},
+		Loser:  models.RatingChange{AthleteScore: e.update(loser, winner, loserResult)},
+	}, nil
+}
+
+// ExpectedScore returns the probability that athlete beats opponent, discounted by
+// the uncertainty in both ratings
+func (e *glicko2RatingEngine) ExpectedScore(athlete, opponent models.AthleteScore) float64 {
+	now := time.Now().UTC()
+	athlete = withGlickoDefaults(athlete)
+	opponent = withGlickoDefaults(opponent)
+	phi := e.inflateDeviation(athlete, now) / glickoScale
+	opponentPhi := e.inflateDeviation(opponent, now) / glickoScale
+
+	g := glickoG(math.Sqrt(phi*phi + opponentPhi*opponentPhi))
+	return 1.0 / (1.0 + math.Exp(-g*(athlete.Score-opponent.Score)/glickoScale))
+}
+
+// inflateDeviation grows an athlete's rating deviation by the rating periods they sat out
+func (e *glicko2RatingEngine) inflateDeviation(score models.AthleteScore, playedAt time.Time) float64 {
+	deviation := score.RatingDeviation
+	lastRated, err := parseStoredTime(score.UpdatedDate)
+	playedAt = playedAt.UTC()
+	if err != nil || !lastRated.Before(playedAt) {
+		return deviation
+	}
+
+	periods := playedAt.Sub(lastRated).Hours() / 24.0 / e.settings.RatingPeriodDays
+	phi := deviation / glickoScale
+	phi = math.Sqrt(phi*phi + periods*score.Volatility*score.Volatility)
+	return math.Min(phi*glickoScale, e.settings.MaxRatingDeviation)
+}
+
+// storedTimeLayouts are the forms a timestamp without time zone can be read back in
+var storedTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999"}
+
+// parseStoredTime reads a timestamp without time zone, such as athlete_score.updated_dt, as the UTC
+// time it was written in
+func parseStoredTime(value string) (time.Time, error) {
+	var err error
+	for _, layout := range storedTimeLayouts {
+		var parsed time.Time
+		if parsed, err = time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// update runs the Glicko-2 step for one athlete against a single opponent
+func (e *glicko2RatingEngine) update(athlete, opponent models.AthleteScore, result float64) models.AthleteScore {
+	mu := athlete.Score / glickoScale
+	phi := athlete.RatingDeviation / glickoScale
+	sigma := athlete.Volatility
+	opponentMu := opponent.Score / glickoScale
+	opponentPhi := opponent.RatingDeviation / glickoScale
+
+	g := glickoG(opponentPhi)
+	expected := 1.0 / (1.0 + math.Exp(-g*(mu-opponentMu)))
+	v := 1.0 / (g * g * expected * (1.0 - expected))
+	delta := v * g * (result - expected)
+
+	newSigma := e.newVolatility(phi, sigma, v, delta)
+	phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+	newPhi := 1.0 / math.Sqrt(1.0/(phiStar*phiStar)+1.0/v)
+	newMu := mu + newPhi*newPhi*g*(result-expected)
+
+	athlete.Score = newMu * glickoScale
+	athlete.RatingDeviation = math.Min(newPhi*glickoScale, e.settings.MaxRatingDeviation)
+	athlete.Volatility = newSigma
+	return athlete
+}
+
+// newVolatility solves for the updated volatility using the Illinois algorithm
+func (e *glicko2RatingEngine) newVolatility(phi, sigma, v, delta float64) float64 {
+	tau := e.settings.Tau
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2.0*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoConvergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA = fA / 2.0
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2.0)
+}
+
+// withGlickoDefaults fills in deviation and volatility for scores that were never rated by Glicko-2
+func withGlickoDefaults(score models.AthleteScore) models.AthleteScore {
+	if score.RatingDeviation <= 0 {
+		score.RatingDeviation = repositories.DefaultRatingDeviation
+	}
+	if score.Volatility <= 0 {
+		score.Volatility = repositories.DefaultVolatility
+	}
+	return score
+}
+
+// glickoG dampens the impact of an opponent whose rating is uncertain
+func glickoG(phi float64) float64 {
+	return 1.0 / math.Sqrt(1.0+3.0*phi*phi/(math.Pi*math.Pi))
+}
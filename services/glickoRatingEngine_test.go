@@ -0,0 +1,143 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ronin/models"
+)
+
+func TestGlicko2Rate(t *testing.T) {
+	tests := []struct {
+		name       string
+		winner     models.AthleteScore
+		loser      models.AthleteScore
+		isDraw     bool
+		wantWinner models.AthleteScore
+		wantLoser  models.AthleteScore
+	}{
+		{
+			// The first opponent of the worked example in Glickman's Glicko-2 paper, as a single bout
+			name:       "upset of an established athlete",
+			winner:     models.AthleteScore{Score: 1500, RatingDeviation: 200, Volatility: 0.06},
+			loser:      models.AthleteScore{Score: 1400, RatingDeviation: 30, Volatility: 0.06},
+			wantWinner: models.AthleteScore{Score: 1563.5642, RatingDeviation: 175.4027, Volatility: 0.059999},
+			wantLoser:  models.AthleteScore{Score: 1398.1436, RatingDeviation: 31.6702, Volatility: 0.059999},
+		},
+		{
+			name:       "new athletes",
+			winner:     models.AthleteScore{Score: 1500, RatingDeviation: 350, Volatility: 0.06},
+			loser:      models.AthleteScore{Score: 1500, RatingDeviation: 350, Volatility: 0.06},
+			wantWinner: models.AthleteScore{Score: 1662.3109, RatingDeviation: 290.3190, Volatility: 0.059999},
+			wantLoser:  models.AthleteScore{Score: 1337.6891, RatingDeviation: 290.3190, Volatility: 0.059999},
+		},
+		{
+			name:       "unrated athletes get the default deviation and volatility",
+			winner:     models.AthleteScore{Score: 1500},
+			loser:      models.AthleteScore{Score: 1500},
+			wantWinner: models.AthleteScore{Score: 1662.3109, RatingDeviation: 290.3190, Volatility: 0.059999},
+			wantLoser:  models.AthleteScore{Score: 1337.6891, RatingDeviation: 290.3190, Volatility: 0.059999},
+		},
+		{
+			name:       "draw between equals",
+			winner:     models.AthleteScore{Score: 1500, RatingDeviation: 350, Volatility: 0.06},
+			loser:      models.AthleteScore{Score: 1500, RatingDeviation: 350, Volatility: 0.06},
+			isDraw:     true,
+			wantWinner: models.AthleteScore{Score: 1500, RatingDeviation: 290.3190, Volatility: 0.059999},
+			wantLoser:  models.AthleteScore{Score: 1500, RatingDeviation: 290.3190, Volatility: 0.059999},
+		},
+	}
+
+	engine, err := NewGlicko2RatingEngine(DefaultGlicko2Settings())
+	if err != nil {
+		t.Fatalf("NewGlicko2RatingEngine() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Rate(models.RatingInput{Winner: tt.winner, Loser: tt.loser, IsDraw: tt.isDraw})
+			if err != nil {
+				t.Fatalf("Rate() error = %v", err)
+			}
+			assertGlickoScore(t, "winner", result.Winner.AthleteScore, tt.wantWinner)
+			assertGlickoScore(t, "loser", result.Loser.AthleteScore, tt.wantLoser)
+		})
+	}
+}
+
+func TestGlicko2InflateDeviation(t *testing.T) {
+	playedAt := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		deviation   float64
+		lastRatedAt string
+		playedIn    *time.Location
+		want        float64
+	}{
+		{name: "rated just now", deviation: 50, lastRatedAt: playedAt.Format(time.RFC3339Nano), want: 50},
+		{name: "never rated", deviation: 50, lastRatedAt: "", want: 50},
+		{name: "one period out", deviation: 50, lastRatedAt: playedAt.AddDate(0, 0, -30).Format(time.RFC3339Nano), want: 51.0749},
+		{name: "one period out stored without a time zone", deviation: 50, lastRatedAt: playedAt.AddDate(0, 0, -30).Format("2006-01-02 15:04:05.999999"), want: 51.0749},
+		{name: "one period out stored in UTC, played in another zone", deviation: 50, lastRatedAt: "2026-05-02T00:00:00",
+			playedIn: time.FixedZone("UTC-5", -5*60*60), want: 51.0749},
+		{name: "four periods out", deviation: 50, lastRatedAt: playedAt.AddDate(0, 0, -120).Format(time.RFC3339Nano), want: 54.1716},
+		{name: "capped at the maximum", deviation: 349, lastRatedAt: playedAt.AddDate(-10, 0, 0).Format(time.RFC3339Nano), want: 350},
+	}
+
+	engine, err := NewGlicko2RatingEngine(DefaultGlicko2Settings())
+	if err != nil {
+		t.Fatalf("NewGlicko2RatingEngine() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := models.AthleteScore{RatingDeviation: tt.deviation, Volatility: 0.06, UpdatedDate: tt.lastRatedAt}
+			at := playedAt
+			if tt.playedIn != nil {
+				at = playedAt.In(tt.playedIn)
+			}
+			got := engine.(*glicko2RatingEngine).inflateDeviation(score, at)
+			if math.Abs(got-tt.want) > ratingTolerance {
+				t.Errorf("inflateDeviation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGlicko2RatingEngineValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Glicko2Settings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: DefaultGlicko2Settings()},
+		{name: "zero tau", settings: Glicko2Settings{Tau: 0, MaxRatingDeviation: 350, RatingPeriodDays: 30}, wantErr: true},
+		{name: "zero max deviation", settings: Glicko2Settings{Tau: 0.5, MaxRatingDeviation: 0, RatingPeriodDays: 30}, wantErr: true},
+		{name: "zero rating period", settings: Glicko2Settings{Tau: 0.5, MaxRatingDeviation: 350, RatingPeriodDays: 0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGlicko2RatingEngine(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewGlicko2RatingEngine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// assertGlickoScore compares a Glicko-2 rating, deviation and volatility to the expected values
+func assertGlickoScore(t *testing.T, who string, got, want models.AthleteScore) {
+	t.Helper()
+	if math.Abs(got.Score-want.Score) > ratingTolerance {
+		t.Errorf("%s score = %v, want %v", who, got.Score, want.Score)
+	}
+	if math.Abs(got.RatingDeviation-want.RatingDeviation) > ratingTolerance {
+		t.Errorf("%s rating deviation = %v, want %v", who, got.RatingDeviation, want.RatingDeviation)
+	}
+	if math.Abs(got.Volatility-want.Volatility) > 0.000001 {
+		t.Errorf("%s volatility = %v, want %v", who, got.Volatility, want.Volatility)
+	}
+}
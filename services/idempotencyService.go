@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a mutation safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentReplayHeader marks a response replayed from an earlier request with the same key
+const idempotentReplayHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength matches idempotency_key.idempotency_key
+const maxIdempotencyKeyLength = 255
+
+// idempotencyAbandonAfter is how long a request can stay in progress before its key is treated as
+// abandoned, for example after a crash, and a retry may run it again
+const idempotencyAbandonAfter = 5 * time.Minute
+
+// IdempotencyService remembers the response to each mutation sent with an Idempotency-Key header
+// for a retention window, so a retried request gets the original response instead of running again
+type IdempotencyService struct {
+	repo      *repositories.IdempotencyRepository
+	retention time.Duration
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService
+func NewIdempotencyService(repo *repositories.IdempotencyRepository, retention time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:      repo,
+		retention: retention,
+	}
+}
+
+// Idempotent wraps a mutation handler. Requests without an Idempotency-Key header run as usual.
+// A key is only accepted from a signed-in athlete, so one caller can never be handed another's
+// response; anonymous requests with a key get 401. The first request with a key runs and its
+// response is stored under the signed-in athlete and key. Retries with the same key and body get that response back without running the handler.
+// A key reused for a different request is rejected with 422, and a retry that arrives while the
+// first request is still running gets 409. Server errors are not stored, so they can be retried.
+func (s *IdempotencyService) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			SendError(w, fmt.Sprintf("%s cannot be longer than %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength), http.StatusBadRequest)
+			return
+		}
+		athleteId := authenticatedAthleteId(r)
+		if athleteId == 0 {
+			SendError(w, fmt.Sprintf("%s requires a signed-in athlete", IdempotencyKeyHeader), http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			SendError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		request := models.IdempotencyKey{
+			Key:           key,
+			AthleteId:     athleteId,
+			RequestMethod: r.Method,
+			RequestPath:   r.URL.Path,
+			RequestHash:   hex.EncodeToString(hash[:]),
+		}
+		stored, claimed, err := s.repo.ClaimIdempotencyKey(request, s.retention, idempotencyAbandonAfter)
+		if err != nil {
+			log.Printf("Failed to claim idempotency key %q: %v", key, err)
+			SendError(w, "Failed to check idempotency key", http.StatusInternalServerError)
+			return
+		}
+
+		if !claimed {
+			s.replay(w, request, stored)
+			return
+		}
+
+		recorder := &idempotentResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		finished := false
+		defer func() {
+			if !finished {
+				s.release(stored)
+			}
+		}()
+		next(recorder, r)
+		finished = true
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			s.release(stored)
+			return
+		}
+		err = s.repo.SaveIdempotentResponse(stored.IdempotencyKeyId, recorder.statusCode, recorder.Header().Get("Content-Type"), recorder.body.String())
+		if err != nil {
+			// The mutation already ran, so keep the key held rather than letting a retry repeat it
+			log.Printf("Failed to store response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// replay answers a request whose key is already held with the stored response, or explains why it cannot
+func (s *IdempotencyService) replay(w http.ResponseWriter, request models.IdempotencyKey, stored models.IdempotencyKey) {
+	if stored.RequestMethod != request.RequestMethod || stored.RequestPath != request.RequestPath || stored.RequestHash != request.RequestHash {
+		SendError(w, fmt.Sprintf("%s %q was already used for a different request", IdempotencyKeyHeader, request.Key), http.StatusUnprocessableEntity)
+		return
+	}
+	if stored.StatusCode == nil {
+		w.Header().Set("Retry-After", "1")
+		SendError(w, fmt.Sprintf("A request with %s %q is still in progress", IdempotencyKeyHeader, request.Key), http.StatusConflict)
+		return
+	}
+
+	if stored.ContentType != nil && *stored.ContentType != "" {
+		w.Header().Set("Content-Type", *stored.ContentType)
+	}
+	w.Header().Set(idempotentReplayHeader, "true")
+	w.WriteHeader(*stored.StatusCode)
+	if stored.ResponseBody != nil {
+		io.WriteString(w, *stored.ResponseBody)
+	}
+}
+
+// release frees a key whose request did not finish, so a retry runs it again
+func (s *IdempotencyService) release(stored models.IdempotencyKey) {
+	if err := s.repo.ReleaseIdempotencyKey(stored.IdempotencyKeyId); err != nil {
+		log.Printf("Failed to release idempotency key %q: %v", stored.Key, err)
+	}
+}
+
+// DeleteExpired deletes every stored response past its retention window
+func (s *IdempotencyService) DeleteExpired() error {
+	deleted, err := s.repo.DeleteExpiredIdempotencyKeys()
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d expired idempotency keys", deleted)
+	}
+	return nil
+}
+
+// idempotentResponseWriter passes a response through while keeping a copy to store
+type idempotentResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *idempotentResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *idempotentResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestIdempotencyReplay(t *testing.T) {
+	created := http.StatusCreated
+	contentType := "application/json"
+	body := `{"boutId":12}`
+	request := models.IdempotencyKey{Key: "retry-1", AthleteId: 4, RequestMethod: http.MethodPost, RequestPath: "/api/v1/bout", RequestHash: "abc"}
+	finished := request
+	finished.StatusCode = &created
+	finished.ContentType = &contentType
+	finished.ResponseBody = &body
+
+	tests := []struct {
+		name         string
+		stored       models.IdempotencyKey
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{name: "finished request", stored: finished, wantStatus: http.StatusCreated, wantBody: body, wantReplayed: true},
+		{name: "still in progress", stored: request, wantStatus: http.StatusConflict},
+		{name: "different body", stored: withIdempotentRequest(finished, http.MethodPost, "/api/v1/bout", "def"), wantStatus: http.StatusUnprocessableEntity},
+		{name: "different path", stored: withIdempotentRequest(finished, http.MethodPost, "/api/v1/outcome", "abc"), wantStatus: http.StatusUnprocessableEntity},
+		{name: "different method", stored: withIdempotentRequest(finished, http.MethodPut, "/api/v1/bout", "abc"), wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			(&IdempotencyService{}).replay(w, request, tt.stored)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("replay() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if replayed := w.Header().Get(idempotentReplayHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replay() marked replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if tt.wantBody != "" {
+				if w.Body.String() != tt.wantBody || w.Header().Get("Content-Type") != contentType {
+					t.Errorf("replay() body = %q (%s), want %q (%s)", w.Body.String(), w.Header().Get("Content-Type"), tt.wantBody, contentType)
+				}
+			}
+			if tt.wantStatus == http.StatusConflict && w.Header().Get("Retry-After") == "" {
+				t.Errorf("replay() of a request in progress has no Retry-After header")
+			}
+		})
+	}
+}
+
+func TestIdempotentWithoutStoredKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus int
+		wantRun    bool
+	}{
+		{name: "no key runs the handler", wantStatus: http.StatusCreated, wantRun: true},
+		{name: "key too long", key: strings.Repeat("k", maxIdempotencyKeyLength+1), wantStatus: http.StatusBadRequest},
+		{name: "key without a signed-in athlete", key: "retry-1", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			// No case reaches the repository
+			handler := (&IdempotencyService{}).Idempotent(func(w http.ResponseWriter, r *http.Request) {
+				ran = true
+				w.WriteHeader(http.StatusCreated)
+			})
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/bout", strings.NewReader(`{}`))
+			if tt.key != "" {
+				r.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if ran != tt.wantRun {
+				t.Errorf("handler ran = %v, want %v", ran, tt.wantRun)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// withIdempotentRequest returns a stored key that was first used for another request
+func withIdempotentRequest(stored models.IdempotencyKey, method, path, hash string) models.IdempotencyKey {
+	stored.RequestMethod = method
+	stored.RequestPath = path
+	stored.RequestHash = hash
+	return stored
+}
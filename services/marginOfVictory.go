@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"ronin/models"
+)
+
+// Rules for how forfeits and disqualifications affect ratings
+const (
+	// OutcomeRuleRated rates the outcome like any other win
+	OutcomeRuleRated = "rated"
+	// OutcomeRuleUnrated records the outcome without changing either rating
+	OutcomeRuleUnrated = "unrated"
+	// OutcomeRuleLoserOnly lowers the loser's rating and leaves the winner's unchanged
+	OutcomeRuleLoserOnly = "loser_only"
+)
+
+// MarginOfVictorySettings scale a rating change by how decisively the bout was won.
+// They are read from the same style_rating_config settings as the engine and apply to every engine.
+type MarginOfVictorySettings struct {
+	FinishMultipliers    map[string]float64 `json:"finishMultipliers"`
+	MarginStep           float64            `json:"marginStep"`
+	MaxMultiplier        float64            `json:"maxMultiplier"`
+	ForfeitRule          string             `json:"forfeitRule"`
+	DisqualificationRule string             `json:"disqualificationRule"`
+}
+
+// DefaultMarginOfVictorySettings returns settings that leave rating changes unscaled
+// and do not rate forfeits
+func DefaultMarginOfVictorySettings() MarginOfVictorySettings {
+	return MarginOfVictorySettings{
+		FinishMultipliers:    map[string]float64{},
+		MarginStep:           0,
+		MaxMultiplier:        2.0,
+		ForfeitRule:          OutcomeRuleUnrated,
+		DisqualificationRule: OutcomeRuleRated,
+	}
+}
+
+// validate checks the margin-of-victory settings for a style
+func (m MarginOfVictorySettings) validate() error {
+	for method, multiplier := range m.FinishMultipliers {
+		if !isFinishMethod(method) {
+			return fmt.Errorf("unknown finish method %q", method)
+		}
+		if multiplier <= 0 {
+			return fmt.Errorf("multiplier for %s must be greater than zero", method)
+		}
+	}
+	if m.MarginStep < 0 {
+		return errors.New("margin step cannot be negative")
+	}
+	if m.MaxMultiplier <= 0 {
+		return errors.New("max multiplier must be greater than zero")
+	}
+	for _, rule := range []string{m.ForfeitRule, m.DisqualificationRule} {
+		if rule != OutcomeRuleRated && rule != OutcomeRuleUnrated && rule != OutcomeRuleLoserOnly {
+			return fmt.Errorf("unknown outcome rule %q", rule)
+		}
+	}
+	return nil
+}
+
+// ruleFor returns how an outcome with the given finish method should be rated
+func (m MarginOfVictorySettings) ruleFor(finishMethod string) string {
+	switch finishMethod {
+	case models.FinishForfeit:
+		return m.ForfeitRule
+	case models.FinishDisqualification:
+		return m.DisqualificationRule
+	default:
+		return OutcomeRuleRated
+	}
+}
+
+// multiplierFor returns the factor a decisive result scales the rating change by
+func (m MarginOfVictorySettings) multiplierFor(input models.RatingInput) float64 {
+	if input.IsDraw {
+		return 1.0
+	}
+
+	multiplier := 1.0
+	if finish, ok := m.FinishMultipliers[input.FinishMethod]; ok {
+		multiplier = finish
+	}
+	multiplier *= 1.0 + m.MarginStep*float64(input.ScoreMargin)
+	return math.Min(multiplier, m.MaxMultiplier)
+}
+
+// apply scales the engine's rating change and enforces the forfeit and disqualification rules
+func (m MarginOfVictorySettings) apply(input models.RatingInput, result models.RatingResult) models.RatingResult {
+	switch m.ruleFor(input.FinishMethod) {
+	case OutcomeRuleUnrated:
+		result.Unrated = true
+		return result
+	case OutcomeRuleLoserOnly:
+		result.Winner.AthleteScore = input.Winner
+		result.Winner.KFactor = 0
+		result.WinnerUnchanged = true
+	}
+
+	multiplier := m.multiplierFor(input)
+	result.Winner.Score = input.Winner.Score + (result.Winner.Score-input.Winner.Score)*multiplier
+	result.Loser.Score = input.Loser.Score + (result.Loser.Score-input.Loser.Score)*multiplier
+	return result
+}
+
+// isFinishMethod reports whether a finish method is one the platform records
+func isFinishMethod(method string) bool {
+	switch method {
+	case models.FinishSubmission, models.FinishPoints, models.FinishDecision,
+		models.FinishDisqualification, models.FinishForfeit:
+		return true
+	}
+	return false
+}
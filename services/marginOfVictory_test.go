@@ -0,0 +1,102 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestMarginOfVictoryApply(t *testing.T) {
+	input := models.RatingInput{
+		Winner: models.AthleteScore{AthleteId: 1, Score: 400},
+		Loser:  models.AthleteScore{AthleteId: 2, Score: 400},
+	}
+	engineResult := models.RatingResult{
+		Winner: models.RatingChange{AthleteScore: models.AthleteScore{AthleteId: 1, Score: 416}, KFactor: 32},
+		Loser:  models.RatingChange{AthleteScore: models.AthleteScore{AthleteId: 2, Score: 384}, KFactor: 32},
+	}
+	scaled := MarginOfVictorySettings{
+		FinishMultipliers:    map[string]float64{models.FinishSubmission: 1.5},
+		MarginStep:           0.1,
+		MaxMultiplier:        1.8,
+		ForfeitRule:          OutcomeRuleLoserOnly,
+		DisqualificationRule: OutcomeRuleUnrated,
+	}
+
+	tests := []struct {
+		name         string
+		settings     MarginOfVictorySettings
+		finishMethod string
+		scoreMargin  int
+		isDraw       bool
+		wantUnrated  bool
+		wantWinner   float64
+		wantLoser    float64
+	}{
+		{name: "defaults leave a decision unscaled", settings: DefaultMarginOfVictorySettings(), finishMethod: models.FinishDecision, wantWinner: 416, wantLoser: 384},
+		{name: "defaults rate a disqualification", settings: DefaultMarginOfVictorySettings(), finishMethod: models.FinishDisqualification, wantWinner: 416, wantLoser: 384},
+		{name: "defaults leave a forfeit unrated", settings: DefaultMarginOfVictorySettings(), finishMethod: models.FinishForfeit, wantUnrated: true},
+		{name: "finish multiplier", settings: scaled, finishMethod: models.FinishSubmission, wantWinner: 424, wantLoser: 376},
+		{name: "score margin", settings: scaled, finishMethod: models.FinishPoints, scoreMargin: 3, wantWinner: 420.8, wantLoser: 379.2},
+		{name: "capped at the max multiplier", settings: scaled, finishMethod: models.FinishSubmission, scoreMargin: 5, wantWinner: 428.8, wantLoser: 371.2},
+		{name: "draws are never scaled", settings: scaled, finishMethod: models.FinishSubmission, scoreMargin: 5, isDraw: true, wantWinner: 416, wantLoser: 384},
+		{name: "loser only keeps the winner's rating", settings: scaled, finishMethod: models.FinishForfeit, wantWinner: 400, wantLoser: 384},
+		{name: "unrated disqualification", settings: scaled, finishMethod: models.FinishDisqualification, wantUnrated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := input
+			input.FinishMethod = tt.finishMethod
+			input.ScoreMargin = tt.scoreMargin
+			input.IsDraw = tt.isDraw
+			result := tt.settings.apply(input, engineResult)
+			if result.Unrated != tt.wantUnrated {
+				t.Fatalf("Unrated = %v, want %v", result.Unrated, tt.wantUnrated)
+			}
+			if tt.wantUnrated {
+				return
+			}
+			if math.Abs(result.Winner.Score-tt.wantWinner) > ratingTolerance {
+				t.Errorf("winner score = %v, want %v", result.Winner.Score, tt.wantWinner)
+			}
+			if math.Abs(result.Loser.Score-tt.wantLoser) > ratingTolerance {
+				t.Errorf("loser score = %v, want %v", result.Loser.Score, tt.wantLoser)
+			}
+		})
+	}
+}
+
+func TestMarginOfVictorySettingsValidate(t *testing.T) {
+	valid := DefaultMarginOfVictorySettings()
+	withMultipliers := func(multipliers map[string]float64) MarginOfVictorySettings {
+		settings := DefaultMarginOfVictorySettings()
+		settings.FinishMultipliers = multipliers
+		return settings
+	}
+
+	tests := []struct {
+		name     string
+		settings MarginOfVictorySettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: valid},
+		{name: "known finish multiplier", settings: withMultipliers(map[string]float64{models.FinishSubmission: 1.5})},
+		{name: "unknown finish method", settings: withMultipliers(map[string]float64{"knockout": 1.5}), wantErr: true},
+		{name: "zero finish multiplier", settings: withMultipliers(map[string]float64{models.FinishPoints: 0}), wantErr: true},
+		{name: "negative margin step", settings: MarginOfVictorySettings{MarginStep: -0.1, MaxMultiplier: 2, ForfeitRule: OutcomeRuleRated, DisqualificationRule: OutcomeRuleRated}, wantErr: true},
+		{name: "zero max multiplier", settings: MarginOfVictorySettings{MaxMultiplier: 0, ForfeitRule: OutcomeRuleRated, DisqualificationRule: OutcomeRuleRated}, wantErr: true},
+		{name: "unknown forfeit rule", settings: MarginOfVictorySettings{MaxMultiplier: 2, ForfeitRule: "ignore", DisqualificationRule: OutcomeRuleRated}, wantErr: true},
+		{name: "loser only disqualification", settings: MarginOfVictorySettings{MaxMultiplier: 2, ForfeitRule: OutcomeRuleRated, DisqualificationRule: OutcomeRuleLoserOnly}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultMatchmakingLimit = 10
+
+// MatchmakingHandler handles HTTP requests for opponent suggestions
+type MatchmakingHandler struct {
+	service *MatchmakingService
+}
+
+// NewMatchmakingHandler creates a new instance of MatchmakingHandler
+func NewMatchmakingHandler(service *MatchmakingService) *MatchmakingHandler {
+	return &MatchmakingHandler{
+		service: service,
+	}
+}
+
+// SuggestOpponents handles GET requests for ranked opponent suggestions in a style. Optional query
+// parameters: limit (default 10) and the weights rating_weight, styles_weight, gym_weight and recency_weight.
+func (h *MatchmakingHandler) SuggestOpponents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	athleteId, err := strconv.Atoi(vars["athlete_id"])
+	if err != nil {
+		SendError(w, "Invalid athlete_id", http.StatusBadRequest)
+		return
+	}
+	styleId, err := strconv.Atoi(vars["style_id"])
+	if err != nil {
+		SendError(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultMatchmakingLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil {
+			SendError(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	weights := DefaultMatchmakingWeights()
+	for param, weight := range map[string]*float64{
+		"rating_weight":  &weights.Rating,
+		"styles_weight":  &weights.SharedStyles,
+		"gym_weight":     &weights.Gym,
+		"recency_weight": &weights.Recency,
+	} {
+		valueStr := query.Get(param)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			SendError(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
+		*weight = value
+	}
+
+	suggestions, err := h.service.SuggestOpponents(athleteId, styleId, weights, limit)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	SendJSON(w, suggestions)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+const (
+	// matchRatingWindow is the rating gap at which the rating factor reaches zero
+	matchRatingWindow = 400.0
+	// matchRecencyDays is how long after a bout a rematch stops being penalised
+	matchRecencyDays = 180.0
+)
+
+// MatchmakingWeights set how much each factor counts towards a suggestion's match score
+type MatchmakingWeights struct {
+	Rating       float64 `json:"rating"`
+	SharedStyles float64 `json:"sharedStyles"`
+	Gym          float64 `json:"gym"`
+	Recency      float64 `json:"recency"`
+}
+
+// DefaultMatchmakingWeights favours close ratings, then nearby gyms
+func DefaultMatchmakingWeights() MatchmakingWeights {
+	return MatchmakingWeights{
+		Rating:       0.5,
+		SharedStyles: 0.15,
+		Gym:          0.2,
+		Recency:      0.15,
+	}
+}
+
+// validate checks that the weights are usable
+func (w MatchmakingWeights) validate() error {
+	if w.Rating < 0 || w.SharedStyles < 0 || w.Gym < 0 || w.Recency < 0 {
+		return errors.New("matchmaking weights cannot be negative")
+	}
+	if w.total() == 0 {
+		return errors.New("at least one matchmaking weight must be greater than zero")
+	}
+	return nil
+}
+
+func (w MatchmakingWeights) total() float64 {
+	return w.Rating + w.SharedStyles + w.Gym + w.Recency
+}
+
+// MatchmakingService ranks possible opponents for an athlete in a style
+type MatchmakingService struct {
+	repo                *repositories.MatchmakingRepository
+	athleteScoreService *AthleteScoreService
+}
+
+// NewMatchmakingService creates a new instance of MatchmakingService
+func NewMatchmakingService(repo *repositories.MatchmakingRepository, athleteScoreService *AthleteScoreService) *MatchmakingService {
+	return &MatchmakingService{
+		repo:                repo,
+		athleteScoreService: athleteScoreService,
+	}
+}
+
+// SuggestOpponents returns up to limit opponents for an athlete in a style, best match first.
+// Athletes the athlete already has an open bout with are never suggested.
+func (s *MatchmakingService) SuggestOpponents(athleteId, styleId int, weights MatchmakingWeights, limit int) ([]models.MatchSuggestion, error) {
+	if err := weights.validate(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	own, err := s.athleteScoreService.GetAthleteScoreByStyle(athleteId, styleId)
+	if err != nil {
+		return nil, fmt.Errorf("athlete %d is not rated in style %d: %w", athleteId, styleId, err)
+	}
+
+	candidates, err := s.repo.GetMatchCandidates(athleteId, styleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match candidates: %w", err)
+	}
+
+	now := time.Now()
+	suggestions := make([]models.MatchSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		suggestions = append(suggestions, scoreMatch(own.Score, candidate, weights, now))
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].MatchScore != suggestions[j].MatchScore {
+			return suggestions[i].MatchScore > suggestions[j].MatchScore
+		}
+		return suggestions[i].AthleteId < suggestions[j].AthleteId
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// scoreMatch turns a candidate into a suggestion with its factors and weighted match score
+func scoreMatch(ownScore float64, candidate models.MatchCandidate, weights MatchmakingWeights, now time.Time) models.MatchSuggestion {
+	difference := candidate.Score - ownScore
+
+	factors := models.MatchFactors{
+		Rating:  math.Max(0, 1-math.Abs(difference)/matchRatingWindow),
+		Gym:     float64(candidate.GymProximity) / models.GymProximitySameGym,
+		Recency: 1,
+	}
+	if candidate.AthleteStyles > 0 {
+		factors.SharedStyles = float64(candidate.SharedStyles) / float64(candidate.AthleteStyles)
+	}
+	if candidate.LastBoutDate != nil {
+		if last, err := time.Parse(time.RFC3339Nano, *candidate.LastBoutDate); err == nil {
+			days := now.Sub(last).Hours() / 24
+			factors.Recency = math.Min(1, math.Max(0, days/matchRecencyDays))
+		}
+	}
+
+	matchScore := (factors.Rating*weights.Rating +
+		factors.SharedStyles*weights.SharedStyles +
+		factors.Gym*weights.Gym +
+		factors.Recency*weights.Recency) / weights.total()
+
+	return models.MatchSuggestion{
+		AthleteId:        candidate.AthleteId,
+		FirstName:        candidate.FirstName,
+		LastName:         candidate.LastName,
+		Username:         candidate.Username,
+		Score:            candidate.Score,
+		RatingDifference: difference,
+		SharedStyles:     candidate.SharedStyles,
+		GymProximity:     candidate.GymProximity,
+		LastBoutDate:     candidate.LastBoutDate,
+		MatchScore:       matchScore,
+		Factors:          factors,
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ronin/models"
+)
+
+func TestScoreMatch(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -45).Format(time.RFC3339Nano)
+	longAgo := now.AddDate(-1, 0, 0).Format(time.RFC3339Nano)
+
+	tests := []struct {
+		name        string
+		candidate   models.MatchCandidate
+		weights     MatchmakingWeights
+		wantFactors models.MatchFactors
+		wantScore   float64
+	}{
+		{
+			name:        "same rating at the same gym, never fought",
+			candidate:   models.MatchCandidate{Score: 400, SharedStyles: 2, AthleteStyles: 2, GymProximity: models.GymProximitySameGym},
+			weights:     DefaultMatchmakingWeights(),
+			wantFactors: models.MatchFactors{Rating: 1, SharedStyles: 1, Gym: 1, Recency: 1},
+			wantScore:   1,
+		},
+		{
+			name:        "half a window apart in the same city, fought recently",
+			candidate:   models.MatchCandidate{Score: 200, SharedStyles: 1, AthleteStyles: 4, GymProximity: models.GymProximitySameCity, LastBoutDate: &recent},
+			weights:     MatchmakingWeights{Rating: 1, SharedStyles: 1, Gym: 1, Recency: 1},
+			wantFactors: models.MatchFactors{Rating: 0.5, SharedStyles: 0.25, Gym: 2.0 / 3, Recency: 0.25},
+			wantScore:   (0.5 + 0.25 + 2.0/3 + 0.25) / 4,
+		},
+		{
+			name:        "beyond the rating window, last bout long ago",
+			candidate:   models.MatchCandidate{Score: 900, SharedStyles: 1, AthleteStyles: 1, LastBoutDate: &longAgo},
+			weights:     MatchmakingWeights{Rating: 1},
+			wantFactors: models.MatchFactors{Rating: 0, SharedStyles: 1, Gym: 0, Recency: 1},
+			wantScore:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion := scoreMatch(400, tt.candidate, tt.weights, now)
+			got := suggestion.Factors
+			if math.Abs(got.Rating-tt.wantFactors.Rating) > ratingTolerance ||
+				math.Abs(got.SharedStyles-tt.wantFactors.SharedStyles) > ratingTolerance ||
+				math.Abs(got.Gym-tt.wantFactors.Gym) > ratingTolerance ||
+				math.Abs(got.Recency-tt.wantFactors.Recency) > ratingTolerance {
+				t.Errorf("scoreMatch() factors = %+v, want %+v", got, tt.wantFactors)
+			}
+			if math.Abs(suggestion.MatchScore-tt.wantScore) > ratingTolerance {
+				t.Errorf("scoreMatch() match score = %v, want %v", suggestion.MatchScore, tt.wantScore)
+			}
+			if suggestion.RatingDifference != tt.candidate.Score-400 {
+				t.Errorf("scoreMatch() rating difference = %v, want %v", suggestion.RatingDifference, tt.candidate.Score-400)
+			}
+		})
+	}
+}
+
+func TestMatchmakingWeightsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights MatchmakingWeights
+		wantErr bool
+	}{
+		{name: "defaults", weights: DefaultMatchmakingWeights()},
+		{name: "rating only", weights: MatchmakingWeights{Rating: 1}},
+		{name: "all zero", weights: MatchmakingWeights{}, wantErr: true},
+		{name: "negative", weights: MatchmakingWeights{Rating: 1, Gym: -0.5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.weights.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
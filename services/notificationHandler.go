@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// NotificationHandler handles HTTP requests for an athlete's notifications
+type NotificationHandler struct {
+	service *NotificationService
+}
+
+// NewNotificationHandler creates a new instance of NotificationHandler
+func NewNotificationHandler(service *NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// GetNotifications handles GET requests for the signed-in athlete's notifications.
+// Pass unread=true to only return notifications that have not been read.
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	athleteId, err := strconv.Atoi(mux.Vars(r)["athlete_id"])
+	if err != nil {
+		SendError(w, "Invalid athlete_id", http.StatusBadRequest)
+		return
+	}
+
+	actorId := authenticatedAthleteId(r)
+	if actorId == 0 {
+		SendError(w, ErrUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+	if actorId != athleteId {
+		SendError(w, "Athletes can only read their own notifications", http.StatusForbidden)
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	notifications, err := h.service.GetNotifications(athleteId, unreadOnly)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, notifications)
+}
+
+// MarkNotificationRead handles PUT requests from the signed-in athlete to mark a notification as read
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	notificationId, err := strconv.Atoi(mux.Vars(r)["notification_id"])
+	if err != nil {
+		SendError(w, "Invalid notification_id", http.StatusBadRequest)
+		return
+	}
+
+	actorId := authenticatedAthleteId(r)
+	if actorId == 0 {
+		SendError(w, ErrUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.MarkRead(notificationId, actorId); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		SendError(w, err.Error(), status)
+		return
+	}
+	SendJSON(w, map[string]interface{}{"success": true})
+}
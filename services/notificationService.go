@@ -0,0 +1,49 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// ErrNotificationNotFound is returned when an athlete has no notification with the given ID
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationService reads and acknowledges athletes' notifications
+type NotificationService struct {
+	repo *repositories.NotificationRepository
+}
+
+// NewNotificationService creates a new instance of NotificationService
+func NewNotificationService(repo *repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		repo: repo,
+	}
+}
+
+// GetNotifications returns an athlete's notifications, newest first
+func (s *NotificationService) GetNotifications(athleteId int, unreadOnly bool) ([]models.Notification, error) {
+	notifications, err := s.repo.GetNotificationsByAthleteId(athleteId, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications for athlete %d: %w", athleteId, err)
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+	return notifications, nil
+}
+
+// MarkRead marks one of an athlete's notifications as read
+func (s *NotificationService) MarkRead(notificationId int, athleteId int) error {
+	err := s.repo.MarkNotificationRead(notificationId, athleteId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark notification %d as read: %w", notificationId, err)
+	}
+	return nil
+}
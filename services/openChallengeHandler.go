@@ -0,0 +1,134 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ronin/models"
+	"ronin/repositories"
+
+	"github.com/gorilla/mux"
+)
+
+// OpenChallengeHandler handles HTTP requests for open challenges
+type OpenChallengeHandler struct {
+	service *OpenChallengeService
+}
+
+// NewOpenChallengeHandler creates a new instance of OpenChallengeHandler
+func NewOpenChallengeHandler(service *OpenChallengeService) *OpenChallengeHandler {
+	return &OpenChallengeHandler{
+		service: service,
+	}
+}
+
+// PostOpenChallenge handles POST requests from the signed-in athlete to post an open challenge
+func (h *OpenChallengeHandler) PostOpenChallenge(w http.ResponseWriter, r *http.Request) {
+	var challenge models.OpenChallenge
+	if err := json.NewDecoder(r.Body).Decode(&challenge); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.PostOpenChallenge(challenge, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), openChallengeErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	SendJSON(w, created)
+}
+
+// GetOpenChallenges handles GET requests for challenges that can still be claimed.
+// Optional query parameter: style_id.
+func (h *OpenChallengeHandler) GetOpenChallenges(w http.ResponseWriter, r *http.Request) {
+	styleId := 0
+	if styleIdStr := r.URL.Query().Get("style_id"); styleIdStr != "" {
+		value, err := strconv.Atoi(styleIdStr)
+		if err != nil {
+			SendError(w, "Invalid style_id", http.StatusBadRequest)
+			return
+		}
+		styleId = value
+	}
+
+	challenges, err := h.service.GetOpenChallenges(styleId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, challenges)
+}
+
+// GetOpenChallenge handles GET requests for a single open challenge
+func (h *OpenChallengeHandler) GetOpenChallenge(w http.ResponseWriter, r *http.Request) {
+	challengeId, err := strconv.Atoi(mux.Vars(r)["challenge_id"])
+	if err != nil {
+		SendError(w, "Invalid challenge_id", http.StatusBadRequest)
+		return
+	}
+
+	challenge, err := h.service.GetOpenChallenge(challengeId)
+	if err != nil {
+		SendError(w, err.Error(), openChallengeErrorStatus(err))
+		return
+	}
+	SendJSON(w, challenge)
+}
+
+// ClaimOpenChallenge handles POST requests from the signed-in athlete to claim an open challenge
+func (h *OpenChallengeHandler) ClaimOpenChallenge(w http.ResponseWriter, r *http.Request) {
+	challengeId, err := strconv.Atoi(mux.Vars(r)["challenge_id"])
+	if err != nil {
+		SendError(w, "Invalid challenge_id", http.StatusBadRequest)
+		return
+	}
+
+	boutId, err := h.service.ClaimOpenChallenge(challengeId, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), openChallengeErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	SendJSON(w, map[string]interface{}{
+		"challengeId": challengeId,
+		"boutId":      boutId,
+	})
+}
+
+// CancelOpenChallenge handles PUT requests from the challenger to withdraw an open challenge
+func (h *OpenChallengeHandler) CancelOpenChallenge(w http.ResponseWriter, r *http.Request) {
+	challengeId, err := strconv.Atoi(mux.Vars(r)["challenge_id"])
+	if err != nil {
+		SendError(w, "Invalid challenge_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CancelOpenChallenge(challengeId, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), openChallengeErrorStatus(err))
+		return
+	}
+	SendJSON(w, challengeId)
+}
+
+// openChallengeErrorStatus maps an open challenge error to an HTTP status code
+func openChallengeErrorStatus(err error) int {
+	var eligibilityErr *ClaimEligibilityError
+	var conflictErr *ScheduleConflictError
+	var rematchErr *RematchLimitError
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.As(err, &eligibilityErr), errors.Is(err, ErrNotChallengeOwner):
+		return http.StatusForbidden
+	case errors.Is(err, ErrOpenChallengeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrOpenChallengeUnavailable), errors.As(err, &conflictErr), errors.As(err, &rematchErr):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
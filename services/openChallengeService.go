@@ -0,0 +1,235 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// ErrOpenChallengeNotFound is returned when there is no open challenge with the given ID
+var ErrOpenChallengeNotFound = errors.New("open challenge not found")
+
+// ErrNotChallengeOwner is returned when someone other than the challenger tries to cancel an open challenge
+var ErrNotChallengeOwner = errors.New("only the challenger can cancel an open challenge")
+
+// ClaimEligibilityError is returned when an athlete does not meet an open challenge's requirements
+type ClaimEligibilityError struct {
+	AthleteId   int
+	ChallengeId int
+	Reason      string
+}
+
+func (e *ClaimEligibilityError) Error() string {
+	return fmt.Sprintf("athlete %d cannot claim open challenge %d: %s", e.AthleteId, e.ChallengeId, e.Reason)
+}
+
+// OpenChallengeService posts open challenges and matches them with the first eligible athlete to claim one
+type OpenChallengeService struct {
+	repo                *repositories.OpenChallengeRepository
+	boutRepo            *repositories.BoutRepository
+	athleteScoreService *AthleteScoreService
+}
+
+// NewOpenChallengeService creates a new instance of OpenChallengeService
+func NewOpenChallengeService(repo *repositories.OpenChallengeRepository, boutRepo *repositories.BoutRepository, athleteScoreService *AthleteScoreService) *OpenChallengeService {
+	return &OpenChallengeService{
+		repo:                repo,
+		boutRepo:            boutRepo,
+		athleteScoreService: athleteScoreService,
+	}
+}
+
+// PostOpenChallenge posts an open challenge with the signed-in athlete as challenger
+func (s *OpenChallengeService) PostOpenChallenge(challenge models.OpenChallenge, actorId int) (models.OpenChallenge, error) {
+	if actorId == 0 {
+		return models.OpenChallenge{}, ErrUnauthenticated
+	}
+	challenge.ChallengerId = actorId
+
+	if err := s.validateOpenChallenge(&challenge); err != nil {
+		return models.OpenChallenge{}, fmt.Errorf("invalid open challenge: %w", err)
+	}
+
+	challengeId, err := s.repo.CreateOpenChallenge(challenge)
+	if err != nil {
+		return models.OpenChallenge{}, fmt.Errorf("failed to post open challenge: %w", err)
+	}
+	return s.GetOpenChallenge(challengeId)
+}
+
+// GetOpenChallenge retrieves an open challenge in any status
+func (s *OpenChallengeService) GetOpenChallenge(challengeId int) (models.OpenChallenge, error) {
+	challenge, err := s.repo.GetOpenChallengeById(challengeId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.OpenChallenge{}, ErrOpenChallengeNotFound
+	}
+	if err != nil {
+		return models.OpenChallenge{}, fmt.Errorf("failed to get open challenge %d: %w", challengeId, err)
+	}
+	return challenge, nil
+}
+
+// GetOpenChallenges lists the challenges that can still be claimed. A styleId of 0 lists every style.
+func (s *OpenChallengeService) GetOpenChallenges(styleId int) ([]models.OpenChallenge, error) {
+	challenges, err := s.repo.GetOpenChallenges(styleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open challenges: %w", err)
+	}
+	if challenges == nil {
+		challenges = []models.OpenChallenge{}
+	}
+	return challenges, nil
+}
+
+// ClaimOpenChallenge makes the signed-in athlete the acceptor of an open challenge and returns the new
+// bout's ID. A claim that breaks the style's rematch limits is rejected or creates an unrated bout,
+// depending on the style's rating config.
+func (s *OpenChallengeService) ClaimOpenChallenge(challengeId int, actorId int) (int, error) {
+	if actorId == 0 {
+		return 0, ErrUnauthenticated
+	}
+
+	challenge, err := s.GetOpenChallenge(challengeId)
+	if err != nil {
+		return 0, err
+	}
+	if challenge.Status != models.OpenChallengeOpen {
+		return 0, repositories.ErrOpenChallengeUnavailable
+	}
+
+	if err := s.checkEligibility(challenge, actorId); err != nil {
+		return 0, err
+	}
+
+	bout := models.Bout{ChallengerId: challenge.ChallengerId, AcceptorId: actorId, StyleId: challenge.StyleId}
+	if err := checkRematchLimits(s.athleteScoreService, &bout); err != nil {
+		return 0, err
+	}
+
+	message := fmt.Sprintf("Athlete %d claimed your open challenge %d", actorId, challengeId)
+	boutId, err := s.repo.ClaimOpenChallenge(challengeId, actorId, bout.Rated, message)
+	if errors.Is(err, repositories.ErrOpenChallengeUnavailable) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim open challenge %d: %w", challengeId, err)
+	}
+	return boutId, nil
+}
+
+// CancelOpenChallenge withdraws one of the signed-in athlete's open challenges
+func (s *OpenChallengeService) CancelOpenChallenge(challengeId int, actorId int) error {
+	if actorId == 0 {
+		return ErrUnauthenticated
+	}
+
+	challenge, err := s.GetOpenChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if challenge.ChallengerId != actorId {
+		return ErrNotChallengeOwner
+	}
+
+	if err := s.repo.CancelOpenChallenge(challengeId, actorId); err != nil {
+		if errors.Is(err, repositories.ErrOpenChallengeUnavailable) {
+			return err
+		}
+		return fmt.Errorf("failed to cancel open challenge %d: %w", challengeId, err)
+	}
+	return nil
+}
+
+// checkEligibility checks an athlete against an open challenge's style, rating window, venue and schedule
+func (s *OpenChallengeService) checkEligibility(challenge models.OpenChallenge, athleteId int) error {
+	ineligible := func(reason string) error {
+		return &ClaimEligibilityError{AthleteId: athleteId, ChallengeId: challenge.ChallengeId, Reason: reason}
+	}
+
+	if athleteId == challenge.ChallengerId {
+		return ineligible("it is their own challenge")
+	}
+	if athleteId == challenge.RefereeId {
+		return ineligible("they are its referee")
+	}
+
+	score, err := s.athleteScoreService.GetAthleteScoreByStyle(athleteId, challenge.StyleId)
+	if err != nil {
+		return ineligible("they are not registered in its style")
+	}
+	if challenge.MinRating != nil && score.Score < *challenge.MinRating {
+		return ineligible(fmt.Sprintf("their rating is below %v", *challenge.MinRating))
+	}
+	if challenge.MaxRating != nil && score.Score > *challenge.MaxRating {
+		return ineligible(fmt.Sprintf("their rating is above %v", *challenge.MaxRating))
+	}
+
+	if challenge.GymId != nil || challenge.Region != nil {
+		trains, err := s.repo.AthleteTrainsAt(athleteId, challenge.GymId, challenge.Region)
+		if err != nil {
+			return fmt.Errorf("failed to check athlete %d's gyms: %w", athleteId, err)
+		}
+		if !trains {
+			return ineligible("they do not train at its gym or in its region")
+		}
+	}
+
+	if challenge.ScheduledAt != nil {
+		start, durationMinutes, err := boutScheduleWindow(*challenge.ScheduledAt, challenge.DurationMinutes)
+		if err != nil {
+			return err
+		}
+		bout := models.Bout{ChallengerId: challenge.ChallengerId, AcceptorId: athleteId, RefereeId: challenge.RefereeId}
+		if err := checkScheduleConflict(s.boutRepo, bout, start, durationMinutes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOpenChallenge validates an open challenge and normalizes its region and schedule
+func (s *OpenChallengeService) validateOpenChallenge(challenge *models.OpenChallenge) error {
+	if challenge.StyleId == 0 {
+		return errors.New("style ID is required")
+	}
+	if challenge.RefereeId == 0 {
+		return errors.New("referee ID is required")
+	}
+	if challenge.RefereeId == challenge.ChallengerId {
+		return errors.New("challenger cannot referee their own challenge")
+	}
+	if challenge.MinRating != nil && challenge.MaxRating != nil && *challenge.MinRating > *challenge.MaxRating {
+		return errors.New("minimum rating cannot be above maximum rating")
+	}
+	if challenge.ExpiresInHours < 0 {
+		return errors.New("expires in hours cannot be negative")
+	}
+	if challenge.GymId != nil && challenge.Region != nil {
+		return errors.New("choose either a gym or a region")
+	}
+	if challenge.Region != nil {
+		region := strings.ToUpper(strings.TrimSpace(*challenge.Region))
+		if len(region) != 2 {
+			return errors.New("region must be a two-letter state code")
+		}
+		challenge.Region = &region
+	}
+
+	bout := models.Bout{
+		ChallengerId:    challenge.ChallengerId,
+		RefereeId:       challenge.RefereeId,
+		ScheduledAt:     challenge.ScheduledAt,
+		DurationMinutes: challenge.DurationMinutes,
+	}
+	if err := prepareBoutSchedule(s.boutRepo, &bout, time.Now()); err != nil {
+		return err
+	}
+	challenge.ScheduledAt = bout.ScheduledAt
+	challenge.DurationMinutes = bout.DurationMinutes
+	return nil
+}
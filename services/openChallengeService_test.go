@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestValidateOpenChallenge(t *testing.T) {
+	gymId := 3
+	region := " ca "
+	badRegion := "California"
+	past := "2020-01-01T10:00:00Z"
+	low, high := 380.0, 420.0
+
+	tests := []struct {
+		name       string
+		challenge  models.OpenChallenge
+		wantRegion string
+		wantErr    bool
+	}{
+		{name: "anyone in the style", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5}},
+		{name: "region is normalized", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, Region: &region}, wantRegion: "CA"},
+		{name: "rating window", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, MinRating: &low, MaxRating: &high}},
+		{name: "missing style", challenge: models.OpenChallenge{ChallengerId: 1, RefereeId: 5}, wantErr: true},
+		{name: "missing referee", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2}, wantErr: true},
+		{name: "challenger as referee", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 1}, wantErr: true},
+		{name: "inverted rating window", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, MinRating: &high, MaxRating: &low}, wantErr: true},
+		{name: "negative expiry", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, ExpiresInHours: -1}, wantErr: true},
+		{name: "gym and region", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, GymId: &gymId, Region: &region}, wantErr: true},
+		{name: "region is not a state code", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, Region: &badRegion}, wantErr: true},
+		{name: "start time in the past", challenge: models.OpenChallenge{ChallengerId: 1, StyleId: 2, RefereeId: 5, ScheduledAt: &past}, wantErr: true},
+	}
+
+	// None of these reach the schedule conflict check, so no repository is needed
+	service := &OpenChallengeService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := tt.challenge
+			err := service.validateOpenChallenge(&challenge)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateOpenChallenge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantRegion != "" && (challenge.Region == nil || *challenge.Region != tt.wantRegion) {
+				t.Errorf("validateOpenChallenge() region = %v, want %q", challenge.Region, tt.wantRegion)
+			}
+			if challenge.DurationMinutes != models.DefaultBoutDurationMinutes {
+				t.Errorf("validateOpenChallenge() duration = %d, want the default %d", challenge.DurationMinutes, models.DefaultBoutDurationMinutes)
+			}
+		})
+	}
+}
+
+func TestCheckEligibilityRejectsChallengeParticipants(t *testing.T) {
+	challenge := models.OpenChallenge{ChallengeId: 4, ChallengerId: 1, StyleId: 2, RefereeId: 5}
+
+	// The challenger and the referee are turned away before their rating is looked up
+	service := &OpenChallengeService{}
+	for _, athleteId := range []int{challenge.ChallengerId, challenge.RefereeId} {
+		err := service.checkEligibility(challenge, athleteId)
+		var eligibilityErr *ClaimEligibilityError
+		if !errors.As(err, &eligibilityErr) {
+			t.Fatalf("checkEligibility() for athlete %d error = %v, want a *ClaimEligibilityError", athleteId, err)
+		}
+		if eligibilityErr.AthleteId != athleteId || eligibilityErr.ChallengeId != challenge.ChallengeId {
+			t.Errorf("checkEligibility() error is for athlete %d and challenge %d, want athlete %d and challenge %d",
+				eligibilityErr.AthleteId, eligibilityErr.ChallengeId, athleteId, challenge.ChallengeId)
+		}
+	}
+}
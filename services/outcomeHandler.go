@@ -1,10 +1,13 @@
 package services
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"ronin/interfaces"
 	"ronin/models"
+	"ronin/repositories"
 
 	"github.com/gorilla/mux"
 )
@@ -45,6 +48,7 @@ func (h *OutcomeHandler) GetOutcome(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(outcome)
 }
 
+// CreateOutcome handles POST requests from an admin to record an outcome without a bout
 func (h *OutcomeHandler) CreateOutcome(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	var outcome models.Outcome
@@ -53,12 +57,13 @@ func (h *OutcomeHandler) CreateOutcome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Create(outcome); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	createdOutcome, err := h.service.Create(outcome, authenticatedAthleteId(r))
+	if err != nil {
+		http.Error(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 
-	json.NewEncoder(w).Encode(outcome)
+	json.NewEncoder(w).Encode(createdOutcome)
 }
 
 func (h *OutcomeHandler) GetOutcomeByBout(w http.ResponseWriter, r *http.Request) {
@@ -75,6 +80,7 @@ func (h *OutcomeHandler) GetOutcomeByBout(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(outcome)
 }
 
+// CreateOutcomeByBout handles POST requests from the bout's referee to record its outcome
 func (h *OutcomeHandler) CreateOutcomeByBout(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	vars := mux.Vars(r)
@@ -86,10 +92,104 @@ func (h *OutcomeHandler) CreateOutcomeByBout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := h.service.CreateForBout(outcome, boutID); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	createdOutcome, err := h.service.CreateForBout(outcome, boutID, authenticatedAthleteId(r))
+	if err != nil {
+		http.Error(w, err.Error(), boutErrorStatus(err))
 		return
 	}
 
-	json.NewEncoder(w).Encode(outcome)
+	json.NewEncoder(w).Encode(createdOutcome)
+}
+
+// VoidOutcome handles POST requests from the bout's referee or an admin to void an outcome recorded in error
+func (h *OutcomeHandler) VoidOutcome(w http.ResponseWriter, r *http.Request) {
+	outcomeID := mux.Vars(r)["outcome_id"]
+
+	var request models.OutcomeVoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Void(outcomeID, request.Reason, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, report)
+}
+
+// GetOutcomeVoid handles GET requests for the audit record of a voided outcome
+func (h *OutcomeHandler) GetOutcomeVoid(w http.ResponseWriter, r *http.Request) {
+	void, err := h.service.GetVoid(mux.Vars(r)["outcome_id"])
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, void)
+}
+
+// ConfirmOutcome handles POST requests from a bout's competitor to confirm its pending outcome
+func (h *OutcomeHandler) ConfirmOutcome(w http.ResponseWriter, r *http.Request) {
+	outcome, err := h.service.Confirm(mux.Vars(r)["outcome_id"], authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, outcome)
+}
+
+// DisputeOutcome handles POST requests from a bout's competitor to dispute its pending outcome
+func (h *OutcomeHandler) DisputeOutcome(w http.ResponseWriter, r *http.Request) {
+	var request models.OutcomeDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	outcome, err := h.service.Dispute(mux.Vars(r)["outcome_id"], request.Reason, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, outcome)
+}
+
+// ResolveOutcome handles POST requests from an admin or gym owner to resolve a disputed outcome
+func (h *OutcomeHandler) ResolveOutcome(w http.ResponseWriter, r *http.Request) {
+	var request models.OutcomeResolutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resolution, err := h.service.Resolve(mux.Vars(r)["outcome_id"], request, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, resolution)
+}
+
+// GetOutcomeReview handles GET requests for an outcome with its confirmations, disputes and resolution
+func (h *OutcomeHandler) GetOutcomeReview(w http.ResponseWriter, r *http.Request) {
+	review, err := h.service.GetReview(mux.Vars(r)["outcome_id"])
+	if err != nil {
+		SendError(w, err.Error(), outcomeErrorStatus(err))
+		return
+	}
+	SendJSON(w, review)
+}
+
+// outcomeErrorStatus maps an outcome service error to an HTTP status code
+func outcomeErrorStatus(err error) int {
+	var statusErr *OutcomeStatusError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrOutcomeAlreadyVoided), errors.Is(err, repositories.ErrOutcomeStatusChanged),
+		errors.Is(err, repositories.ErrOutcomeAlreadyAnswered), errors.As(err, &statusErr):
+		return http.StatusConflict
+	}
+	return boutErrorStatus(err)
 }
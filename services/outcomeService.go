@@ -1,31 +1,53 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"ronin/interfaces"
 	"ronin/models"
 	"ronin/repositories"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // outcomeService implements the interfaces.OutcomeService interface
 type outcomeService struct {
-	outcomeRepo         *repositories.OutcomeRepository
-	athleteScoreService *AthleteScoreService
-	boutRepository      *repositories.BoutRepository
+	outcomeRepo          *repositories.OutcomeRepository
+	athleteScoreService  *AthleteScoreService
+	overallRatingService *OverallRatingService
+	boutRepository       *repositories.BoutRepository
+	unitOfWork           *repositories.UnitOfWork
+	ratingReplayService  *RatingReplayService
+	gymRepo              *repositories.GymRepository
+	notificationRepo     *repositories.NotificationRepository
+	confirmationWindow   time.Duration
 }
 
 // NewOutcomeService creates a new instance of OutcomeService with all required dependencies
 func NewOutcomeService(
 	outcomeRepo *repositories.OutcomeRepository,
 	athleteScoreService *AthleteScoreService,
+	overallRatingService *OverallRatingService,
 	boutRepo *repositories.BoutRepository,
+	unitOfWork *repositories.UnitOfWork,
+	ratingReplayService *RatingReplayService,
+	gymRepo *repositories.GymRepository,
+	notificationRepo *repositories.NotificationRepository,
+	confirmationWindow time.Duration,
 ) interfaces.OutcomeService {
 	return &outcomeService{
-		outcomeRepo:         outcomeRepo,
-		athleteScoreService: athleteScoreService,
-		boutRepository:      boutRepo,
+		outcomeRepo:          outcomeRepo,
+		athleteScoreService:  athleteScoreService,
+		overallRatingService: overallRatingService,
+		boutRepository:       boutRepo,
+		unitOfWork:           unitOfWork,
+		ratingReplayService:  ratingReplayService,
+		gymRepo:              gymRepo,
+		notificationRepo:     notificationRepo,
+		confirmationWindow:   confirmationWindow,
 	}
 }
 
@@ -48,99 +70,183 @@ func (s *outcomeService) GetByID(id string) (models.Outcome, error) {
 	if err != nil {
 		return models.Outcome{}, fmt.Errorf("failed to get outcome by ID %s: %w", id, err)
 	}
-	return outcome, nil
+	return s.withOutcomeDetails(outcome)
 }
 
-func (s *outcomeService) Create(outcome models.Outcome) error {
-	if err := s.validateOutcome(outcome); err != nil {
-		return fmt.Errorf("invalid outcome: %w", err)
+// Create records a confirmed outcome that has no bout. Only an admin can record one, since it skips
+// the bout's referee and the confirmation window.
+func (s *outcomeService) Create(outcome models.Outcome, actorID int) (models.Outcome, error) {
+	if err := requireAdmin(actorID); err != nil {
+		return models.Outcome{}, err
 	}
-
-	createdOutcome, err := s.outcomeRepo.CreateOutcome(outcome)
-	if err != nil {
-		return fmt.Errorf("failed to create outcome: %w", err)
-	}
-
-	if err := s.updateAthleteScores(createdOutcome); err != nil {
-		return fmt.Errorf("failed to update athlete scores: %w", err)
+	if err := s.validateOutcome(outcome); err != nil {
+		return models.Outcome{}, fmt.Errorf("invalid outcome: %w", err)
 	}
 
-	return nil
+	outcome.Rated = true
+	outcome.ConfirmationStatus = models.OutcomeStatusConfirmed
+	return s.recordOutcome(withOutcomeDefaults(outcome))
 }
 
-func (s *outcomeService) CreateForBout(outcome models.Outcome, boutID string) error {
+// CreateForBout records the outcome of a bout, completes the bout and asks both competitors to confirm
+// it. Only the bout's referee can record it. The outcome stays pending, off both athletes' records
+// and ratings, until it is confirmed.
+func (s *outcomeService) CreateForBout(outcome models.Outcome, boutID string, actorID int) (models.Outcome, error) {
 	log.Printf("Starting CreateForBout for bout %s with outcome: %+v", boutID, outcome)
 
 	if err := s.validateOutcome(outcome); err != nil {
 		log.Printf("Validation failed for outcome: %v", err)
-		return fmt.Errorf("invalid outcome: %w", err)
+		return models.Outcome{}, fmt.Errorf("invalid outcome: %w", err)
 	}
 
 	if boutID == "" {
 		log.Println("Bout ID is empty")
-		return errors.New("bout ID cannot be empty")
+		return models.Outcome{}, errors.New("bout ID cannot be empty")
 	}
 
 	// Check if bout exists and is in correct state
 	bout, err := s.boutRepository.GetBoutById(boutID)
 	if err != nil {
 		log.Printf("Error getting bout %s: %v", boutID, err)
-		return fmt.Errorf("failed to get bout: %w", err)
+		return models.Outcome{}, fmt.Errorf("failed to get bout: %w", err)
 	}
 	log.Printf("Found bout: %+v", bout)
 
-	if !bout.Accepted {
-		log.Printf("Bout %s is not accepted", boutID)
-		return fmt.Errorf("bout %s is not accepted", boutID)
+	if err := requireBoutRole(bout, actorID, BoutRoleReferee); err != nil {
+		log.Printf("Athlete %d cannot record the outcome of bout %s: %v", actorID, boutID, err)
+		return models.Outcome{}, err
 	}
 
-	if bout.Completed {
-		log.Printf("Bout %s is already completed", boutID)
-		return fmt.Errorf("bout %s is already completed", boutID)
+	if !canTransitionBout(bout.Status, models.BoutStatusCompleted) {
+		log.Printf("Bout %s cannot be completed from status %s", boutID, bout.Status)
+		return models.Outcome{}, &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: models.BoutStatusCompleted}
 	}
 
 	// Check if outcome already exists
 	existingOutcome, err := s.outcomeRepo.GetOutcomeByBoutId(boutID)
 	if err == nil {
 		log.Printf("Outcome already exists for bout %s: %+v", boutID, existingOutcome)
-		return fmt.Errorf("outcome already exists for bout %s", boutID)
+		return models.Outcome{}, fmt.Errorf("outcome already exists for bout %s", boutID)
 	}
 
-	// Create the outcome first
-	createdOutcome, err := s.outcomeRepo.CreateOutcome(outcome)
+	outcome.BoutId = bout.BoutId
+	outcome.Rated = bout.Rated
+	createdOutcome, err := s.recordPendingOutcome(withOutcomeDefaults(outcome), bout)
 	if err != nil {
-		log.Printf("Failed to create outcome for bout %s: %v", boutID, err)
-		return fmt.Errorf("failed to create outcome: %w", err)
+		log.Printf("Failed to record outcome for bout %s: %v", boutID, err)
+		return models.Outcome{}, err
 	}
-	log.Printf("Successfully created outcome with ID: %d", createdOutcome.OutcomeId)
+	log.Printf("Successfully created outcome with ID: %d and completed bout %s, awaiting confirmation until %s",
+		createdOutcome.OutcomeId, boutID, *createdOutcome.ConfirmByDate)
+	return createdOutcome, nil
+}
 
-	// Now update the athlete scores with the created outcome
-	if err := s.updateAthleteScores(createdOutcome); err != nil {
-		log.Printf("Failed to update athlete scores: %v", err)
-		return fmt.Errorf("failed to update athlete scores: %w", err)
+func (s *outcomeService) GetByBoutID(boutID string) (models.Outcome, error) {
+	if boutID == "" {
+		return models.Outcome{}, errors.New("bout ID cannot be empty")
 	}
 
-	// Complete the bout
-	if err := s.boutRepository.CompleteBoutByBoutId(boutID); err != nil {
-		log.Printf("Failed to complete bout %s: %v", boutID, err)
-		return fmt.Errorf("failed to complete bout: %w", err)
+	outcome, err := s.outcomeRepo.GetOutcomeByBoutId(boutID)
+	if err != nil {
+		return models.Outcome{}, fmt.Errorf("failed to get outcome for bout %s: %w", boutID, err)
 	}
-	log.Printf("Successfully completed bout %s", boutID)
+	return s.withOutcomeDetails(outcome)
+}
 
-	log.Printf("Successfully created outcome for bout %s", boutID)
-	return nil
+// maxVoidReasonLength matches outcome_void.reason
+const maxVoidReasonLength = 500
+
+// Void voids an outcome recorded in error. Only the bout's referee or an admin can void it. In one
+// transaction the outcome is marked voided and audited, both athletes' records are reversed, the bout
+// moves to voided and the style's ratings are rebuilt without the outcome, re-rating every later one.
+func (s *outcomeService) Void(outcomeID string, reason string, actorID int) (models.OutcomeVoidReport, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.OutcomeVoidReport{}, errors.New("a reason is required to void an outcome")
+	}
+	if len(reason) > maxVoidReasonLength {
+		return models.OutcomeVoidReport{}, fmt.Errorf("reason cannot be longer than %d characters", maxVoidReasonLength)
+	}
+
+	outcome, err := s.outcomeRepo.GetOutcomeById(outcomeID)
+	if err != nil {
+		return models.OutcomeVoidReport{}, fmt.Errorf("failed to get outcome %s: %w", outcomeID, err)
+	}
+	if outcome.VoidedDate != nil {
+		return models.OutcomeVoidReport{}, repositories.ErrOutcomeAlreadyVoided
+	}
+
+	var bout *models.Bout
+	if outcome.BoutId != 0 {
+		found, err := s.boutRepository.GetBoutById(strconv.Itoa(outcome.BoutId))
+		if err != nil {
+			return models.OutcomeVoidReport{}, fmt.Errorf("failed to get bout: %w", err)
+		}
+		bout = &found
+	}
+
+	if err := requireBoutRoleOrAdmin(bout, actorID, BoutRoleReferee); err != nil {
+		return models.OutcomeVoidReport{}, err
+	}
+	if bout != nil && !canTransitionBout(bout.Status, models.BoutStatusVoided) {
+		return models.OutcomeVoidReport{}, &BoutTransitionError{BoutId: bout.BoutId, From: bout.Status, To: models.BoutStatusVoided}
+	}
+
+	report := models.OutcomeVoidReport{Changes: []models.RatingReplayChange{}}
+	err = s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		// The style is replayed below, so nothing else may rate it until the void commits
+		if err := s.athleteScoreService.LockStyleRatings(tx, outcome.StyleId); err != nil {
+			return err
+		}
+
+		void, err := s.outcomeRepo.VoidOutcome(tx, outcome, actorID, reason)
+		if err != nil {
+			return err
+		}
+		report.Void = void
+
+		if bout != nil {
+			if err := s.boutRepository.VoidBout(tx, bout.BoutId, bout.Status, actorID); err != nil {
+				return fmt.Errorf("failed to void bout: %w", err)
+			}
+		}
+
+		replayed, changes, err := s.ratingReplayService.ReplayStyleWithout(tx, outcome.StyleId, outcome.OutcomeId)
+		if err != nil {
+			return err
+		}
+		report.OutcomesReplayed = replayed
+		report.Changes = append(report.Changes, changes...)
+		return nil
+	})
+	if bout != nil && errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return models.OutcomeVoidReport{}, staleBoutError(s.boutRepository, *bout, models.BoutStatusVoided)
+	}
+	if err != nil {
+		return models.OutcomeVoidReport{}, err
+	}
+	log.Printf("Athlete %d voided outcome %d and re-rated %d outcomes in style %d: %s",
+		actorID, outcome.OutcomeId, report.OutcomesReplayed, outcome.StyleId, reason)
+
+	// The style's scores are already rebuilt, so a stale overall rating is logged rather than failing the void
+	if err := s.overallRatingService.RecomputeAll(); err != nil {
+		log.Printf("Failed to recompute overall ratings after voiding outcome %d: %v", outcome.OutcomeId, err)
+	}
+	return report, nil
 }
 
-func (s *outcomeService) GetByBoutID(boutID string) (models.Outcome, error) {
-	if boutID == "" {
-		return models.Outcome{}, errors.New("bout ID cannot be empty")
+// GetVoid returns the audit record of a voided outcome
+func (s *outcomeService) GetVoid(outcomeID string) (models.OutcomeVoid, error) {
+	id, err := strconv.Atoi(outcomeID)
+	if err != nil {
+		return models.OutcomeVoid{}, fmt.Errorf("invalid outcome ID %q", outcomeID)
 	}
 
-	outcome, err := s.outcomeRepo.GetOutcomeById(boutID) // Using GetOutcomeById as GetOutcomeByBoutId doesn't exist
+	void, err := s.outcomeRepo.GetOutcomeVoid(id)
 	if err != nil {
-		return models.Outcome{}, fmt.Errorf("failed to get outcome for bout %s: %w", boutID, err)
+		return models.OutcomeVoid{}, fmt.Errorf("failed to get void of outcome %d: %w", id, err)
 	}
-	return outcome, nil
+	return void, nil
 }
 
 func (s *outcomeService) validateOutcome(outcome models.Outcome) error {
@@ -153,40 +259,558 @@ func (s *outcomeService) validateOutcome(outcome models.Outcome) error {
 	if outcome.StyleId == 0 {
 		return errors.New("style ID is required")
 	}
+	if outcome.FinishMethod != "" && !isFinishMethod(outcome.FinishMethod) {
+		return fmt.Errorf("unknown finish method %q", outcome.FinishMethod)
+	}
+	if outcome.ScoreMargin < 0 {
+		return errors.New("score margin cannot be negative")
+	}
+	return validateOutcomeDetails(outcome)
+}
+
+// maxSubmissionTypeLength matches outcome_finish.submission_type
+const maxSubmissionTypeLength = 50
+
+// validateOutcomeDetails checks an outcome's scorecard and finish against its result. Every scorecard
+// line must belong to the winner or loser, and each athlete is scored at most once per round.
+func validateOutcomeDetails(outcome models.Outcome) error {
+	lastRound := 0
+	scored := make(map[[2]int]bool)
+	for _, score := range outcome.Scorecard {
+		if score.AthleteId != outcome.WinnerId && score.AthleteId != outcome.LoserId {
+			return fmt.Errorf("scorecard athlete %d is not the winner or loser", score.AthleteId)
+		}
+		if score.RoundNumber < 1 {
+			return errors.New("scorecard round numbers start at 1")
+		}
+		if score.Points < 0 || score.Advantages < 0 || score.Penalties < 0 {
+			return errors.New("scorecard points, advantages and penalties cannot be negative")
+		}
+		line := [2]int{score.RoundNumber, score.AthleteId}
+		if scored[line] {
+			return fmt.Errorf("athlete %d is scored twice in round %d", score.AthleteId, score.RoundNumber)
+		}
+		scored[line] = true
+		if score.RoundNumber > lastRound {
+			lastRound = score.RoundNumber
+		}
+	}
+
+	if outcome.Finish == nil {
+		return nil
+	}
+	finish := outcome.Finish
+	if finish.SubmissionType != nil {
+		if outcome.FinishMethod != models.FinishSubmission {
+			return errors.New("a submission type can only be given for a submission finish")
+		}
+		if strings.TrimSpace(*finish.SubmissionType) == "" || len(*finish.SubmissionType) > maxSubmissionTypeLength {
+			return fmt.Errorf("submission type must be between 1 and %d characters", maxSubmissionTypeLength)
+		}
+	}
+	if finish.FinishRound != nil {
+		if *finish.FinishRound < 1 {
+			return errors.New("finish round must be at least 1")
+		}
+		if lastRound > 0 && *finish.FinishRound > lastRound {
+			return fmt.Errorf("finish round %d is after the last scored round %d", *finish.FinishRound, lastRound)
+		}
+	}
+	if finish.FinishTimeSeconds != nil && *finish.FinishTimeSeconds < 0 {
+		return errors.New("finish time cannot be negative")
+	}
 	return nil
 }
 
-func (s *outcomeService) createOutcomeForBout(outcome models.Outcome, boutID string) error {
-	var err error
-	if outcome.IsDraw {
-		err = s.outcomeRepo.CreateOutcomeByBoutIdDraw(&outcome, boutID)
-	} else {
-		err = s.outcomeRepo.CreateOutcomeByBoutIdNotDraw(&outcome, boutID)
+// withOutcomeDetails loads an outcome's finish, when it has one, and its scorecard
+func (s *outcomeService) withOutcomeDetails(outcome models.Outcome) (models.Outcome, error) {
+	finish, err := s.outcomeRepo.GetOutcomeFinish(outcome.OutcomeId)
+	if err == nil {
+		outcome.Finish = &finish
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return models.Outcome{}, fmt.Errorf("failed to get finish of outcome %d: %w", outcome.OutcomeId, err)
+	}
+
+	scorecard, err := s.outcomeRepo.GetOutcomeScorecard(outcome.OutcomeId)
+	if err != nil {
+		return models.Outcome{}, fmt.Errorf("failed to get scorecard of outcome %d: %w", outcome.OutcomeId, err)
+	}
+	outcome.Scorecard = scorecard
+	return outcome, nil
+}
+
+// withOutcomeDefaults fills in the finish method for outcomes recorded without one
+func withOutcomeDefaults(outcome models.Outcome) models.Outcome {
+	if outcome.FinishMethod == "" {
+		outcome.FinishMethod = models.FinishDecision
+	}
+	return outcome
+}
+
+// recordOutcome rates a confirmed outcome, stores it, counts it in both athletes' records and saves
+// their new ratings in one transaction. Nothing is kept if any step fails. Overall ratings are
+// recomputed after the commit.
+func (s *outcomeService) recordOutcome(outcome models.Outcome) (models.Outcome, error) {
+	var result models.RatingResult
+	err := s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		rated, err := s.athleteScoreService.RateOutcome(tx, outcome)
+		if err != nil {
+			return fmt.Errorf("failed to rate outcome: %w", err)
+		}
+		result = rated
+
+		created, err := s.outcomeRepo.InsertOutcome(tx, outcome)
+		if err != nil {
+			return fmt.Errorf("failed to create outcome: %w", err)
+		}
+		outcome = created
+
+		if err := s.athleteScoreService.SaveRatingResult(tx, result, outcome.OutcomeId); err != nil {
+			return fmt.Errorf("failed to update athlete scores: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	s.recomputeOverall(outcome, result)
+	return outcome, nil
+}
+
+// recordPendingOutcome stores a bout's outcome as pending, completes the bout and notifies both
+// competitors in one transaction. Ratings and records are left alone until the outcome is confirmed.
+func (s *outcomeService) recordPendingOutcome(outcome models.Outcome, bout models.Bout) (models.Outcome, error) {
+	confirmBy := time.Now().Add(s.confirmationWindow).UTC().Format(time.RFC3339)
+	outcome.ConfirmationStatus = models.OutcomeStatusPending
+	outcome.ConfirmByDate = &confirmBy
+
+	err := s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		created, err := s.outcomeRepo.InsertOutcome(tx, outcome)
+		if err != nil {
+			return fmt.Errorf("failed to create outcome: %w", err)
+		}
+		outcome = created
+
+		if err := s.boutRepository.CompleteBout(tx, bout.BoutId, bout.Status, bout.RefereeId); err != nil {
+			return fmt.Errorf("failed to complete bout: %w", err)
+		}
+
+		message := fmt.Sprintf("The outcome of bout %d was recorded. Confirm or dispute it by %s", bout.BoutId, confirmBy)
+		return s.notify(tx, bout, models.NotificationOutcomeRecorded, message, bout.ChallengerId, bout.AcceptorId)
+	})
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return models.Outcome{}, staleBoutError(s.boutRepository, bout, models.BoutStatusCompleted)
+	}
+	if err != nil {
+		return models.Outcome{}, err
+	}
+	return outcome, nil
+}
+
+// Confirm records a competitor's confirmation of a pending outcome. Once both competitors have
+// confirmed, the outcome is counted in their records and rated.
+func (s *outcomeService) Confirm(outcomeID string, actorID int) (models.Outcome, error) {
+	outcome, bout, err := s.getPendingOutcome(outcomeID, actorID, "confirmed")
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	var result models.RatingResult
+	confirmed := false
+	err = s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		if err := s.athleteScoreService.LockStyleRatings(tx, outcome.StyleId); err != nil {
+			return err
+		}
+
+		// Locking the outcome makes the other competitor's answer wait, so exactly one of the two
+		// confirmations sees the other and confirms the outcome
+		responses, err := s.outcomeRepo.LockPendingOutcome(tx, outcome.OutcomeId)
+		if err != nil {
+			return err
+		}
+		otherConfirmed := false
+		for _, response := range responses {
+			if response.AthleteId != actorID && response.Response == models.OutcomeResponseConfirm {
+				otherConfirmed = true
+			}
+		}
+
+		response := models.OutcomeResponse{OutcomeId: outcome.OutcomeId, AthleteId: actorID, Response: models.OutcomeResponseConfirm}
+		if err := s.outcomeRepo.InsertOutcomeResponse(tx, response); err != nil {
+			return err
+		}
+		if !otherConfirmed {
+			return nil
+		}
+
+		outcome, result, err = s.confirmOutcomeTx(tx, outcome, bout, models.OutcomeStatusPending, actorID)
+		confirmed = err == nil
+		return err
+	})
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return models.Outcome{}, staleBoutError(s.boutRepository, bout, models.BoutStatusCompleted)
+	}
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	if confirmed {
+		s.recomputeOverall(outcome, result)
 	}
+	return outcome, nil
+}
+
+// maxDisputeReasonLength matches outcome_response.reason and outcome_resolution.reason
+const maxDisputeReasonLength = 500
 
+// Dispute records a competitor's dispute of a pending outcome. The outcome's rating changes are held
+// and its bout is marked disputed until an admin or the owner of the bout's gym resolves it.
+func (s *outcomeService) Dispute(outcomeID string, reason string, actorID int) (models.Outcome, error) {
+	reason, err := requireOutcomeReason(reason, "dispute")
 	if err != nil {
-		return fmt.Errorf("failed to create outcome for bout %s: %w", boutID, err)
+		return models.Outcome{}, err
 	}
 
+	outcome, bout, err := s.getPendingOutcome(outcomeID, actorID, "disputed")
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	err = s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		err := s.outcomeRepo.InsertOutcomeResponse(tx, models.OutcomeResponse{
+			OutcomeId: outcome.OutcomeId,
+			AthleteId: actorID,
+			Response:  models.OutcomeResponseDispute,
+			Reason:    &reason,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.outcomeRepo.DisputeOutcome(tx, outcome.OutcomeId); err != nil {
+			return err
+		}
+		if err := s.boutRepository.DisputeBout(tx, bout.BoutId, actorID); err != nil {
+			return fmt.Errorf("failed to dispute bout: %w", err)
+		}
+
+		opponent := bout.ChallengerId
+		if actorID == bout.ChallengerId {
+			opponent = bout.AcceptorId
+		}
+		message := fmt.Sprintf("Athlete %d disputed the outcome of bout %d: %s", actorID, bout.BoutId, reason)
+		return s.notify(tx, bout, models.NotificationOutcomeDisputed, message, opponent, bout.RefereeId)
+	})
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return models.Outcome{}, staleBoutError(s.boutRepository, bout, models.BoutStatusDisputed)
+	}
+	if err != nil {
+		return models.Outcome{}, err
+	}
+	log.Printf("Athlete %d disputed outcome %d: %s", actorID, outcome.OutcomeId, reason)
+
+	outcome.ConfirmationStatus = models.OutcomeStatusDisputed
+	return outcome, nil
+}
+
+// Resolve settles a disputed outcome. Only an admin or the owner of the gym the bout was held at can
+// resolve it. Upholding confirms the outcome as recorded, overturning confirms it with the corrected
+// result, and voiding voids the outcome and its bout. The resolution is audited in the same transaction.
+func (s *outcomeService) Resolve(outcomeID string, request models.OutcomeResolutionRequest, actorID int) (models.OutcomeResolution, error) {
+	reason, err := requireOutcomeReason(request.Reason, "resolve")
+	if err != nil {
+		return models.OutcomeResolution{}, err
+	}
+
+	outcome, err := s.outcomeRepo.GetOutcomeById(outcomeID)
+	if err != nil {
+		return models.OutcomeResolution{}, fmt.Errorf("failed to get outcome %s: %w", outcomeID, err)
+	}
+	if outcome.VoidedDate != nil {
+		return models.OutcomeResolution{}, repositories.ErrOutcomeAlreadyVoided
+	}
+	if outcome.ConfirmationStatus != models.OutcomeStatusDisputed {
+		return models.OutcomeResolution{}, &OutcomeStatusError{OutcomeId: outcome.OutcomeId, Status: outcome.ConfirmationStatus, Action: "resolved"}
+	}
+
+	bout, err := s.boutRepository.GetBoutById(strconv.Itoa(outcome.BoutId))
+	if err != nil {
+		return models.OutcomeResolution{}, fmt.Errorf("failed to get bout: %w", err)
+	}
+	gymOwnerId, err := s.gymOwnerId(bout)
+	if err != nil {
+		return models.OutcomeResolution{}, err
+	}
+	if err := requireAdminOrGymOwner(bout, gymOwnerId, actorID); err != nil {
+		return models.OutcomeResolution{}, err
+	}
+
+	resolution := models.OutcomeResolution{OutcomeId: outcome.OutcomeId, ResolvedBy: actorID, Action: request.Action, Reason: reason}
+	audit := func(tx *repositories.Tx) error {
+		inserted, err := s.outcomeRepo.InsertOutcomeResolution(tx, resolution)
+		if err != nil {
+			return fmt.Errorf("failed to audit resolution: %w", err)
+		}
+		resolution = inserted
+
+		message := fmt.Sprintf("The disputed outcome of bout %d was resolved (%s): %s", bout.BoutId, request.Action, reason)
+		return s.notify(tx, bout, models.NotificationOutcomeResolved, message, bout.ChallengerId, bout.AcceptorId, bout.RefereeId)
+	}
+
+	switch request.Action {
+	case models.OutcomeResolutionUphold:
+		_, err = s.confirmOutcome(outcome, bout, models.OutcomeStatusDisputed, actorID, audit)
+	case models.OutcomeResolutionOverturn:
+		corrected, correctErr := overturnedOutcome(outcome, bout, request)
+		if correctErr != nil {
+			return models.OutcomeResolution{}, correctErr
+		}
+		_, err = s.confirmOutcome(corrected, bout, models.OutcomeStatusDisputed, actorID, audit)
+	case models.OutcomeResolutionVoid:
+		err = s.unitOfWork.Run(func(tx *repositories.Tx) error {
+			if _, err := s.outcomeRepo.VoidOutcome(tx, outcome, actorID, reason); err != nil {
+				return err
+			}
+			if err := s.boutRepository.VoidBout(tx, bout.BoutId, models.BoutStatusDisputed, actorID); err != nil {
+				return fmt.Errorf("failed to void bout: %w", err)
+			}
+			return audit(tx)
+		})
+		if errors.Is(err, repositories.ErrBoutStatusChanged) {
+			return models.OutcomeResolution{}, staleBoutError(s.boutRepository, bout, models.BoutStatusVoided)
+		}
+	default:
+		return models.OutcomeResolution{}, fmt.Errorf("unknown resolution action %q", request.Action)
+	}
+	if err != nil {
+		return models.OutcomeResolution{}, err
+	}
+	log.Printf("Athlete %d resolved disputed outcome %d (%s): %s", actorID, outcome.OutcomeId, request.Action, reason)
+	return resolution, nil
+}
+
+// GetReview returns an outcome with every competitor's answer to it and, once a dispute is settled, its resolution
+func (s *outcomeService) GetReview(outcomeID string) (models.OutcomeReview, error) {
+	outcome, err := s.outcomeRepo.GetOutcomeById(outcomeID)
+	if err != nil {
+		return models.OutcomeReview{}, fmt.Errorf("failed to get outcome %s: %w", outcomeID, err)
+	}
+
+	responses, err := s.outcomeRepo.GetOutcomeResponses(outcome.OutcomeId)
+	if err != nil {
+		return models.OutcomeReview{}, fmt.Errorf("failed to get responses to outcome %d: %w", outcome.OutcomeId, err)
+	}
+	review := models.OutcomeReview{Outcome: outcome, Responses: responses}
+
+	resolution, err := s.outcomeRepo.GetOutcomeResolution(outcome.OutcomeId)
+	if err == nil {
+		review.Resolution = &resolution
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return models.OutcomeReview{}, fmt.Errorf("failed to get resolution of outcome %d: %w", outcome.OutcomeId, err)
+	}
+	return review, nil
+}
+
+// ConfirmOverdue confirms every pending outcome whose confirmation window ended without a dispute.
+// Outcomes that are confirmed, disputed or voided while the job runs are left alone.
+func (s *outcomeService) ConfirmOverdue() error {
+	outcomes, err := s.outcomeRepo.GetOverdueOutcomes()
+	if err != nil {
+		return fmt.Errorf("failed to get overdue outcomes: %w", err)
+	}
+
+	confirmed := 0
+	for _, outcome := range outcomes {
+		bout, err := s.boutRepository.GetBoutById(strconv.Itoa(outcome.BoutId))
+		if err != nil {
+			return fmt.Errorf("failed to get bout of outcome %d: %w", outcome.OutcomeId, err)
+		}
+
+		_, err = s.confirmOutcome(outcome, bout, models.OutcomeStatusPending, 0, nil)
+		if errors.Is(err, repositories.ErrOutcomeStatusChanged) {
+			log.Printf("Outcome %d was answered before it could be confirmed automatically", outcome.OutcomeId)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to confirm outcome %d: %w", outcome.OutcomeId, err)
+		}
+		confirmed++
+	}
+
+	if confirmed > 0 {
+		log.Printf("Confirmed %d outcomes nobody answered in time", confirmed)
+	}
 	return nil
 }
 
-// updateAthleteScores updates the scores for both athletes involved in the outcome
-func (s *outcomeService) updateAthleteScores(outcome models.Outcome) error {
-	loserScore, err := s.athleteScoreService.GetAthleteScoreByStyle(outcome.LoserId, outcome.StyleId)
+// confirmOutcome confirms an outcome from fromStatus, rates it, counts it in both athletes' records,
+// saves their new ratings, completes its bout if it was disputed and runs extra, when given, all in
+// one transaction. Overall ratings are recomputed after the commit.
+func (s *outcomeService) confirmOutcome(outcome models.Outcome, bout models.Bout, fromStatus string, actorID int,
+	extra func(tx *repositories.Tx) error) (models.Outcome, error) {
+	var result models.RatingResult
+	err := s.unitOfWork.Run(func(tx *repositories.Tx) error {
+		if err := s.athleteScoreService.LockStyleRatings(tx, outcome.StyleId); err != nil {
+			return err
+		}
+
+		var err error
+		outcome, result, err = s.confirmOutcomeTx(tx, outcome, bout, fromStatus, actorID)
+		if err != nil {
+			return err
+		}
+
+		if extra != nil {
+			return extra(tx)
+		}
+		return nil
+	})
+	if errors.Is(err, repositories.ErrBoutStatusChanged) {
+		return models.Outcome{}, staleBoutError(s.boutRepository, bout, models.BoutStatusCompleted)
+	}
+	if err != nil {
+		return models.Outcome{}, err
+	}
+
+	s.recomputeOverall(outcome, result)
+	return outcome, nil
+}
+
+// confirmOutcomeTx confirms an outcome from fromStatus, rates it, counts it in both athletes'
+// records, saves their new ratings and completes its bout if it was disputed, inside a shared
+// transaction that already holds the style's rating lock
+func (s *outcomeService) confirmOutcomeTx(tx *repositories.Tx, outcome models.Outcome, bout models.Bout, fromStatus string,
+	actorID int) (models.Outcome, models.RatingResult, error) {
+	confirmed, err := s.outcomeRepo.ConfirmOutcome(tx, outcome, fromStatus)
+	if err != nil {
+		return models.Outcome{}, models.RatingResult{}, err
+	}
+
+	result, err := s.athleteScoreService.RateOutcome(tx, confirmed)
+	if err != nil {
+		return models.Outcome{}, models.RatingResult{}, fmt.Errorf("failed to rate outcome: %w", err)
+	}
+
+	if err := s.athleteScoreService.SaveRatingResult(tx, result, confirmed.OutcomeId); err != nil {
+		return models.Outcome{}, models.RatingResult{}, fmt.Errorf("failed to update athlete scores: %w", err)
+	}
+
+	if bout.Status == models.BoutStatusDisputed {
+		if err := s.boutRepository.CompleteBout(tx, bout.BoutId, models.BoutStatusDisputed, actorID); err != nil {
+			return models.Outcome{}, models.RatingResult{}, fmt.Errorf("failed to complete bout: %w", err)
+		}
+	}
+	return confirmed, result, nil
+}
+
+// getPendingOutcome loads a pending outcome and its bout for a competitor about to answer it
+func (s *outcomeService) getPendingOutcome(outcomeID string, actorID int, action string) (models.Outcome, models.Bout, error) {
+	outcome, err := s.outcomeRepo.GetOutcomeById(outcomeID)
 	if err != nil {
-		return fmt.Errorf("failed to get loser score: %w", err)
+		return models.Outcome{}, models.Bout{}, fmt.Errorf("failed to get outcome %s: %w", outcomeID, err)
+	}
+	if outcome.VoidedDate != nil {
+		return models.Outcome{}, models.Bout{}, repositories.ErrOutcomeAlreadyVoided
+	}
+	if outcome.BoutId == 0 {
+		return models.Outcome{}, models.Bout{}, &OutcomeStatusError{OutcomeId: outcome.OutcomeId, Status: outcome.ConfirmationStatus, Action: action}
 	}
 
-	winnerScore, err := s.athleteScoreService.GetAthleteScoreByStyle(outcome.WinnerId, outcome.StyleId)
+	bout, err := s.boutRepository.GetBoutById(strconv.Itoa(outcome.BoutId))
 	if err != nil {
-		return fmt.Errorf("failed to get winner score: %w", err)
+		return models.Outcome{}, models.Bout{}, fmt.Errorf("failed to get bout: %w", err)
+	}
+	if err := requireBoutRole(bout, actorID, BoutRoleCompetitor); err != nil {
+		return models.Outcome{}, models.Bout{}, err
 	}
+	if outcome.ConfirmationStatus != models.OutcomeStatusPending {
+		return models.Outcome{}, models.Bout{}, &OutcomeStatusError{OutcomeId: outcome.OutcomeId, Status: outcome.ConfirmationStatus, Action: action}
+	}
+	return outcome, bout, nil
+}
 
-	err = s.athleteScoreService.CalculateNewScores(&winnerScore, &loserScore, outcome.IsDraw, outcome.OutcomeId)
+// gymOwnerId returns the owner of the gym a bout was held at, or 0 when it has no gym or the gym has no owner
+func (s *outcomeService) gymOwnerId(bout models.Bout) (int, error) {
+	if bout.GymId == nil {
+		return 0, nil
+	}
+	gym, err := s.gymRepo.GetGymById(strconv.Itoa(*bout.GymId))
 	if err != nil {
-		return fmt.Errorf("failed to calculate new scores: %w", err)
+		return 0, fmt.Errorf("failed to get gym %d: %w", *bout.GymId, err)
 	}
+	if gym.OwnerId == nil {
+		return 0, nil
+	}
+	return *gym.OwnerId, nil
+}
 
+// notify sends the same notification about a bout to each athlete, skipping missing ones
+func (s *outcomeService) notify(tx *repositories.Tx, bout models.Bout, kind string, message string, athleteIds ...int) error {
+	for _, athleteId := range athleteIds {
+		if athleteId == 0 {
+			continue
+		}
+		err := s.notificationRepo.AddNotification(tx, models.Notification{
+			AthleteId: athleteId,
+			BoutId:    &bout.BoutId,
+			Kind:      kind,
+			Message:   message,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to notify athlete %d: %w", athleteId, err)
+		}
+	}
 	return nil
 }
+
+// recomputeOverall refreshes both athletes' overall ratings after a rated outcome. The style scores
+// are already saved, so a stale overall rating is logged rather than failing the outcome.
+func (s *outcomeService) recomputeOverall(outcome models.Outcome, result models.RatingResult) {
+	if result.Unrated {
+		log.Printf("Outcome %d is unrated, scores unchanged", outcome.OutcomeId)
+		return
+	}
+	if err := s.overallRatingService.Recompute(outcome.WinnerId, outcome.LoserId); err != nil {
+		log.Printf("Failed to recompute overall ratings for outcome %d: %v", outcome.OutcomeId, err)
+	}
+}
+
+// overturnedOutcome applies a corrected result to a disputed outcome. The corrected winner and loser
+// must be the bout's two competitors and the result must differ from the one recorded.
+func overturnedOutcome(outcome models.Outcome, bout models.Bout, request models.OutcomeResolutionRequest) (models.Outcome, error) {
+	competitors := newRematchPair(bout.ChallengerId, bout.AcceptorId)
+	if request.WinnerId == request.LoserId || newRematchPair(request.WinnerId, request.LoserId) != competitors {
+		return models.Outcome{}, fmt.Errorf("the corrected winner and loser must be athletes %d and %d", bout.ChallengerId, bout.AcceptorId)
+	}
+	if request.IsDraw == outcome.IsDraw && (request.IsDraw || request.WinnerId == outcome.WinnerId) {
+		return models.Outcome{}, errors.New("an overturned outcome must change the result; uphold it instead")
+	}
+
+	outcome.WinnerId = request.WinnerId
+	outcome.LoserId = request.LoserId
+	outcome.IsDraw = request.IsDraw
+	return outcome, nil
+}
+
+// requireOutcomeReason trims a dispute or resolution reason and checks that it is given and fits its column
+func requireOutcomeReason(reason string, action string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", fmt.Errorf("a reason is required to %s an outcome", action)
+	}
+	if len(reason) > maxDisputeReasonLength {
+		return "", fmt.Errorf("reason cannot be longer than %d characters", maxDisputeReasonLength)
+	}
+	return reason, nil
+}
+
+// OutcomeStatusError is returned when an outcome is not in the confirmation status an action needs
+type OutcomeStatusError struct {
+	OutcomeId int
+	Status    string
+	Action    string
+}
+
+func (e *OutcomeStatusError) Error() string {
+	return fmt.Sprintf("outcome %d is %s and cannot be %s", e.OutcomeId, e.Status, e.Action)
+}
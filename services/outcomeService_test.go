@@ -0,0 +1,155 @@
+package services
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestOverturnedOutcome(t *testing.T) {
+	bout := models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 3}
+	recorded := models.Outcome{OutcomeId: 5, BoutId: 9, WinnerId: 1, LoserId: 2}
+	recordedDraw := models.Outcome{OutcomeId: 6, BoutId: 9, WinnerId: 1, LoserId: 2, IsDraw: true}
+
+	tests := []struct {
+		name    string
+		outcome models.Outcome
+		request models.OutcomeResolutionRequest
+		want    models.Outcome
+		wantErr bool
+	}{
+		{
+			name:    "winner and loser swapped",
+			outcome: recorded,
+			request: models.OutcomeResolutionRequest{WinnerId: 2, LoserId: 1},
+			want:    models.Outcome{OutcomeId: 5, BoutId: 9, WinnerId: 2, LoserId: 1},
+		},
+		{
+			name:    "win changed to a draw",
+			outcome: recorded,
+			request: models.OutcomeResolutionRequest{WinnerId: 1, LoserId: 2, IsDraw: true},
+			want:    models.Outcome{OutcomeId: 5, BoutId: 9, WinnerId: 1, LoserId: 2, IsDraw: true},
+		},
+		{
+			name:    "draw changed to a win",
+			outcome: recordedDraw,
+			request: models.OutcomeResolutionRequest{WinnerId: 1, LoserId: 2},
+			want:    models.Outcome{OutcomeId: 6, BoutId: 9, WinnerId: 1, LoserId: 2},
+		},
+		{name: "same result", outcome: recorded, request: models.OutcomeResolutionRequest{WinnerId: 1, LoserId: 2}, wantErr: true},
+		{name: "still a draw", outcome: recordedDraw, request: models.OutcomeResolutionRequest{WinnerId: 2, LoserId: 1, IsDraw: true}, wantErr: true},
+		{name: "referee as winner", outcome: recorded, request: models.OutcomeResolutionRequest{WinnerId: 3, LoserId: 1}, wantErr: true},
+		{name: "same athlete twice", outcome: recorded, request: models.OutcomeResolutionRequest{WinnerId: 2, LoserId: 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := overturnedOutcome(tt.outcome, bout, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("overturnedOutcome() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("overturnedOutcome() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireOutcomeReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		want    string
+		wantErr bool
+	}{
+		{name: "trimmed", reason: "  the tap was a slip \n", want: "the tap was a slip"},
+		{name: "longest allowed", reason: strings.Repeat("x", maxDisputeReasonLength), want: strings.Repeat("x", maxDisputeReasonLength)},
+		{name: "blank", reason: " \t ", wantErr: true},
+		{name: "too long", reason: strings.Repeat("x", maxDisputeReasonLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := requireOutcomeReason(tt.reason, "dispute")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requireOutcomeReason() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("requireOutcomeReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireAdminOrGymOwner(t *testing.T) {
+	bout := models.Bout{BoutId: 9, ChallengerId: 1, AcceptorId: 2, RefereeId: 3}
+
+	tests := []struct {
+		name       string
+		gymOwnerId int
+		actorId    int
+		wantStatus int
+	}{
+		{name: "gym owner", gymOwnerId: 7, actorId: 7, wantStatus: http.StatusOK},
+		{name: "someone else", gymOwnerId: 7, actorId: 1, wantStatus: http.StatusForbidden},
+		{name: "no gym owner", gymOwnerId: 0, actorId: 1, wantStatus: http.StatusForbidden},
+		{name: "anonymous", gymOwnerId: 7, actorId: 0, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireAdminOrGymOwner(bout, tt.gymOwnerId, tt.actorId)
+			if got := authorizationErrorStatus(err, http.StatusOK); got != tt.wantStatus {
+				t.Errorf("requireAdminOrGymOwner() error = %v, which maps to %d, want %d", err, got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateOutcomeDetails(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	stringPtr := func(v string) *string { return &v }
+	scorecard := []models.OutcomeRoundScore{
+		{RoundNumber: 1, AthleteId: 1, Points: 4, Advantages: 1},
+		{RoundNumber: 1, AthleteId: 2, Points: 2},
+		{RoundNumber: 2, AthleteId: 1, Points: 2},
+		{RoundNumber: 2, AthleteId: 2, Points: 0, Penalties: 1},
+	}
+	submission := func(scores []models.OutcomeRoundScore, finish *models.OutcomeFinish) models.Outcome {
+		return models.Outcome{WinnerId: 1, LoserId: 2, FinishMethod: models.FinishSubmission, Scorecard: scores, Finish: finish}
+	}
+
+	tests := []struct {
+		name    string
+		outcome models.Outcome
+		wantErr bool
+	}{
+		{name: "no details", outcome: submission(nil, nil)},
+		{name: "scorecard and finish", outcome: submission(scorecard, &models.OutcomeFinish{
+			SubmissionType: stringPtr("armbar"), FinishRound: intPtr(2), FinishTimeSeconds: intPtr(95)})},
+		{name: "finish round without a scorecard", outcome: submission(nil, &models.OutcomeFinish{FinishRound: intPtr(3)})},
+		{name: "referee on the scorecard", outcome: submission([]models.OutcomeRoundScore{{RoundNumber: 1, AthleteId: 3}}, nil), wantErr: true},
+		{name: "round zero", outcome: submission([]models.OutcomeRoundScore{{RoundNumber: 0, AthleteId: 1}}, nil), wantErr: true},
+		{name: "negative points", outcome: submission([]models.OutcomeRoundScore{{RoundNumber: 1, AthleteId: 1, Points: -2}}, nil), wantErr: true},
+		{name: "scored twice in a round", outcome: submission([]models.OutcomeRoundScore{
+			{RoundNumber: 1, AthleteId: 1, Points: 2}, {RoundNumber: 1, AthleteId: 1, Points: 3}}, nil), wantErr: true},
+		{name: "finish after the last scored round", outcome: submission(scorecard, &models.OutcomeFinish{FinishRound: intPtr(3)}), wantErr: true},
+		{name: "finish round zero", outcome: submission(nil, &models.OutcomeFinish{FinishRound: intPtr(0)}), wantErr: true},
+		{name: "negative finish time", outcome: submission(nil, &models.OutcomeFinish{FinishTimeSeconds: intPtr(-1)}), wantErr: true},
+		{name: "blank submission type", outcome: submission(nil, &models.OutcomeFinish{SubmissionType: stringPtr("  ")}), wantErr: true},
+		{name: "submission type on a decision", outcome: models.Outcome{WinnerId: 1, LoserId: 2, FinishMethod: models.FinishDecision,
+			Finish: &models.OutcomeFinish{SubmissionType: stringPtr("armbar")}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutcomeDetails(tt.outcome)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateOutcomeDetails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"ronin/models"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultLeaderboardLimit = 50
+
+// OverallRatingHandler handles HTTP requests for composite cross-style ratings
+type OverallRatingHandler struct {
+	service *OverallRatingService
+}
+
+// NewOverallRatingHandler creates a new instance of OverallRatingHandler
+func NewOverallRatingHandler(service *OverallRatingService) *OverallRatingHandler {
+	return &OverallRatingHandler{
+		service: service,
+	}
+}
+
+// GetAthleteOverallRating handles GET requests for an athlete's overall rating and its per-style weights
+func (h *OverallRatingHandler) GetAthleteOverallRating(w http.ResponseWriter, r *http.Request) {
+	athleteId, err := strconv.Atoi(mux.Vars(r)["athlete_id"])
+	if err != nil {
+		SendError(w, "Invalid athlete_id", http.StatusBadRequest)
+		return
+	}
+
+	rating, err := h.service.GetOverallRating(athleteId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	SendJSON(w, rating)
+}
+
+// GetOverallLeaderboard handles GET requests for the global overall leaderboard.
+// Optional query parameters: limit (default 50) and offset.
+func (h *OverallRatingHandler) GetOverallLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultLeaderboardLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil {
+			SendError(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			SendError(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+		offset = value
+	}
+
+	ratings, err := h.service.GetOverallLeaderboard(limit, offset)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	SendJSON(w, ratings)
+}
+
+// GetOverallRatingConfig handles GET requests for the overall rating weighting config
+func (h *OverallRatingHandler) GetOverallRatingConfig(w http.ResponseWriter, r *http.Request) {
+	config, err := h.service.GetOverallRatingConfig()
+	if err != nil {
+		SendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, config)
+}
+
+// SetOverallRatingConfig handles PUT requests to change the overall rating weighting config
+func (h *OverallRatingHandler) SetOverallRatingConfig(w http.ResponseWriter, r *http.Request) {
+	var config models.OverallRatingConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetOverallRatingConfig(config, authenticatedAthleteId(r)); err != nil {
+		SendError(w, err.Error(), authorizationErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+	SendJSON(w, config)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// StyleSimilarity says how much two styles measure the same skill, from 0 (unrelated) to 1 (identical)
+type StyleSimilarity struct {
+	StyleId      int     `json:"styleId"`
+	OtherStyleId int     `json:"otherStyleId"`
+	Similarity   float64 `json:"similarity"`
+}
+
+// OverallRatingSettings control how per-style scores are weighted into the overall rating.
+// Each style is weighted by its rated bouts plus BoutPrior, capped at MaxBouts when set, and
+// divided by one plus its similarity to the athlete's other styles so that closely related
+// styles are not counted twice.
+type OverallRatingSettings struct {
+	BoutPrior    float64           `json:"boutPrior"`
+	MaxBouts     int               `json:"maxBouts"`
+	Similarities []StyleSimilarity `json:"similarities"`
+}
+
+// DefaultOverallRatingSettings returns settings that weight styles by bouts, with no similarity discount
+func DefaultOverallRatingSettings() OverallRatingSettings {
+	return OverallRatingSettings{
+		BoutPrior: 1,
+	}
+}
+
+// validate checks the overall rating settings
+func (o OverallRatingSettings) validate() error {
+	if o.BoutPrior < 0 {
+		return errors.New("bout prior cannot be negative")
+	}
+	if o.MaxBouts < 0 {
+		return errors.New("max bouts cannot be negative")
+	}
+	for _, similarity := range o.Similarities {
+		if similarity.StyleId == 0 || similarity.OtherStyleId == 0 {
+			return errors.New("style similarity requires both style IDs")
+		}
+		if similarity.StyleId == similarity.OtherStyleId {
+			return errors.New("style similarity must be between two different styles")
+		}
+		if similarity.Similarity < 0 || similarity.Similarity > 1 {
+			return errors.New("style similarity must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+// similarity returns how similar two styles are, in either order
+func (o OverallRatingSettings) similarity(styleId, otherStyleId int) float64 {
+	for _, s := range o.Similarities {
+		if (s.StyleId == styleId && s.OtherStyleId == otherStyleId) ||
+			(s.StyleId == otherStyleId && s.OtherStyleId == styleId) {
+			return s.Similarity
+		}
+	}
+	return 0
+}
+
+// combine weights each style's score and returns the overall rating built from them
+func (o OverallRatingSettings) combine(athleteId int, styles []models.OverallRatingStyle) models.OverallRating {
+	rating := models.OverallRating{
+		AthleteId:   athleteId,
+		StylesRated: len(styles),
+		Styles:      styles,
+	}
+
+	totalWeight := 0.0
+	for i := range styles {
+		bouts := styles[i].RatedBouts
+		if o.MaxBouts > 0 && bouts > o.MaxBouts {
+			bouts = o.MaxBouts
+		}
+
+		overlap := 0.0
+		for j := range styles {
+			if i != j {
+				overlap += o.similarity(styles[i].StyleId, styles[j].StyleId)
+			}
+		}
+
+		styles[i].Weight = (float64(bouts) + o.BoutPrior) / (1 + overlap)
+		totalWeight += styles[i].Weight
+		rating.RatedBouts += styles[i].RatedBouts
+	}
+
+	// Without any bouts or prior every style counts the same
+	if totalWeight == 0 {
+		for i := range styles {
+			styles[i].Weight = 1
+		}
+		totalWeight = float64(len(styles))
+	}
+
+	weighted := 0.0
+	for i := range styles {
+		styles[i].Weight /= totalWeight
+		weighted += styles[i].Score * styles[i].Weight
+	}
+	rating.OverallScore = weighted
+	return rating
+}
+
+// OverallRatingService maintains each athlete's composite rating across styles
+type OverallRatingService struct {
+	repo *repositories.OverallRatingRepository
+}
+
+// NewOverallRatingService creates a new instance of OverallRatingService
+func NewOverallRatingService(repo *repositories.OverallRatingRepository) *OverallRatingService {
+	return &OverallRatingService{
+		repo: repo,
+	}
+}
+
+// GetOverallRatingConfig returns the overall rating weighting config, falling back to the defaults
+func (s *OverallRatingService) GetOverallRatingConfig() (models.OverallRatingConfig, error) {
+	config, err := s.repo.GetOverallRatingConfig()
+	if err == sql.ErrNoRows {
+		return models.OverallRatingConfig{Settings: []byte("{}")}, nil
+	}
+	if err != nil {
+		return models.OverallRatingConfig{}, fmt.Errorf("failed to get overall rating config: %w", err)
+	}
+	return config, nil
+}
+
+// SetOverallRatingConfig validates and stores the weighting config, then recomputes every overall
+// rating. Only an admin can change it.
+func (s *OverallRatingService) SetOverallRatingConfig(config models.OverallRatingConfig, actorID int) error {
+	if err := requireAdmin(actorID); err != nil {
+		return err
+	}
+	if _, err := overallRatingSettings(config); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpsertOverallRatingConfig(config); err != nil {
+		return fmt.Errorf("failed to save overall rating config: %w", err)
+	}
+	return s.RecomputeAll()
+}
+
+// GetOverallRating returns an athlete's overall rating with the weight given to each style
+func (s *OverallRatingService) GetOverallRating(athleteId int) (models.OverallRating, error) {
+	settings, err := s.settings()
+	if err != nil {
+		return models.OverallRating{}, err
+	}
+
+	rating, err := s.repo.GetOverallRating(athleteId)
+	if err == sql.ErrNoRows {
+		return models.OverallRating{}, fmt.Errorf("athlete %d has no overall rating", athleteId)
+	}
+	if err != nil {
+		return models.OverallRating{}, fmt.Errorf("failed to get overall rating for athlete %d: %w", athleteId, err)
+	}
+
+	styles, err := s.repo.GetAthleteStyleRatings(athleteId)
+	if err != nil {
+		return models.OverallRating{}, fmt.Errorf("failed to get style ratings for athlete %d: %w", athleteId, err)
+	}
+	rating.Styles = settings.combine(athleteId, styles).Styles
+	return rating, nil
+}
+
+// GetOverallLeaderboard returns a page of athletes ordered by overall rating
+func (s *OverallRatingService) GetOverallLeaderboard(limit, offset int) ([]models.OverallRating, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+	if offset < 0 {
+		return nil, errors.New("offset cannot be negative")
+	}
+
+	ratings, err := s.repo.GetOverallLeaderboard(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overall leaderboard: %w", err)
+	}
+	return ratings, nil
+}
+
+// Recompute rebuilds and stores the overall rating of the given athletes
+func (s *OverallRatingService) Recompute(athleteIds ...int) error {
+	settings, err := s.settings()
+	if err != nil {
+		return err
+	}
+
+	for _, athleteId := range athleteIds {
+		styles, err := s.repo.GetAthleteStyleRatings(athleteId)
+		if err != nil {
+			return fmt.Errorf("failed to get style ratings for athlete %d: %w", athleteId, err)
+		}
+		if len(styles) == 0 {
+			continue
+		}
+
+		if err := s.repo.UpsertOverallRating(settings.combine(athleteId, styles)); err != nil {
+			return fmt.Errorf("failed to save overall rating for athlete %d: %w", athleteId, err)
+		}
+	}
+	return nil
+}
+
+// RecomputeAll rebuilds the overall rating of every rated athlete
+func (s *OverallRatingService) RecomputeAll() error {
+	athleteIds, err := s.repo.GetRatedAthleteIds()
+	if err != nil {
+		return fmt.Errorf("failed to get rated athletes: %w", err)
+	}
+
+	if err := s.Recompute(athleteIds...); err != nil {
+		return err
+	}
+	log.Printf("Recomputed overall ratings for %d athletes", len(athleteIds))
+	return nil
+}
+
+// settings loads and decodes the current weighting config
+func (s *OverallRatingService) settings() (OverallRatingSettings, error) {
+	config, err := s.GetOverallRatingConfig()
+	if err != nil {
+		return OverallRatingSettings{}, err
+	}
+	return overallRatingSettings(config)
+}
+
+// overallRatingSettings decodes and validates the settings of an overall rating config
+func overallRatingSettings(config models.OverallRatingConfig) (OverallRatingSettings, error) {
+	settings := DefaultOverallRatingSettings()
+	if err := decodeEngineSettings(config.Settings, &settings); err != nil {
+		return OverallRatingSettings{}, err
+	}
+	if err := settings.validate(); err != nil {
+		return OverallRatingSettings{}, fmt.Errorf("invalid overall rating settings: %w", err)
+	}
+	return settings, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestOverallRatingCombine(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    OverallRatingSettings
+		styles      []models.OverallRatingStyle
+		wantScore   float64
+		wantWeights []float64
+	}{
+		{
+			name:     "weights styles by rated bouts plus the prior",
+			settings: DefaultOverallRatingSettings(),
+			styles: []models.OverallRatingStyle{
+				{StyleId: 1, Score: 500, RatedBouts: 5},
+				{StyleId: 2, Score: 400, RatedBouts: 1},
+			},
+			wantScore:   475,
+			wantWeights: []float64{0.75, 0.25},
+		},
+		{
+			name:     "caps bouts at max bouts",
+			settings: OverallRatingSettings{BoutPrior: 0, MaxBouts: 5},
+			styles: []models.OverallRatingStyle{
+				{StyleId: 1, Score: 500, RatedBouts: 20},
+				{StyleId: 2, Score: 400, RatedBouts: 5},
+			},
+			wantScore:   450,
+			wantWeights: []float64{0.5, 0.5},
+		},
+		{
+			name: "discounts styles similar to the athlete's others",
+			settings: OverallRatingSettings{BoutPrior: 0, Similarities: []StyleSimilarity{
+				{StyleId: 2, OtherStyleId: 1, Similarity: 1},
+			}},
+			styles: []models.OverallRatingStyle{
+				{StyleId: 1, Score: 500, RatedBouts: 4},
+				{StyleId: 2, Score: 500, RatedBouts: 4},
+				{StyleId: 3, Score: 350, RatedBouts: 4},
+			},
+			wantScore:   425,
+			wantWeights: []float64{0.25, 0.25, 0.5},
+		},
+		{
+			name:     "counts every style the same without bouts or prior",
+			settings: OverallRatingSettings{},
+			styles: []models.OverallRatingStyle{
+				{StyleId: 1, Score: 420},
+				{StyleId: 2, Score: 380},
+			},
+			wantScore:   400,
+			wantWeights: []float64{0.5, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating := tt.settings.combine(7, tt.styles)
+			if math.Abs(rating.OverallScore-tt.wantScore) > ratingTolerance {
+				t.Errorf("combine() overall score = %v, want %v", rating.OverallScore, tt.wantScore)
+			}
+			if rating.StylesRated != len(tt.styles) {
+				t.Errorf("combine() styles rated = %d, want %d", rating.StylesRated, len(tt.styles))
+			}
+			for i, want := range tt.wantWeights {
+				if math.Abs(rating.Styles[i].Weight-want) > ratingTolerance {
+					t.Errorf("style %d weight = %v, want %v", rating.Styles[i].StyleId, rating.Styles[i].Weight, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOverallRatingSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings OverallRatingSettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: DefaultOverallRatingSettings()},
+		{name: "negative prior", settings: OverallRatingSettings{BoutPrior: -1}, wantErr: true},
+		{name: "negative max bouts", settings: OverallRatingSettings{MaxBouts: -1}, wantErr: true},
+		{name: "similarity to itself", settings: OverallRatingSettings{Similarities: []StyleSimilarity{{StyleId: 1, OtherStyleId: 1, Similarity: 0.5}}}, wantErr: true},
+		{name: "similarity above one", settings: OverallRatingSettings{Similarities: []StyleSimilarity{{StyleId: 1, OtherStyleId: 2, Similarity: 1.5}}}, wantErr: true},
+		{name: "similarity missing a style", settings: OverallRatingSettings{Similarities: []StyleSimilarity{{StyleId: 1, Similarity: 0.5}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// DecaySettings control how ratings in a style decay while an athlete is inactive.
+// They are read from the style's rating config settings; decay is off when DecayInactiveDays is 0.
+type DecaySettings struct {
+	DecayInactiveDays int     `json:"decayInactiveDays"`
+	DecayIntervalDays int     `json:"decayIntervalDays"`
+	DecayPoints       float64 `json:"decayPoints"`
+	DecayFloor        float64 `json:"decayFloor"`
+}
+
+// DefaultDecaySettings returns settings with decay disabled
+func DefaultDecaySettings() DecaySettings {
+	return DecaySettings{
+		DecayInactiveDays: 0,
+		DecayIntervalDays: 30,
+		DecayPoints:       10,
+		DecayFloor:        repositories.DefaultScore,
+	}
+}
+
+// validate checks the decay settings for a style
+func (d DecaySettings) validate() error {
+	if d.DecayInactiveDays < 0 {
+		return errors.New("decay inactive days cannot be negative")
+	}
+	if d.DecayInactiveDays > 0 && d.DecayIntervalDays <= 0 {
+		return errors.New("decay interval days must be greater than zero")
+	}
+	if d.DecayPoints < 0 {
+		return errors.New("decay points cannot be negative")
+	}
+	return nil
+}
+
+// enabled reports whether the style decays inactive ratings at all
+func (d DecaySettings) enabled() bool {
+	return d.DecayInactiveDays > 0 && d.DecayPoints > 0
+}
+
+// RatingDecayService lowers the ratings of athletes with no recent outcomes in a style
+type RatingDecayService struct {
+	athleteScoreService  *AthleteScoreService
+	overallRatingService *OverallRatingService
+	scoreRepo            *repositories.AthleteScoreRepository
+	styleRepo            *repositories.StyleRepository
+}
+
+// NewRatingDecayService creates a new instance of RatingDecayService
+func NewRatingDecayService(
+	athleteScoreService *AthleteScoreService,
+	overallRatingService *OverallRatingService,
+	scoreRepo *repositories.AthleteScoreRepository,
+	styleRepo *repositories.StyleRepository,
+) *RatingDecayService {
+	return &RatingDecayService{
+		athleteScoreService:  athleteScoreService,
+		overallRatingService: overallRatingService,
+		scoreRepo:            scoreRepo,
+		styleRepo:            styleRepo,
+	}
+}
+
+// Run applies one round of decay to every style that has it enabled
+func (s *RatingDecayService) Run() error {
+	styles, err := s.styleRepo.GetAllStyles()
+	if err != nil {
+		return fmt.Errorf("failed to get styles: %w", err)
+	}
+
+	now := time.Now()
+	total := 0
+	for _, style := range styles {
+		decayed, err := s.decayStyle(style.StyleId, now)
+		total += decayed
+		if err != nil {
+			return fmt.Errorf("failed to decay style %d: %w", style.StyleId, err)
+		}
+		if decayed > 0 {
+			log.Printf("Applied inactivity decay to %d athletes in style %d", decayed, style.StyleId)
+		}
+	}
+
+	if total > 0 {
+		return s.overallRatingService.RecomputeAll()
+	}
+	return nil
+}
+
+// decayStyle lowers every inactive rating in a style and flags those athletes as provisional
+func (s *RatingDecayService) decayStyle(styleId int, now time.Time) (int, error) {
+	rating, err := s.athleteScoreService.ratingForStyle(styleId)
+	if err != nil {
+		return 0, err
+	}
+	if !rating.decay.enabled() {
+		return 0, nil
+	}
+
+	inactiveSince := now.AddDate(0, 0, -rating.decay.DecayInactiveDays)
+	decayedSince := now.AddDate(0, 0, -rating.decay.DecayIntervalDays)
+	scores, err := s.scoreRepo.GetInactiveScores(styleId, inactiveSince, decayedSince)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get inactive scores: %w", err)
+	}
+
+	decayed := 0
+	for _, score := range scores {
+		newScore := math.Max(score.Score-rating.decay.DecayPoints, rating.decay.DecayFloor)
+		if newScore >= score.Score && score.Provisional {
+			continue
+		}
+
+		change := models.RatingChange{
+			AthleteScore: score,
+			Reason:       models.ScoreReasonDecay,
+		}
+		change.Score = math.Min(newScore, score.Score)
+		change.Provisional = true
+
+		if err := s.scoreRepo.UpdateAthleteScore(change, 0); err != nil {
+			return decayed, fmt.Errorf("failed to decay athlete %d: %w", score.AthleteId, err)
+		}
+		decayed++
+	}
+	return decayed, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import "testing"
+
+func TestDecaySettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    DecaySettings
+		wantErr     bool
+		wantEnabled bool
+	}{
+		{name: "defaults are off", settings: DefaultDecaySettings()},
+		{name: "enabled", settings: DecaySettings{DecayInactiveDays: 60, DecayIntervalDays: 30, DecayPoints: 10, DecayFloor: 400}, wantEnabled: true},
+		{name: "no points is off", settings: DecaySettings{DecayInactiveDays: 60, DecayIntervalDays: 30, DecayPoints: 0}},
+		{name: "negative inactive days", settings: DecaySettings{DecayInactiveDays: -1, DecayIntervalDays: 30, DecayPoints: 10}, wantErr: true},
+		{name: "zero interval while enabled", settings: DecaySettings{DecayInactiveDays: 60, DecayIntervalDays: 0, DecayPoints: 10}, wantErr: true},
+		{name: "zero interval while off", settings: DecaySettings{DecayInactiveDays: 0, DecayIntervalDays: 0, DecayPoints: 10}},
+		{name: "negative points", settings: DecaySettings{DecayInactiveDays: 60, DecayIntervalDays: 30, DecayPoints: -5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := tt.settings.enabled(); got != tt.wantEnabled {
+				t.Errorf("enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ronin/interfaces"
+	"ronin/models"
+)
+
+// EngineElo is the rating engine used for styles without a style_rating_config row
+const EngineElo = "elo"
+
+// NewRatingEngine builds the rating engine named in a style's rating config
+func NewRatingEngine(config models.StyleRatingConfig) (interfaces.RatingEngine, error) {
+	switch config.Engine {
+	case "", EngineElo:
+		settings := DefaultEloSettings()
+		if err := decodeEngineSettings(config.Settings, &settings); err != nil {
+			return nil, err
+		}
+		return NewEloRatingEngine(settings)
+	case EngineGlicko2:
+		settings := DefaultGlicko2Settings()
+		if err := decodeEngineSettings(config.Settings, &settings); err != nil {
+			return nil, err
+		}
+		return NewGlicko2RatingEngine(settings)
+	default:
+		return nil, fmt.Errorf("unknown rating engine %q", config.Engine)
+	}
+}
+
+// decodeEngineSettings overlays the stored settings onto the engine defaults
+func decodeEngineSettings(raw json.RawMessage, settings interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, settings); err != nil {
+		return fmt.Errorf("invalid rating engine settings: %w", err)
+	}
+	return nil
+}
+
+// styleRating bundles everything needed to rate an outcome in a style
+type styleRating struct {
+	engine  interfaces.RatingEngine
+	margin  MarginOfVictorySettings
+	rematch RematchSettings
+	decay   DecaySettings
+	season  SeasonResetSettings
+}
+
+// newStyleRating builds the rating engine, margin-of-victory and rematch rules, decay and season reset settings from a style's rating config
+func newStyleRating(config models.StyleRatingConfig) (styleRating, error) {
+	engine, err := NewRatingEngine(config)
+	if err != nil {
+		return styleRating{}, err
+	}
+
+	margin := DefaultMarginOfVictorySettings()
+	if err := decodeEngineSettings(config.Settings, &margin); err != nil {
+		return styleRating{}, err
+	}
+	if err := margin.validate(); err != nil {
+		return styleRating{}, fmt.Errorf("invalid margin of victory settings: %w", err)
+	}
+
+	rematch := DefaultRematchSettings()
+	if err := decodeEngineSettings(config.Settings, &rematch); err != nil {
+		return styleRating{}, err
+	}
+	if err := rematch.validate(); err != nil {
+		return styleRating{}, fmt.Errorf("invalid rematch settings: %w", err)
+	}
+
+	decay := DefaultDecaySettings()
+	if err := decodeEngineSettings(config.Settings, &decay); err != nil {
+		return styleRating{}, err
+	}
+	if err := decay.validate(); err != nil {
+		return styleRating{}, fmt.Errorf("invalid decay settings: %w", err)
+	}
+
+	season := DefaultSeasonResetSettings()
+	if err := decodeEngineSettings(config.Settings, &season); err != nil {
+		return styleRating{}, err
+	}
+	if err := season.validate(); err != nil {
+		return styleRating{}, fmt.Errorf("invalid season reset settings: %w", err)
+	}
+
+	return styleRating{
+		engine:  engine,
+		margin:  margin,
+		rematch: rematch,
+		decay:   decay,
+		season:  season,
+	}, nil
+}
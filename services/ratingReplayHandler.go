@@ -0,0 +1,52 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// RatingReplayHandler handles HTTP requests for rating recomputation
+type RatingReplayHandler struct {
+	service *RatingReplayService
+}
+
+// NewRatingReplayHandler creates a new instance of RatingReplayHandler
+func NewRatingReplayHandler(service *RatingReplayService) *RatingReplayHandler {
+	return &RatingReplayHandler{
+		service: service,
+	}
+}
+
+// ReplayRatings handles POST requests to rebuild ratings from the outcome history.
+// Optional query parameters: style_id limits the replay to one style, dry_run=true reports without writing.
+// Only an admin can replay ratings.
+func (h *RatingReplayHandler) ReplayRatings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	styleId := 0
+	if styleStr := query.Get("style_id"); styleStr != "" {
+		id, err := strconv.Atoi(styleStr)
+		if err != nil {
+			SendError(w, "Invalid style_id", http.StatusBadRequest)
+			return
+		}
+		styleId = id
+	}
+
+	dryRun := false
+	if dryRunStr := query.Get("dry_run"); dryRunStr != "" {
+		value, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			SendError(w, "Invalid dry_run", http.StatusBadRequest)
+			return
+		}
+		dryRun = value
+	}
+
+	report, err := h.service.Replay(styleId, dryRun, authenticatedAthleteId(r))
+	if err != nil {
+		SendError(w, err.Error(), authorizationErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+	SendJSON(w, report)
+}
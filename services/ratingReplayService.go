@@ -0,0 +1,407 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// RatingReplayService rebuilds athlete_score and athlete_score_history from the outcome history,
+// the resets of closed seasons and the inactivity decay already applied
+type RatingReplayService struct {
+	athleteScoreService  *AthleteScoreService
+	overallRatingService *OverallRatingService
+	scoreRepo            *repositories.AthleteScoreRepository
+	outcomeRepo          *repositories.OutcomeRepository
+	styleRepo            *repositories.StyleRepository
+	seasonRepo           *repositories.SeasonRepository
+	unitOfWork           *repositories.UnitOfWork
+}
+
+// styleReplay is the in-memory result of replaying a single style. seasons and decays hold the
+// season resets and decays not yet reapplied, oldest first.
+type styleReplay struct {
+	rows       []models.RatingChange
+	final      map[int]models.AthleteScore
+	replayed   int
+	skipped    int
+	seasons    []models.Season
+	decays     []models.AthleteScoreHistory
+	decayFloor float64
+}
+
+// NewRatingReplayService creates a new instance of RatingReplayService
+func NewRatingReplayService(
+	athleteScoreService *AthleteScoreService,
+	overallRatingService *OverallRatingService,
+	scoreRepo *repositories.AthleteScoreRepository,
+	outcomeRepo *repositories.OutcomeRepository,
+	styleRepo *repositories.StyleRepository,
+	seasonRepo *repositories.SeasonRepository,
+	unitOfWork *repositories.UnitOfWork,
+) *RatingReplayService {
+	return &RatingReplayService{
+		athleteScoreService:  athleteScoreService,
+		overallRatingService: overallRatingService,
+		scoreRepo:            scoreRepo,
+		outcomeRepo:          outcomeRepo,
+		styleRepo:            styleRepo,
+		seasonRepo:           seasonRepo,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+// Replay recomputes ratings for a style, or for every style when styleId is 0. Only an admin can run it.
+// In dry-run mode nothing is written and the report only describes what would change. Each style is
+// read and rewritten in one transaction holding its rating lock, so no outcome, decay or season reset
+// can be rated in between.
+func (s *RatingReplayService) Replay(styleId int, dryRun bool, actorID int) (models.RatingReplayReport, error) {
+	if err := requireAdmin(actorID); err != nil {
+		return models.RatingReplayReport{}, err
+	}
+
+	styleIds, err := s.styleIdsToReplay(styleId)
+	if err != nil {
+		return models.RatingReplayReport{}, err
+	}
+
+	report := models.RatingReplayReport{
+		StyleIds: styleIds,
+		DryRun:   dryRun,
+		Changes:  []models.RatingReplayChange{},
+	}
+
+	for _, id := range styleIds {
+		var replay styleReplay
+		var changes []models.RatingReplayChange
+		err := s.unitOfWork.Run(func(tx *repositories.Tx) error {
+			if err := s.scoreRepo.LockStyleScores(tx, id); err != nil {
+				return fmt.Errorf("failed to lock style %d: %w", id, err)
+			}
+
+			var err error
+			replay, err = s.replayStyle(tx, id, 0)
+			if err != nil {
+				return fmt.Errorf("failed to replay style %d: %w", id, err)
+			}
+
+			changes, err = s.diffStyle(tx, id, replay.final)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				return nil
+			}
+			if err := s.scoreRepo.RewriteStyleScores(tx, id, replay.rows); err != nil {
+				return fmt.Errorf("failed to write replayed scores for style %d: %w", id, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return models.RatingReplayReport{}, err
+		}
+		if !dryRun {
+			log.Printf("Replayed %d outcomes for style %d", replay.replayed, id)
+		}
+
+		report.OutcomesReplayed += replay.replayed
+		report.OutcomesSkipped += replay.skipped
+		report.Changes = append(report.Changes, changes...)
+	}
+
+	if !dryRun {
+		if err := s.overallRatingService.RecomputeAll(); err != nil {
+			return models.RatingReplayReport{}, err
+		}
+	}
+
+	return report, nil
+}
+
+// styleIdsToReplay expands a style filter into the list of styles to rebuild
+func (s *RatingReplayService) styleIdsToReplay(styleId int) ([]int, error) {
+	if styleId != 0 {
+		return []int{styleId}, nil
+	}
+
+	styles, err := s.styleRepo.GetAllStyles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get styles: %w", err)
+	}
+
+	styleIds := make([]int, 0, len(styles))
+	for _, style := range styles {
+		styleIds = append(styleIds, style.StyleId)
+	}
+	return styleIds, nil
+}
+
+// ReplayStyleWithout rebuilds a style's ratings as if an outcome had never been recorded and writes
+// them inside a shared transaction, so later outcomes are re-rated without it. Season resets and
+// inactivity decay are reapplied where they happened, so only the outcome's own contribution is
+// taken out. The transaction must already hold the style's rating lock. It returns how many
+// outcomes were replayed and how each current rating changed. Overall ratings are left to the
+// caller to recompute once the transaction commits.
+func (s *RatingReplayService) ReplayStyleWithout(tx *repositories.Tx, styleId int, outcomeId int) (int, []models.RatingReplayChange, error) {
+	replay, err := s.replayStyle(tx, styleId, outcomeId)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to replay style %d: %w", styleId, err)
+	}
+
+	changes, err := s.diffStyle(tx, styleId, replay.final)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := s.scoreRepo.RewriteStyleScores(tx, styleId, replay.rows); err != nil {
+		return 0, nil, fmt.Errorf("failed to write replayed scores for style %d: %w", styleId, err)
+	}
+	return replay.replayed, changes, nil
+}
+
+// replayStyle seeds every athlete at the starting rating and applies the style's outcomes in order,
+// soft-resetting ratings wherever a season was closed in between and reapplying inactivity decay at
+// the times it was recorded. The outcome with ID excludeOutcomeId, if any, is left out. The history
+// is read through tx, which must hold the style's rating lock.
+func (s *RatingReplayService) replayStyle(tx *repositories.Tx, styleId int, excludeOutcomeId int) (styleReplay, error) {
+	rating, err := s.athleteScoreService.ratingForStyle(styleId)
+	if err != nil {
+		return styleReplay{}, err
+	}
+
+	seeds, err := s.scoreRepo.GetStyleRatingSeeds(tx, styleId)
+	if err != nil {
+		return styleReplay{}, fmt.Errorf("failed to get rating seeds: %w", err)
+	}
+
+	outcomes, err := s.outcomeRepo.GetOutcomesByStyle(tx, styleId)
+	if err != nil {
+		return styleReplay{}, fmt.Errorf("failed to get outcomes: %w", err)
+	}
+
+	seasons, err := s.seasonRepo.GetClosedSeasonsByStyle(tx, styleId)
+	if err != nil {
+		return styleReplay{}, fmt.Errorf("failed to get closed seasons: %w", err)
+	}
+
+	decays, err := s.scoreRepo.GetDecayHistoryByStyle(tx, styleId)
+	if err != nil {
+		return styleReplay{}, fmt.Errorf("failed to get decay history: %w", err)
+	}
+
+	return s.replayOutcomes(rating, seeds, outcomes, seasons, decays, excludeOutcomeId, time.Now())
+}
+
+// replayOutcomes runs a style's history through its rating rules in memory: every seeded athlete
+// starts at the starting rating, then the outcomes are applied in order with the season resets and
+// decays recorded in between, and finally those recorded before until
+func (s *RatingReplayService) replayOutcomes(rating styleRating, seeds []models.AthleteScore, outcomes []models.Outcome,
+	seasons []models.Season, decays []models.AthleteScoreHistory, excludeOutcomeId int, until time.Time) (styleReplay, error) {
+	replay := styleReplay{
+		final:      make(map[int]models.AthleteScore),
+		seasons:    seasons,
+		decays:     decays,
+		decayFloor: rating.decay.DecayFloor,
+	}
+	ratedAt := make(map[rematchPair][]time.Time)
+	for _, seed := range seeds {
+		seed.Score = repositories.DefaultScore
+		seed.RatingDeviation = repositories.DefaultRatingDeviation
+		seed.Volatility = repositories.DefaultVolatility
+		replay.rows = append(replay.rows, models.RatingChange{AthleteScore: seed, Reason: models.ScoreReasonRegistration})
+		replay.final[seed.AthleteId] = seed
+	}
+
+	for _, outcome := range outcomes {
+		if outcome.OutcomeId == excludeOutcomeId {
+			continue
+		}
+
+		if outcome.ConfirmedDate == nil {
+			return styleReplay{}, fmt.Errorf("outcome %d has no confirmed date", outcome.OutcomeId)
+		}
+		playedAt, err := time.Parse(time.RFC3339Nano, *outcome.ConfirmedDate)
+		if err != nil {
+			return styleReplay{}, fmt.Errorf("invalid confirmed date on outcome %d: %w", outcome.OutcomeId, err)
+		}
+
+		// Resets and decays recorded before the outcome change the scores it is rated from
+		if err := replay.applyScheduledChanges(playedAt); err != nil {
+			return styleReplay{}, err
+		}
+
+		winner, winnerFound := replay.final[outcome.WinnerId]
+		loser, loserFound := replay.final[outcome.LoserId]
+		if !winnerFound || !loserFound {
+			log.Printf("Skipping outcome %d in replay: missing winner or loser", outcome.OutcomeId)
+			replay.skipped++
+			continue
+		}
+
+		replay.replayed++
+		if !outcome.Rated {
+			continue
+		}
+
+		pair := newRematchPair(outcome.WinnerId, outcome.LoserId)
+		result, err := s.athleteScoreService.rateOutcome(rating, models.RatingInput{
+			Winner:       winner,
+			Loser:        loser,
+			IsDraw:       outcome.IsDraw,
+			FinishMethod: outcome.FinishMethod,
+			ScoreMargin:  outcome.ScoreMargin,
+			PlayedAt:     playedAt,
+			Rematch:      rating.rematch.history(ratedAt[pair], playedAt),
+		})
+		if err != nil {
+			return styleReplay{}, err
+		}
+		if result.Unrated {
+			continue
+		}
+		ratedAt[pair] = append(ratedAt[pair], playedAt)
+
+		for _, rated := range ratedChanges(&result) {
+			change := *rated
+			change.OutcomeId = outcome.OutcomeId
+			change.CreatedDate = *outcome.ConfirmedDate
+			change.UpdatedDate = *outcome.ConfirmedDate
+			replay.rows = append(replay.rows, change)
+			replay.final[change.AthleteId] = change.AthleteScore
+		}
+	}
+
+	if err := replay.applyScheduledChanges(until); err != nil {
+		return styleReplay{}, err
+	}
+
+	return replay, nil
+}
+
+// applyScheduledChanges reapplies, in time order, every season reset and decay recorded before the
+// given time. A season reset goes first when both happened at the same moment.
+func (r *styleReplay) applyScheduledChanges(before time.Time) error {
+	for {
+		var seasonAt, decayAt time.Time
+		var err error
+		if len(r.seasons) > 0 {
+			seasonAt, err = time.Parse(time.RFC3339Nano, *r.seasons[0].ClosedDate)
+			if err != nil {
+				return fmt.Errorf("invalid closed date on season %d: %w", r.seasons[0].SeasonId, err)
+			}
+		}
+		if len(r.decays) > 0 {
+			decayAt, err = time.Parse(time.RFC3339Nano, r.decays[0].CreatedDate)
+			if err != nil {
+				return fmt.Errorf("invalid date on decay %d: %w", r.decays[0].HistoryId, err)
+			}
+		}
+
+		seasonDue := len(r.seasons) > 0 && seasonAt.Before(before)
+		decayDue := len(r.decays) > 0 && decayAt.Before(before)
+		switch {
+		case seasonDue && (!decayDue || !decayAt.Before(seasonAt)):
+			if err := r.applySeasonReset(r.seasons[0], seasonAt); err != nil {
+				return err
+			}
+			r.seasons = r.seasons[1:]
+		case decayDue:
+			r.applyDecay(r.decays[0])
+			r.decays = r.decays[1:]
+		default:
+			return nil
+		}
+	}
+}
+
+// applySeasonReset resets the replayed ratings of every athlete in the style when a season closed,
+// using the mean and weight recorded at close
+func (r *styleReplay) applySeasonReset(season models.Season, closedAt time.Time) error {
+	athleteIds := make([]int, 0, len(r.final))
+	for athleteId := range r.final {
+		athleteIds = append(athleteIds, athleteId)
+	}
+	sort.Ints(athleteIds)
+
+	reset := seasonReset(season)
+	for _, athleteId := range athleteIds {
+		score := r.final[athleteId]
+		lastChanged, err := time.Parse(time.RFC3339Nano, score.UpdatedDate)
+		if err != nil {
+			return fmt.Errorf("invalid updated date for athlete %d: %w", athleteId, err)
+		}
+		if lastChanged.After(closedAt) {
+			// The athlete joined the style after this season closed
+			continue
+		}
+
+		change := models.RatingChange{AthleteScore: score, Reason: models.ScoreReasonSeasonReset}
+		change.OutcomeId = 0
+		change.Score = reset.apply(score.Score)
+		change.CreatedDate = *season.ClosedDate
+		change.UpdatedDate = *season.ClosedDate
+		r.rows = append(r.rows, change)
+		r.final[athleteId] = change.AthleteScore
+	}
+	return nil
+}
+
+// applyDecay lowers an athlete's replayed rating by the points a recorded decay took off, never
+// below the style's decay floor, and flags them as provisional as the decay job did
+func (r *styleReplay) applyDecay(decay models.AthleteScoreHistory) {
+	score, found := r.final[decay.AthleteId]
+	if !found {
+		return
+	}
+
+	change := models.RatingChange{AthleteScore: score, Reason: models.ScoreReasonDecay}
+	change.OutcomeId = 0
+	change.Score = math.Min(math.Max(score.Score-(decay.PreviousScore-decay.NewScore), r.decayFloor), score.Score)
+	change.Provisional = true
+	change.CreatedDate = decay.CreatedDate
+	change.UpdatedDate = decay.CreatedDate
+	r.rows = append(r.rows, change)
+	r.final[decay.AthleteId] = change.AthleteScore
+}
+
+// diffStyle compares the replayed ratings of a style with the ones currently stored, read through tx
+func (s *RatingReplayService) diffStyle(tx *repositories.Tx, styleId int, replayed map[int]models.AthleteScore) ([]models.RatingReplayChange, error) {
+	current, err := s.scoreRepo.GetStyleScoresForReplay(tx, styleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current scores for style %d: %w", styleId, err)
+	}
+
+	changesByAthlete := make(map[int]models.RatingReplayChange)
+	for _, score := range current {
+		changesByAthlete[score.AthleteId] = models.RatingReplayChange{
+			AthleteId:              score.AthleteId,
+			StyleId:                styleId,
+			CurrentScore:           score.Score,
+			CurrentRatingDeviation: score.RatingDeviation,
+		}
+	}
+	for _, score := range replayed {
+		change := changesByAthlete[score.AthleteId]
+		change.AthleteId = score.AthleteId
+		change.StyleId = styleId
+		change.ReplayedScore = score.Score
+		change.ReplayedRatingDeviation = score.RatingDeviation
+		changesByAthlete[score.AthleteId] = change
+	}
+
+	changes := make([]models.RatingReplayChange, 0, len(changesByAthlete))
+	for _, change := range changesByAthlete {
+		change.ScoreDelta = change.ReplayedScore - change.CurrentScore
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].AthleteId < changes[j].AthleteId
+	})
+	return changes, nil
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+// replayTestStart is when every athlete in the replay tests registered to the style
+var replayTestStart = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// liveEvent is one change the live services make to a style's ratings: confirming an outcome,
+// closing a season or decaying an inactive athlete
+type liveEvent struct {
+	at        time.Time
+	outcome   *models.Outcome
+	season    *models.Season
+	decayedId int
+}
+
+// liveHistory is what the live services leave behind after a run of events: the current scores and
+// the rows a replay reads back
+type liveHistory struct {
+	final    map[int]models.AthleteScore
+	seeds    []models.AthleteScore
+	outcomes []models.Outcome
+	seasons  []models.Season
+	decays   []models.AthleteScoreHistory
+}
+
+func TestReplayMatchesLiveRatings(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   string
+		settings string
+	}{
+		{name: "elo", engine: EngineElo},
+		{name: "elo with k-factor policy", engine: EngineElo, settings: `{"kFactor": 32, "provisionalBouts": 2, "provisionalKFactor": 48, "experienceBands": [{"minBouts": 3, "kFactor": 24}]}`},
+		{name: "elo with margin of victory", engine: EngineElo, settings: `{"finishMultipliers": {"submission": 1.5}, "marginStep": 0.1, "forfeitRule": "loser_only"}`},
+		{name: "elo with rematch limits", engine: EngineElo, settings: `{"maxRatedRematches": 2, "rematchPeriodHours": 720, "rematchDecay": 0.5}`},
+		{name: "elo with decay and seasons", engine: EngineElo, settings: `{"decayInactiveDays": 30, "decayPoints": 15, "decayFloor": 390, "seasonResetWeight": 0.25}`},
+		{name: "glicko-2", engine: EngineGlicko2},
+		{name: "glicko-2 with decay and seasons", engine: EngineGlicko2, settings: `{"decayInactiveDays": 30, "decayPoints": 15, "seasonResetMean": 420}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating := testStyleRating(t, tt.engine, tt.settings)
+			live := rateLive(t, rating, replayTestEvents())
+
+			replay, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, 0, replayTestEnd())
+			if err != nil {
+				t.Fatalf("replayOutcomes() error = %v", err)
+			}
+			assertSameRatings(t, replay.final, live.final)
+		})
+	}
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	rating := testStyleRating(t, EngineGlicko2, `{"decayInactiveDays": 30, "decayPoints": 15}`)
+	live := rateLive(t, rating, replayTestEvents())
+
+	first, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, 0, replayTestEnd())
+	if err != nil {
+		t.Fatalf("replayOutcomes() error = %v", err)
+	}
+	second, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, 0, replayTestEnd())
+	if err != nil {
+		t.Fatalf("replayOutcomes() error = %v", err)
+	}
+	if !reflect.DeepEqual(first.rows, second.rows) {
+		t.Errorf("replaying the same history twice wrote different rows")
+	}
+}
+
+func TestVoidMatchesReplayWithout(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   string
+		settings string
+		voidId   int
+	}{
+		{name: "elo first outcome", engine: EngineElo, voidId: 1},
+		{name: "elo middle outcome", engine: EngineElo, settings: `{"maxRatedRematches": 2, "rematchPeriodHours": 720}`, voidId: 3},
+		{name: "elo last outcome", engine: EngineElo, voidId: 6},
+		{name: "elo with decay and seasons", engine: EngineElo, settings: `{"decayInactiveDays": 30, "decayPoints": 15, "seasonResetWeight": 0.25}`, voidId: 2},
+		{name: "glicko-2 with decay and seasons", engine: EngineGlicko2, settings: `{"decayInactiveDays": 30, "decayPoints": 15}`, voidId: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating := testStyleRating(t, tt.engine, tt.settings)
+			live := rateLive(t, rating, replayTestEvents())
+
+			voided, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, tt.voidId, replayTestEnd())
+			if err != nil {
+				t.Fatalf("replayOutcomes() error = %v", err)
+			}
+
+			var without []models.Outcome
+			for _, outcome := range live.outcomes {
+				if outcome.OutcomeId != tt.voidId {
+					without = append(without, outcome)
+				}
+			}
+			replayed, err := testReplayService().replayOutcomes(rating, live.seeds, without, live.seasons, live.decays, 0, replayTestEnd())
+			if err != nil {
+				t.Fatalf("replayOutcomes() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(voided.rows, replayed.rows) {
+				t.Errorf("voiding outcome %d wrote different rows than replaying without it", tt.voidId)
+			}
+			assertSameRatings(t, voided.final, replayed.final)
+
+			// Only the outcome's own contribution goes; every season reset and decay stays
+			for _, reason := range []string{models.ScoreReasonSeasonReset, models.ScoreReasonDecay} {
+				if got, want := countReason(voided.rows, reason), countReason(replayOfLive(t, rating, live).rows, reason); got != want {
+					t.Errorf("void kept %d %s rows, want %d", got, reason, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayDecayRespectsFloor(t *testing.T) {
+	rating := testStyleRating(t, EngineElo, `{"decayInactiveDays": 30, "decayPoints": 15, "decayFloor": 395}`)
+	live := rateLive(t, rating, replayTestEvents())
+
+	for _, voidId := range []int{0, 1, 2, 5} {
+		replay, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, voidId, replayTestEnd())
+		if err != nil {
+			t.Fatalf("replayOutcomes() error = %v", err)
+		}
+
+		previous := make(map[int]float64)
+		for _, row := range replay.rows {
+			if row.Reason == models.ScoreReasonDecay {
+				before := previous[row.AthleteId]
+				if row.Score > before {
+					t.Errorf("without outcome %d, decay raised athlete %d from %v to %v", voidId, row.AthleteId, before, row.Score)
+				}
+				if row.Score < rating.decay.DecayFloor && row.Score != before {
+					t.Errorf("without outcome %d, decay took athlete %d from %v to %v, below the floor", voidId, row.AthleteId, before, row.Score)
+				}
+				if !row.Provisional {
+					t.Errorf("without outcome %d, decay did not flag athlete %d provisional", voidId, row.AthleteId)
+				}
+			}
+			previous[row.AthleteId] = row.Score
+		}
+	}
+}
+
+// replayTestEvents is a season of bouts between three athletes with a season close and decay of
+// the athlete who stopped competing
+func replayTestEvents() []liveEvent {
+	day := func(days int) time.Time {
+		return replayTestStart.AddDate(0, 0, days)
+	}
+	outcome := func(id, winner, loser int, isDraw bool, finish string, margin int) *models.Outcome {
+		return &models.Outcome{OutcomeId: id, WinnerId: winner, LoserId: loser, StyleId: 1, IsDraw: isDraw,
+			FinishMethod: finish, ScoreMargin: margin, Rated: true}
+	}
+
+	return []liveEvent{
+		{at: day(1), outcome: outcome(1, 1, 3, false, models.FinishSubmission, 0)},
+		{at: day(2), outcome: outcome(2, 1, 2, false, models.FinishPoints, 4)},
+		{at: day(3), outcome: outcome(3, 2, 1, false, models.FinishDecision, 1)},
+		{at: day(4), outcome: outcome(4, 1, 2, true, models.FinishDecision, 0)},
+		{at: day(40), decayedId: 3},
+		{at: day(45), season: &models.Season{SeasonId: 1, StyleId: 1}},
+		{at: day(50), outcome: outcome(5, 2, 1, false, models.FinishForfeit, 0)},
+		{at: day(70), decayedId: 3},
+		{at: day(75), outcome: outcome(6, 1, 2, false, models.FinishSubmission, 2)},
+	}
+}
+
+// replayTestEnd is when the replay tests rebuild the ratings
+func replayTestEnd() time.Time {
+	return replayTestStart.AddDate(0, 1, 90)
+}
+
+// rateLive applies events the way the live services do: each outcome is rated from the athletes'
+// current scores, each season close soft-resets every rating and each decay lowers one rating
+func rateLive(t *testing.T, rating styleRating, events []liveEvent) liveHistory {
+	t.Helper()
+	live := liveHistory{final: make(map[int]models.AthleteScore)}
+	registered := replayTestStart.Format(time.RFC3339Nano)
+	for _, athleteId := range []int{1, 2, 3} {
+		seed := models.AthleteScore{AthleteId: athleteId, StyleId: 1, CreatedDate: registered, UpdatedDate: registered}
+		live.seeds = append(live.seeds, seed)
+
+		seed.Score = repositories.DefaultScore
+		seed.RatingDeviation = repositories.DefaultRatingDeviation
+		seed.Volatility = repositories.DefaultVolatility
+		live.final[athleteId] = seed
+	}
+
+	scores := &AthleteScoreService{}
+	ratedAt := make(map[rematchPair][]time.Time)
+	for _, event := range events {
+		at := event.at.Format(time.RFC3339Nano)
+		switch {
+		case event.outcome != nil:
+			outcome := *event.outcome
+			outcome.ConfirmedDate = &at
+			live.outcomes = append(live.outcomes, outcome)
+
+			pair := newRematchPair(outcome.WinnerId, outcome.LoserId)
+			result, err := scores.rateOutcome(rating, models.RatingInput{
+				Winner:       live.final[outcome.WinnerId],
+				Loser:        live.final[outcome.LoserId],
+				IsDraw:       outcome.IsDraw,
+				FinishMethod: outcome.FinishMethod,
+				ScoreMargin:  outcome.ScoreMargin,
+				PlayedAt:     event.at,
+				Rematch:      rating.rematch.history(ratedAt[pair], event.at),
+			})
+			if err != nil {
+				t.Fatalf("rateOutcome() error = %v", err)
+			}
+			if result.Unrated {
+				continue
+			}
+			ratedAt[pair] = append(ratedAt[pair], event.at)
+			for _, change := range []models.RatingChange{result.Winner, result.Loser} {
+				change.OutcomeId = outcome.OutcomeId
+				change.UpdatedDate = at
+				live.final[change.AthleteId] = change.AthleteScore
+			}
+
+		case event.season != nil:
+			season := *event.season
+			season.ClosedDate = &at
+			season.ResetMean = rating.season.SeasonResetMean
+			season.ResetWeight = rating.season.SeasonResetWeight
+			live.seasons = append(live.seasons, season)
+
+			for athleteId, score := range live.final {
+				score.Score = rating.season.apply(score.Score)
+				score.OutcomeId = 0
+				score.UpdatedDate = at
+				live.final[athleteId] = score
+			}
+
+		default:
+			if !rating.decay.enabled() {
+				continue
+			}
+			score := live.final[event.decayedId]
+			newScore := math.Min(math.Max(score.Score-rating.decay.DecayPoints, rating.decay.DecayFloor), score.Score)
+			live.decays = append(live.decays, models.AthleteScoreHistory{
+				AthleteId:     event.decayedId,
+				StyleId:       1,
+				PreviousScore: score.Score,
+				NewScore:      newScore,
+				Reason:        models.ScoreReasonDecay,
+				CreatedDate:   at,
+			})
+
+			score.Score = newScore
+			score.Provisional = true
+			score.OutcomeId = 0
+			score.UpdatedDate = at
+			live.final[event.decayedId] = score
+		}
+	}
+	return live
+}
+
+// replayOfLive replays the whole of a live history
+func replayOfLive(t *testing.T, rating styleRating, live liveHistory) styleReplay {
+	t.Helper()
+	replay, err := testReplayService().replayOutcomes(rating, live.seeds, live.outcomes, live.seasons, live.decays, 0, replayTestEnd())
+	if err != nil {
+		t.Fatalf("replayOutcomes() error = %v", err)
+	}
+	return replay
+}
+
+// testStyleRating builds a style's rating rules from an engine name and raw settings
+func testStyleRating(t *testing.T, engine string, settings string) styleRating {
+	t.Helper()
+	config := models.StyleRatingConfig{StyleId: 1, Engine: engine}
+	if settings != "" {
+		config.Settings = json.RawMessage(settings)
+	}
+	rating, err := newStyleRating(config)
+	if err != nil {
+		t.Fatalf("newStyleRating() error = %v", err)
+	}
+	return rating
+}
+
+// testReplayService returns a replay service for the in-memory parts of a replay, which need no repositories
+func testReplayService() *RatingReplayService {
+	return &RatingReplayService{athleteScoreService: &AthleteScoreService{}}
+}
+
+// assertSameRatings compares every athlete's rating, deviation, volatility, bout count and provisional flag
+func assertSameRatings(t *testing.T, got, want map[int]models.AthleteScore) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got ratings for %d athletes, want %d", len(got), len(want))
+	}
+	for athleteId, wantScore := range want {
+		gotScore, found := got[athleteId]
+		if !found {
+			t.Errorf("no rating for athlete %d", athleteId)
+			continue
+		}
+		if math.Abs(gotScore.Score-wantScore.Score) > ratingTolerance ||
+			math.Abs(gotScore.RatingDeviation-wantScore.RatingDeviation) > ratingTolerance ||
+			math.Abs(gotScore.Volatility-wantScore.Volatility) > 0.000001 {
+			t.Errorf("athlete %d rated %v (deviation %v, volatility %v), want %v (deviation %v, volatility %v)", athleteId,
+				gotScore.Score, gotScore.RatingDeviation, gotScore.Volatility, wantScore.Score, wantScore.RatingDeviation, wantScore.Volatility)
+		}
+		if gotScore.RatedBouts != wantScore.RatedBouts {
+			t.Errorf("athlete %d has %d rated bouts, want %d", athleteId, gotScore.RatedBouts, wantScore.RatedBouts)
+		}
+		if gotScore.Provisional != wantScore.Provisional {
+			t.Errorf("athlete %d provisional = %v, want %v", athleteId, gotScore.Provisional, wantScore.Provisional)
+		}
+	}
+}
+
+// countReason counts the replayed rows written for a reason
+func countReason(rows []models.RatingChange, reason string) int {
+	count := 0
+	for _, row := range rows {
+		if row.Reason == reason {
+			count++
+		}
+	}
+	return count
+}
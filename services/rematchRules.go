@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"ronin/models"
+)
+
+// Rules for rated bouts that break a style's rematch limits
+const (
+	// RematchRuleUnrated allows the bout but leaves both ratings unchanged
+	RematchRuleUnrated = "unrated"
+	// RematchRuleReject refuses to create the bout
+	RematchRuleReject = "reject"
+)
+
+// RematchSettings stop the same pair of athletes from farming rating off each other. They are read
+// from the same style_rating_config settings as the engine and apply to every engine.
+type RematchSettings struct {
+	MaxRatedRematches    int     `json:"maxRatedRematches"`
+	RematchPeriodHours   int     `json:"rematchPeriodHours"`
+	RematchCooldownHours int     `json:"rematchCooldownHours"`
+	RematchDecay         float64 `json:"rematchDecay"`
+	RematchOverLimitRule string  `json:"rematchOverLimitRule"`
+}
+
+// DefaultRematchSettings returns settings that rate every rematch in full
+func DefaultRematchSettings() RematchSettings {
+	return RematchSettings{
+		MaxRatedRematches:    0,
+		RematchPeriodHours:   168,
+		RematchCooldownHours: 0,
+		RematchDecay:         1.0,
+		RematchOverLimitRule: RematchRuleUnrated,
+	}
+}
+
+// validate checks the rematch settings for a style
+func (r RematchSettings) validate() error {
+	if r.MaxRatedRematches < 0 {
+		return errors.New("max rated rematches cannot be negative")
+	}
+	if r.RematchPeriodHours <= 0 {
+		return errors.New("rematch period hours must be greater than zero")
+	}
+	if r.RematchCooldownHours < 0 {
+		return errors.New("rematch cooldown hours cannot be negative")
+	}
+	if r.RematchDecay <= 0 || r.RematchDecay > 1 {
+		return errors.New("rematch decay must be greater than zero and at most 1")
+	}
+	if r.RematchOverLimitRule != RematchRuleUnrated && r.RematchOverLimitRule != RematchRuleReject {
+		return fmt.Errorf("unknown rematch over-limit rule %q", r.RematchOverLimitRule)
+	}
+	return nil
+}
+
+// periodStart is the start of the window rated rematches are counted in
+func (r RematchSettings) periodStart(now time.Time) time.Time {
+	return now.Add(-time.Duration(r.RematchPeriodHours) * time.Hour)
+}
+
+// history summarises the times a pair's earlier bouts were rated, oldest first, as seen at now
+func (r RematchSettings) history(ratedAt []time.Time, now time.Time) models.RematchHistory {
+	var history models.RematchHistory
+	since := r.periodStart(now)
+	for i := range ratedAt {
+		if !ratedAt[i].Before(since) {
+			history.RatedInPeriod++
+		}
+		history.LastRatedAt = &ratedAt[i]
+	}
+	return history
+}
+
+// violation explains why another rated bout between a pair would break the limits, or returns an
+// empty string when it would not
+func (r RematchSettings) violation(history models.RematchHistory, now time.Time) string {
+	if r.MaxRatedRematches > 0 && history.RatedInPeriod >= r.MaxRatedRematches {
+		return fmt.Sprintf("these athletes already had %d rated bouts in the last %d hours", history.RatedInPeriod, r.RematchPeriodHours)
+	}
+	if r.RematchCooldownHours > 0 && history.LastRatedAt != nil {
+		cooldownEnds := history.LastRatedAt.Add(time.Duration(r.RematchCooldownHours) * time.Hour)
+		if now.Before(cooldownEnds) {
+			return fmt.Sprintf("these athletes had a rated bout less than %d hours ago", r.RematchCooldownHours)
+		}
+	}
+	return ""
+}
+
+// multiplierFor returns the factor a rematch scales the rating change by, shrinking with every
+// rated bout the pair already had in the period
+func (r RematchSettings) multiplierFor(history models.RematchHistory) float64 {
+	return math.Pow(r.RematchDecay, float64(history.RatedInPeriod))
+}
+
+// apply leaves over-limit rematches unrated and scales the rating change of the others
+func (r RematchSettings) apply(input models.RatingInput, result models.RatingResult) models.RatingResult {
+	if r.violation(input.Rematch, input.PlayedAt) != "" {
+		result.Unrated = true
+		return result
+	}
+
+	multiplier := r.multiplierFor(input.Rematch)
+	result.Winner.Score = input.Winner.Score + (result.Winner.Score-input.Winner.Score)*multiplier
+	result.Loser.Score = input.Loser.Score + (result.Loser.Score-input.Loser.Score)*multiplier
+	return result
+}
+
+// RematchLimitError is returned when a style rejects a rated bout that breaks its rematch limits
+type RematchLimitError struct {
+	StyleId int
+	Reason  string
+}
+
+func (e *RematchLimitError) Error() string {
+	return fmt.Sprintf("rematch limit reached in style %d: %s", e.StyleId, e.Reason)
+}
+
+// rematchPair is an unordered pair of athletes
+type rematchPair [2]int
+
+// newRematchPair orders two athlete IDs so both sides of a bout map to the same pair
+func newRematchPair(athleteA, athleteB int) rematchPair {
+	if athleteA > athleteB {
+		athleteA, athleteB = athleteB, athleteA
+	}
+	return rematchPair{athleteA, athleteB}
+}
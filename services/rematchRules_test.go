@@ -0,0 +1,160 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ronin/models"
+)
+
+func TestRematchViolation(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	hoursAgo := func(hours int) *time.Time {
+		at := now.Add(-time.Duration(hours) * time.Hour)
+		return &at
+	}
+
+	tests := []struct {
+		name      string
+		settings  RematchSettings
+		history   models.RematchHistory
+		wantRated bool
+	}{
+		{name: "defaults never limit", settings: DefaultRematchSettings(), history: models.RematchHistory{RatedInPeriod: 50, LastRatedAt: hoursAgo(1)}, wantRated: true},
+		{name: "first bout", settings: RematchSettings{MaxRatedRematches: 2, RematchPeriodHours: 168}, wantRated: true},
+		{name: "under the limit", settings: RematchSettings{MaxRatedRematches: 2, RematchPeriodHours: 168}, history: models.RematchHistory{RatedInPeriod: 1, LastRatedAt: hoursAgo(10)}, wantRated: true},
+		{name: "at the limit", settings: RematchSettings{MaxRatedRematches: 2, RematchPeriodHours: 168}, history: models.RematchHistory{RatedInPeriod: 2, LastRatedAt: hoursAgo(10)}, wantRated: false},
+		{name: "inside the cooldown", settings: RematchSettings{RematchPeriodHours: 168, RematchCooldownHours: 24}, history: models.RematchHistory{RatedInPeriod: 1, LastRatedAt: hoursAgo(23)}, wantRated: false},
+		{name: "cooldown just ended", settings: RematchSettings{RematchPeriodHours: 168, RematchCooldownHours: 24}, history: models.RematchHistory{RatedInPeriod: 1, LastRatedAt: hoursAgo(24)}, wantRated: true},
+		{name: "cooldown without an earlier bout", settings: RematchSettings{RematchPeriodHours: 168, RematchCooldownHours: 24}, wantRated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.settings.violation(tt.history, now)
+			if (reason == "") != tt.wantRated {
+				t.Errorf("violation() = %q, want rated %v", reason, tt.wantRated)
+			}
+		})
+	}
+}
+
+func TestRematchHistory(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	settings := RematchSettings{RematchPeriodHours: 24}
+
+	tests := []struct {
+		name          string
+		ratedAt       []time.Time
+		wantInPeriod  int
+		wantLastRated *time.Time
+	}{
+		{name: "never rated"},
+		{name: "outside the period", ratedAt: []time.Time{now.Add(-48 * time.Hour)}, wantInPeriod: 0, wantLastRated: timePtr(now.Add(-48 * time.Hour))},
+		{name: "period start is included", ratedAt: []time.Time{now.Add(-24 * time.Hour)}, wantInPeriod: 1, wantLastRated: timePtr(now.Add(-24 * time.Hour))},
+		{
+			name:          "mixed",
+			ratedAt:       []time.Time{now.Add(-72 * time.Hour), now.Add(-12 * time.Hour), now.Add(-1 * time.Hour)},
+			wantInPeriod:  2,
+			wantLastRated: timePtr(now.Add(-1 * time.Hour)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := settings.history(tt.ratedAt, now)
+			if history.RatedInPeriod != tt.wantInPeriod {
+				t.Errorf("RatedInPeriod = %d, want %d", history.RatedInPeriod, tt.wantInPeriod)
+			}
+			if (history.LastRatedAt == nil) != (tt.wantLastRated == nil) ||
+				(history.LastRatedAt != nil && !history.LastRatedAt.Equal(*tt.wantLastRated)) {
+				t.Errorf("LastRatedAt = %v, want %v", history.LastRatedAt, tt.wantLastRated)
+			}
+		})
+	}
+}
+
+func TestRematchApply(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	input := models.RatingInput{
+		Winner:   models.AthleteScore{AthleteId: 1, Score: 400},
+		Loser:    models.AthleteScore{AthleteId: 2, Score: 400},
+		PlayedAt: now,
+	}
+	engineResult := models.RatingResult{
+		Winner: models.RatingChange{AthleteScore: models.AthleteScore{AthleteId: 1, Score: 416}},
+		Loser:  models.RatingChange{AthleteScore: models.AthleteScore{AthleteId: 2, Score: 384}},
+	}
+
+	tests := []struct {
+		name        string
+		settings    RematchSettings
+		rematch     models.RematchHistory
+		wantUnrated bool
+		wantWinner  float64
+		wantLoser   float64
+	}{
+		{name: "first bout in full", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 0.5}, wantWinner: 416, wantLoser: 384},
+		{name: "second bout halved", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 0.5}, rematch: models.RematchHistory{RatedInPeriod: 1}, wantWinner: 408, wantLoser: 392},
+		{name: "third bout quartered", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 0.5}, rematch: models.RematchHistory{RatedInPeriod: 2}, wantWinner: 404, wantLoser: 396},
+		{name: "over the limit", settings: RematchSettings{MaxRatedRematches: 2, RematchPeriodHours: 168, RematchDecay: 0.5}, rematch: models.RematchHistory{RatedInPeriod: 2}, wantUnrated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := input
+			input.Rematch = tt.rematch
+			result := tt.settings.apply(input, engineResult)
+			if result.Unrated != tt.wantUnrated {
+				t.Fatalf("Unrated = %v, want %v", result.Unrated, tt.wantUnrated)
+			}
+			if tt.wantUnrated {
+				return
+			}
+			if math.Abs(result.Winner.Score-tt.wantWinner) > ratingTolerance {
+				t.Errorf("winner score = %v, want %v", result.Winner.Score, tt.wantWinner)
+			}
+			if math.Abs(result.Loser.Score-tt.wantLoser) > ratingTolerance {
+				t.Errorf("loser score = %v, want %v", result.Loser.Score, tt.wantLoser)
+			}
+		})
+	}
+}
+
+func TestRematchSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings RematchSettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: DefaultRematchSettings()},
+		{name: "negative max rematches", settings: RematchSettings{MaxRatedRematches: -1, RematchPeriodHours: 168, RematchDecay: 1, RematchOverLimitRule: RematchRuleUnrated}, wantErr: true},
+		{name: "zero period", settings: RematchSettings{RematchPeriodHours: 0, RematchDecay: 1, RematchOverLimitRule: RematchRuleUnrated}, wantErr: true},
+		{name: "negative cooldown", settings: RematchSettings{RematchPeriodHours: 168, RematchCooldownHours: -1, RematchDecay: 1, RematchOverLimitRule: RematchRuleUnrated}, wantErr: true},
+		{name: "zero decay", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 0, RematchOverLimitRule: RematchRuleUnrated}, wantErr: true},
+		{name: "decay above one", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 1.5, RematchOverLimitRule: RematchRuleUnrated}, wantErr: true},
+		{name: "reject rule", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 1, RematchOverLimitRule: RematchRuleReject}},
+		{name: "unknown rule", settings: RematchSettings{RematchPeriodHours: 168, RematchDecay: 1, RematchOverLimitRule: "ignore"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRematchPair(t *testing.T) {
+	if newRematchPair(7, 3) != newRematchPair(3, 7) {
+		t.Errorf("newRematchPair(7, 3) = %v, want %v", newRematchPair(7, 3), newRematchPair(3, 7))
+	}
+}
+
+// timePtr returns a pointer to a copy of t
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ronin/models"
+	"ronin/repositories"
+
+	"github.com/gorilla/mux"
+)
+
+// SeasonHandler handles HTTP requests for seasons and their archived standings
+type SeasonHandler struct {
+	service *SeasonService
+}
+
+// NewSeasonHandler creates a new instance of SeasonHandler
+func NewSeasonHandler(service *SeasonService) *SeasonHandler {
+	return &SeasonHandler{
+		service: service,
+	}
+}
+
+// CreateSeason handles POST requests to add a season to a style
+func (h *SeasonHandler) CreateSeason(w http.ResponseWriter, r *http.Request) {
+	styleId, err := strconv.Atoi(mux.Vars(r)["style_id"])
+	if err != nil {
+		SendError(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	var season models.Season
+	if err := json.NewDecoder(r.Body).Decode(&season); err != nil {
+		SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	season.StyleId = styleId
+
+	created, err := h.service.CreateSeason(season)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	SendJSON(w, created)
+}
+
+// GetSeasonsByStyle handles GET requests for every season of a style
+func (h *SeasonHandler) GetSeasonsByStyle(w http.ResponseWriter, r *http.Request) {
+	styleId, err := strconv.Atoi(mux.Vars(r)["style_id"])
+	if err != nil {
+		SendError(w, "Invalid style_id", http.StatusBadRequest)
+		return
+	}
+
+	seasons, err := h.service.GetSeasonsByStyle(styleId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, seasons)
+}
+
+// GetSeason handles GET requests for a single season
+func (h *SeasonHandler) GetSeason(w http.ResponseWriter, r *http.Request) {
+	seasonId, err := strconv.Atoi(mux.Vars(r)["season_id"])
+	if err != nil {
+		SendError(w, "Invalid season_id", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.service.GetSeason(seasonId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	SendJSON(w, season)
+}
+
+// CloseSeason handles POST requests to archive a season's standings and soft-reset its ratings.
+// Only an admin can close a season early.
+func (h *SeasonHandler) CloseSeason(w http.ResponseWriter, r *http.Request) {
+	seasonId, err := strconv.Atoi(mux.Vars(r)["season_id"])
+	if err != nil {
+		SendError(w, "Invalid season_id", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.service.CloseSeason(seasonId, authenticatedAthleteId(r))
+	if errors.Is(err, repositories.ErrSeasonAlreadyClosed) {
+		SendError(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		SendError(w, err.Error(), authorizationErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+	SendJSON(w, season)
+}
+
+// GetSeasonStandings handles GET requests for the archived final standings of a season
+func (h *SeasonHandler) GetSeasonStandings(w http.ResponseWriter, r *http.Request) {
+	seasonId, err := strconv.Atoi(mux.Vars(r)["season_id"])
+	if err != nil {
+		SendError(w, "Invalid season_id", http.StatusBadRequest)
+		return
+	}
+
+	standings, err := h.service.GetSeasonStandings(seasonId)
+	if err != nil {
+		SendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	SendJSON(w, standings)
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"ronin/models"
+	"ronin/repositories"
+)
+
+const seasonDateLayout = "2006-01-02"
+
+// SeasonResetSettings control how ratings are pulled back toward a mean when a season closes.
+// They are read from the style's rating config settings. A weight of 0 keeps ratings as they are,
+// a weight of 1 resets everyone to the mean.
+type SeasonResetSettings struct {
+	SeasonResetMean   float64 `json:"seasonResetMean"`
+	SeasonResetWeight float64 `json:"seasonResetWeight"`
+}
+
+// DefaultSeasonResetSettings returns settings that halve each athlete's distance from the starting score
+func DefaultSeasonResetSettings() SeasonResetSettings {
+	return SeasonResetSettings{
+		SeasonResetMean:   repositories.DefaultScore,
+		SeasonResetWeight: 0.5,
+	}
+}
+
+// validate checks the season reset settings for a style
+func (s SeasonResetSettings) validate() error {
+	if s.SeasonResetWeight < 0 || s.SeasonResetWeight > 1 {
+		return errors.New("season reset weight must be between 0 and 1")
+	}
+	return nil
+}
+
+// apply returns a score moved toward the reset mean by the reset weight
+func (s SeasonResetSettings) apply(score float64) float64 {
+	return s.SeasonResetMean + (score-s.SeasonResetMean)*(1-s.SeasonResetWeight)
+}
+
+// SeasonService manages seasons and archives their final standings when they close
+type SeasonService struct {
+	athleteScoreService  *AthleteScoreService
+	overallRatingService *OverallRatingService
+	scoreRepo            *repositories.AthleteScoreRepository
+	seasonRepo           *repositories.SeasonRepository
+}
+
+// NewSeasonService creates a new instance of SeasonService
+func NewSeasonService(
+	athleteScoreService *AthleteScoreService,
+	overallRatingService *OverallRatingService,
+	scoreRepo *repositories.AthleteScoreRepository,
+	seasonRepo *repositories.SeasonRepository,
+) *SeasonService {
+	return &SeasonService{
+		athleteScoreService:  athleteScoreService,
+		overallRatingService: overallRatingService,
+		scoreRepo:            scoreRepo,
+		seasonRepo:           seasonRepo,
+	}
+}
+
+// CreateSeason validates and stores a new season. Seasons in the same style cannot overlap.
+func (s *SeasonService) CreateSeason(season models.Season) (models.Season, error) {
+	season.SeasonName = strings.TrimSpace(season.SeasonName)
+	if season.StyleId == 0 {
+		return models.Season{}, errors.New("style ID is required")
+	}
+	if season.SeasonName == "" {
+		return models.Season{}, errors.New("season name is required")
+	}
+
+	start, err := time.Parse(seasonDateLayout, season.StartDate)
+	if err != nil {
+		return models.Season{}, errors.New("start date must be formatted as YYYY-MM-DD")
+	}
+	end, err := time.Parse(seasonDateLayout, season.EndDate)
+	if err != nil {
+		return models.Season{}, errors.New("end date must be formatted as YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return models.Season{}, errors.New("end date cannot be before start date")
+	}
+
+	overlaps, err := s.seasonRepo.HasOverlappingSeason(season.StyleId, season.StartDate, season.EndDate)
+	if err != nil {
+		return models.Season{}, fmt.Errorf("failed to check for overlapping seasons: %w", err)
+	}
+	if overlaps {
+		return models.Season{}, errors.New("season overlaps an existing season for this style")
+	}
+
+	seasonId, err := s.seasonRepo.CreateSeason(season)
+	if err != nil {
+		return models.Season{}, fmt.Errorf("failed to create season: %w", err)
+	}
+	return s.GetSeason(seasonId)
+}
+
+// GetSeason returns a single season
+func (s *SeasonService) GetSeason(seasonId int) (models.Season, error) {
+	season, err := s.seasonRepo.GetSeasonById(seasonId)
+	if err == sql.ErrNoRows {
+		return models.Season{}, fmt.Errorf("season %d not found", seasonId)
+	}
+	if err != nil {
+		return models.Season{}, fmt.Errorf("failed to get season %d: %w", seasonId, err)
+	}
+	return season, nil
+}
+
+// GetSeasonsByStyle returns every season of a style, most recent first
+func (s *SeasonService) GetSeasonsByStyle(styleId int) ([]models.Season, error) {
+	seasons, err := s.seasonRepo.GetSeasonsByStyle(styleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seasons for style %d: %w", styleId, err)
+	}
+	return seasons, nil
+}
+
+// GetSeasonStandings returns the archived final standings of a closed season
+func (s *SeasonService) GetSeasonStandings(seasonId int) ([]models.SeasonStanding, error) {
+	season, err := s.GetSeason(seasonId)
+	if err != nil {
+		return nil, err
+	}
+	if season.ClosedDate == nil {
+		return nil, errors.New("season has not been closed yet")
+	}
+
+	standings, err := s.seasonRepo.GetSeasonStandings(seasonId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standings for season %d: %w", seasonId, err)
+	}
+	return standings, nil
+}
+
+// CloseSeason closes a season ahead of its end date. Only an admin can close one.
+func (s *SeasonService) CloseSeason(seasonId int, actorID int) (models.Season, error) {
+	if err := requireAdmin(actorID); err != nil {
+		return models.Season{}, err
+	}
+	return s.closeSeason(seasonId)
+}
+
+// closeSeason snapshots every current rating in the season's style into the archive and
+// soft-resets those ratings toward the style's configured mean
+func (s *SeasonService) closeSeason(seasonId int) (models.Season, error) {
+	season, err := s.GetSeason(seasonId)
+	if err != nil {
+		return models.Season{}, err
+	}
+	if season.ClosedDate != nil {
+		return models.Season{}, repositories.ErrSeasonAlreadyClosed
+	}
+
+	start, err := time.Parse(seasonDateLayout, season.StartDate)
+	if err != nil {
+		return models.Season{}, fmt.Errorf("failed to parse season start date: %w", err)
+	}
+	if time.Now().Before(start) {
+		return models.Season{}, errors.New("season has not started yet")
+	}
+
+	rating, err := s.athleteScoreService.ratingForStyle(season.StyleId)
+	if err != nil {
+		return models.Season{}, err
+	}
+
+	scores, err := s.scoreRepo.GetLatestScoresByStyle(season.StyleId)
+	if err != nil {
+		return models.Season{}, fmt.Errorf("failed to get current scores: %w", err)
+	}
+
+	standings, resets := seasonCloseRows(season.StyleId, scores, rating.season)
+	season.ResetMean = rating.season.SeasonResetMean
+	season.ResetWeight = rating.season.SeasonResetWeight
+
+	if err := s.seasonRepo.CloseSeason(season, standings, resets); err != nil {
+		if errors.Is(err, repositories.ErrSeasonAlreadyClosed) {
+			return models.Season{}, err
+		}
+		return models.Season{}, fmt.Errorf("failed to close season %d: %w", seasonId, err)
+	}
+
+	log.Printf("Closed season %d for style %d, archived %d standings", seasonId, season.StyleId, len(standings))
+
+	if err := s.overallRatingService.RecomputeAll(); err != nil {
+		log.Printf("Failed to recompute overall ratings after closing season %d: %v", seasonId, err)
+	}
+	return s.GetSeason(seasonId)
+}
+
+// CloseEndedSeasons closes every season whose end date has passed
+func (s *SeasonService) CloseEndedSeasons() error {
+	seasons, err := s.seasonRepo.GetEndedOpenSeasons()
+	if err != nil {
+		return fmt.Errorf("failed to get ended seasons: %w", err)
+	}
+
+	for _, season := range seasons {
+		_, err := s.closeSeason(season.SeasonId)
+		if err != nil && !errors.Is(err, repositories.ErrSeasonAlreadyClosed) {
+			return err
+		}
+	}
+	return nil
+}
+
+// seasonReset returns the settings that were applied when a closed season was reset
+func seasonReset(season models.Season) SeasonResetSettings {
+	return SeasonResetSettings{
+		SeasonResetMean:   season.ResetMean,
+		SeasonResetWeight: season.ResetWeight,
+	}
+}
+
+// seasonCloseRows ranks the current scores highest first, with tied scores sharing a rank,
+// and builds the soft-reset rating for each athlete
+func seasonCloseRows(styleId int, scores []models.AthleteScore, reset SeasonResetSettings) ([]models.SeasonStanding, []models.RatingChange) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	standings := make([]models.SeasonStanding, 0, len(scores))
+	resets := make([]models.RatingChange, 0, len(scores))
+	for i, score := range scores {
+		rank := i + 1
+		if i > 0 && score.Score == scores[i-1].Score {
+			rank = standings[i-1].Rank
+		}
+
+		standings = append(standings, models.SeasonStanding{
+			AthleteId:       score.AthleteId,
+			StyleId:         styleId,
+			Rank:            rank,
+			Score:           score.Score,
+			RatingDeviation: score.RatingDeviation,
+			Volatility:      score.Volatility,
+		})
+
+		change := models.RatingChange{
+			AthleteScore: score,
+			Reason:       models.ScoreReasonSeasonReset,
+		}
+		change.Score = reset.apply(score.Score)
+		resets = append(resets, change)
+	}
+	return standings, resets
+}
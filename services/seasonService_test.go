@@ -0,0 +1,84 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"ronin/models"
+)
+
+func TestSeasonResetSettingsApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reset SeasonResetSettings
+		score float64
+		want  float64
+	}{
+		{name: "default halves the distance above the mean", reset: DefaultSeasonResetSettings(), score: 500, want: 450},
+		{name: "default halves the distance below the mean", reset: DefaultSeasonResetSettings(), score: 360, want: 380},
+		{name: "zero weight keeps the score", reset: SeasonResetSettings{SeasonResetMean: 400, SeasonResetWeight: 0}, score: 480, want: 480},
+		{name: "full weight resets to the mean", reset: SeasonResetSettings{SeasonResetMean: 420, SeasonResetWeight: 1}, score: 480, want: 420},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reset.apply(tt.score); math.Abs(got-tt.want) > ratingTolerance {
+				t.Errorf("apply(%v) = %v, want %v", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeasonResetSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  float64
+		wantErr bool
+	}{
+		{name: "zero", weight: 0},
+		{name: "one", weight: 1},
+		{name: "negative", weight: -0.1, wantErr: true},
+		{name: "above one", weight: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SeasonResetSettings{SeasonResetMean: 400, SeasonResetWeight: tt.weight}.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeasonCloseRows(t *testing.T) {
+	scores := []models.AthleteScore{
+		{AthleteId: 1, Score: 410},
+		{AthleteId: 2, Score: 460},
+		{AthleteId: 3, Score: 410},
+		{AthleteId: 4, Score: 380},
+	}
+
+	standings, resets := seasonCloseRows(7, scores, DefaultSeasonResetSettings())
+
+	wantRanks := []struct{ athleteId, rank int }{{2, 1}, {1, 2}, {3, 2}, {4, 4}}
+	if len(standings) != len(wantRanks) {
+		t.Fatalf("got %d standings, want %d", len(standings), len(wantRanks))
+	}
+	for i, want := range wantRanks {
+		if standings[i].AthleteId != want.athleteId || standings[i].Rank != want.rank || standings[i].StyleId != 7 {
+			t.Errorf("standing %d = athlete %d rank %d style %d, want athlete %d rank %d style 7", i,
+				standings[i].AthleteId, standings[i].Rank, standings[i].StyleId, want.athleteId, want.rank)
+		}
+	}
+
+	wantScores := map[int]float64{1: 405, 2: 430, 3: 405, 4: 390}
+	for _, reset := range resets {
+		if reset.Reason != models.ScoreReasonSeasonReset {
+			t.Errorf("athlete %d reset has reason %q, want %q", reset.AthleteId, reset.Reason, models.ScoreReasonSeasonReset)
+		}
+		if math.Abs(reset.Score-wantScores[reset.AthleteId]) > ratingTolerance {
+			t.Errorf("athlete %d reset to %v, want %v", reset.AthleteId, reset.Score, wantScores[reset.AthleteId])
+		}
+	}
+}
@@ -76,6 +76,22 @@ func (s *styleService) RegisterMultipleStylesToAthlete(athleteID int, styles []i
 	return nil
 }
 
+// SetChallengeExpiry sets how many hours new challenges in a style stay open by default. Only an
+// admin can change it.
+func (s *styleService) SetChallengeExpiry(styleID int, hours int, actorID int) error {
+	if err := requireAdmin(actorID); err != nil {
+		return err
+	}
+	if hours <= 0 {
+		return fmt.Errorf("challenge expiry hours must be greater than zero")
+	}
+
+	if err := s.repo.SetChallengeExpiryHours(styleID, hours); err != nil {
+		return fmt.Errorf("failed to set challenge expiry for style %d: %w", styleID, err)
+	}
+	return nil
+}
+
 // GetCommonStyles retrieves styles common between two athletes
 func (s *styleService) GetCommonStyles(acceptorID, challengerID string) ([]models.Style, error) {
 	if acceptorID == "" || challengerID == "" {
@@ -94,6 +110,9 @@ func (s *styleService) validateStyle(style models.Style) error {
 	if style.StyleName == "" {
 		return fmt.Errorf("style name cannot be empty")
 	}
+	if style.ChallengeExpiryHours < 0 {
+		return fmt.Errorf("challenge expiry hours cannot be negative")
+	}
 	return nil
 }
 
@@ -193,3 +212,27 @@ func (h *StyleHandler) GetCommonStyles(w http.ResponseWriter, r *http.Request) {
 	}
 	json.NewEncoder(w).Encode(styles)
 }
+
+// SetChallengeExpiry handles PUT requests to set a style's default challenge expiry
+func (h *StyleHandler) SetChallengeExpiry(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	styleID, err := strconv.Atoi(mux.Vars(r)["style_id"])
+	if err != nil {
+		http.Error(w, "invalid style ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		ChallengeExpiryHours int `json:"challengeExpiryHours"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetChallengeExpiry(styleID, request.ChallengeExpiryHours, authenticatedAthleteId(r)); err != nil {
+		http.Error(w, err.Error(), authorizationErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+	json.NewEncoder(w).Encode(request)
+}
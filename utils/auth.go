@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAuthToken is returned for tokens that are malformed, forged or expired
+var ErrInvalidAuthToken = errors.New("invalid or expired auth token")
+
+type contextKey string
+
+const athleteIdKey contextKey = "athleteId"
+
+var (
+	authSecret      []byte
+	authTokenTTL    = 24 * time.Hour
+	adminAthleteIds = map[int]bool{}
+)
+
+// ConfigureAuth loads the token signing secret and lifetime from AUTH_SECRET and AUTH_TOKEN_TTL,
+// and the platform admins from ADMIN_ATHLETE_IDS. Without AUTH_SECRET a random secret is used,
+// so tokens stop working when the server restarts.
+func ConfigureAuth() {
+	secret := os.Getenv("AUTH_SECRET")
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			log.Fatalf("Error generating auth secret: %v", err)
+		}
+		secret = hex.EncodeToString(random)
+		log.Println("AUTH_SECRET is not set, using a random secret for this process")
+	}
+	authSecret = []byte(secret)
+	authTokenTTL = GetDurationEnv("AUTH_TOKEN_TTL", authTokenTTL)
+
+	for _, value := range strings.Split(os.Getenv("ADMIN_ATHLETE_IDS"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		athleteId, err := strconv.Atoi(value)
+		if err != nil || athleteId <= 0 {
+			log.Printf("Ignoring invalid athlete ID %q in ADMIN_ATHLETE_IDS", value)
+			continue
+		}
+		adminAthleteIds[athleteId] = true
+	}
+}
+
+// IsAdmin reports whether an athlete is listed in ADMIN_ATHLETE_IDS
+func IsAdmin(athleteId int) bool {
+	return adminAthleteIds[athleteId]
+}
+
+// IssueAuthToken returns a signed token that identifies an athlete until it expires
+func IssueAuthToken(athleteId int) (string, error) {
+	if len(authSecret) == 0 {
+		return "", errors.New("auth is not configured")
+	}
+
+	expires := time.Now().Add(authTokenTTL).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", athleteId, expires)))
+	return payload + "." + signAuthPayload(payload), nil
+}
+
+// ParseAuthToken verifies a token and returns the athlete it was issued to
+func ParseAuthToken(token string) (int, error) {
+	if len(authSecret) == 0 {
+		return 0, errors.New("auth is not configured")
+	}
+
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(signAuthPayload(payload))) {
+		return 0, ErrInvalidAuthToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, ErrInvalidAuthToken
+	}
+	idStr, expiresStr, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return 0, ErrInvalidAuthToken
+	}
+
+	athleteId, err := strconv.Atoi(idStr)
+	if err != nil || athleteId <= 0 {
+		return 0, ErrInvalidAuthToken
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return 0, ErrInvalidAuthToken
+	}
+	return athleteId, nil
+}
+
+// signAuthPayload returns the URL-safe HMAC-SHA256 signature of a token payload
+func signAuthPayload(payload string) string {
+	mac := hmac.New(sha256.New, authSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// WithAthleteId returns a context carrying the authenticated athlete's ID
+func WithAthleteId(ctx context.Context, athleteId int) context.Context {
+	return context.WithValue(ctx, athleteIdKey, athleteId)
+}
+
+// AthleteIdFromContext returns the authenticated athlete's ID, or 0 if the request is anonymous
+func AthleteIdFromContext(ctx context.Context) int {
+	athleteId, _ := ctx.Value(athleteIdKey).(int)
+	return athleteId
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthTokenRoundTrip(t *testing.T) {
+	authSecret = []byte("test-secret")
+	authTokenTTL = time.Hour
+
+	token, err := IssueAuthToken(42)
+	if err != nil {
+		t.Fatalf("IssueAuthToken() error = %v", err)
+	}
+
+	athleteId, err := ParseAuthToken(token)
+	if err != nil {
+		t.Fatalf("ParseAuthToken() error = %v", err)
+	}
+	if athleteId != 42 {
+		t.Errorf("ParseAuthToken() = %d, want 42", athleteId)
+	}
+}
+
+func TestParseAuthTokenRejects(t *testing.T) {
+	authSecret = []byte("test-secret")
+	authTokenTTL = time.Hour
+	valid, err := IssueAuthToken(42)
+	if err != nil {
+		t.Fatalf("IssueAuthToken() error = %v", err)
+	}
+
+	authTokenTTL = -time.Minute
+	expired, err := IssueAuthToken(42)
+	if err != nil {
+		t.Fatalf("IssueAuthToken() error = %v", err)
+	}
+	authTokenTTL = time.Hour
+
+	authSecret = []byte("other-secret")
+	forged, err := IssueAuthToken(42)
+	if err != nil {
+		t.Fatalf("IssueAuthToken() error = %v", err)
+	}
+	authSecret = []byte("test-secret")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: "NDI6OTk5OTk5OTk5OQ"},
+		{name: "tampered payload", token: "NDM6OTk5OTk5OTk5OQ." + valid[len(valid)-43:]},
+		{name: "signed with another secret", token: forged},
+		{name: "expired", token: expired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAuthToken(tt.token); err != ErrInvalidAuthToken {
+				t.Errorf("ParseAuthToken() error = %v, want %v", err, ErrInvalidAuthToken)
+			}
+		})
+	}
+}
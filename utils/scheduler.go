@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"log"
+	"time"
+)
+
+// RunEvery starts a background goroutine that runs job on a fixed interval for the life of the process.
+// Errors are logged and the job keeps running on the next tick.
+func RunEvery(name string, interval time.Duration, job func() error) {
+	log.Printf("Scheduling %s every %v", name, interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := job(); err != nil {
+				log.Printf("Scheduled job %s failed: %v", name, err)
+			}
+		}
+	}()
+}